@@ -6,34 +6,209 @@ package ConcaveHull
  */
 
 import (
+	"context"
+	"fmt"
 	"sort"
 	"github.com/furstenheim/go-convex-hull-2d"
 	"sync"
 	"github.com/furstenheim/SimpleRTree"
 	"math"
-	"github.com/paulmach/go.geo"
-	"github.com/paulmach/go.geo/reducers"
+	"github.com/USACE/concavehull/geomutil"
+	"time"
 )
 
 const DEFAULT_SEGLENGTH = 0.001
+// Bisection steps of the tolerance that satisfies Options.MaxVertices
+const capVerticesIterations = 30
 type concaver struct {
-	rtree * SimpleRTree.SimpleRTree
+	rtree SpatialIndex
 	seglength float64
-	options *Options
-	closestPointsMem []closestPoint
+	searchEpsilon float64
+	metric Metric
+	grid *gridIndex // nearest neighbour search with a metric, which the rtree doesn't support
 	searchItemsMem []searchItem
 	flatPointBuffer []float64
 	rtreePool *sync.Pool
+	deadline time.Time
+	compat CompatVersion
+	levels []float64 // extra simplification tolerances, see ComputeLevels
+	levelHulls []FlatPoints
+	ctx context.Context
+	partial bool // some edges were left straight because of time budget or cancellation
+	maxEdgeLength float64
+	maxDepth float64
+	maxSteps float64 // 2^MaxBisectionDepth, 0 for no limit
+	maxVertices int
+	stats SegmentizeStats
+	maxProbes int
+	warnings []Warning
+	exact bool
+	interpolated map[[2]float64]bool // vertices added by subdividing long edges or moved by smoothing
+	smoothWindow float64
+	smoothFixed FlatPoints
+	tracer Tracer
+	traceCtx context.Context
+	debug bool
+	inputs FlatPoints // sorted input points, only kept for Options.Debug and Options.KeepInputsInside
+	debugConvexHull FlatPoints
+	constraints *simplifyConstraints
+	checkpoint *checkpoint
+	resumedProbes int // probes of the runs resumed from the checkpoint, MaxProbes only limits those of this run
 }
+// Options are copied when a computation starts, so the same Options can be shared by concurrent computations and changed
+// between them. ConcaveHullPool, Cache, Metric, Projection, Instrumentation and Tracer are shared rather than copied, so they
+// must be safe for concurrent use. See NewOptions for a functional constructor
 type Options struct {
 	Seglength float64
+	SeglengthRelative float64 // seglength as a fraction of the diagonal of the bounding box of the points, e.g. 0.002. Ignored if Seglength is set
 	EstimatedRatioConcaveConvex int // estimated ratio of number of points between concave and convex hull. Will be used to allocate
 	ConcaveHullPool *sync.Pool
+	// Builds the index searched for the closest points instead of SimpleRTree, for example rtreegoindex.New
+	SpatialIndex SpatialIndexBuilder
+	// Maximum number of entries of a node of the spatial index, 0 for the default of SimpleRTree. Larger nodes make the index
+	// shallower, which can pay off on very skewed data such as thin coastal strips. Computations sharing a ConcaveHullPool
+	// should use the same value. Ignored by a Concaver, whose index is built by Prepare
+	RTreeNodeSize int
+	// Hulls are looked up in and stored to the cache, keyed by a hash of the sorted points and of the options.
+	// Computations with a TimeBudget or with functions in the options are not cached
+	Cache Cache
+	// Distance used for the length of the edges, to snap them to the closest points and to simplify the boundary, defaults
+	// to euclidean distance. Seglength, which is also the simplification tolerance, and SearchEpsilon are then expressed in this metric
+	Metric Metric
+	// Distance added to the search radius of each probe in segmentize. The radius is the distance to the closest of the
+	// points already found on both sides, so without a guard a point exactly as far as those is missed due to rounding.
+	// It is expressed in the units of the coordinates, so it should be far smaller than seglength. Defaults to 0
+	SearchEpsilon float64
+	// Like SearchEpsilon but as a fraction of the diagonal of the bounding box of the points. Ignored if SearchEpsilon is set
+	SearchEpsilonRelative float64
+	// Density hull mode. Points whose kernel density estimate is below DensityThreshold times the median density are ignored,
+	// so that isolated points do not drag the boundary outward. Typical values are between 0.05 and 0.5
+	DensityThreshold float64
+	// Bandwidth of the gaussian kernel of the density hull mode, in the units of the coordinates. Defaults to Silverman's rule of thumb
+	DensityBandwidth float64
+	// Drop points whose distance to their OutlierNeighbours-th nearest neighbour exceeds OutlierRejection times the median
+	// of that distance over all points, e.g. 5. Dropped points are reported in Hull.Dropped
+	OutlierRejection float64
+	// Number of neighbours used by OutlierRejection. Defaults to 4
+	OutlierNeighbours int
+	// Fraction of the points, e.g. 0.01, to discard before computing the hull, picking the most extreme ones as defined by TrimBy.
+	// Dropped points are reported in Hull.Dropped
+	TrimFraction float64
+	TrimBy TrimCriterion
+	// Number of outer convex layers to discard before computing the hull, see ConvexLayers. Dropped points are reported in Hull.Dropped
+	PeelLayers int
+	// Used by ComputeClusters, points closer than ClusterDistance belong to the same cluster
+	ClusterDistance float64
+	// Used by ComputeClusters, clusters with fewer points are dropped, or returned as a point or a segment if KeepSmallClusters is set
+	MinPoints int
+	KeepSmallClusters bool
+	// Used by ComputeClusters, number of cluster hulls computed concurrently. 0 and 1 compute them one after the other
+	Workers int
+	// Algorithm used to compute the hull, defaults to AlgorithmSnapHull
+	Algorithm Algorithm
+	// Used by AlgorithmEdgeLength, border triangles are removed while their border edge is longer than this length
+	EdgeLengthThreshold float64
+	// Used by AlgorithmEdgeLength if EdgeLengthThreshold is not set. Threshold as a fraction between the shortest and the longest
+	// edge of the triangulation, 0 gives the most concave hull and 1 the convex hull
+	EdgeLengthRatio float64
+	// With ComputeContext, split the hull wherever it is narrower than BridgeWidth and report the pieces in Hull.Parts, see SplitNarrow
+	BridgeWidth float64
+	// With ComputeContext, return the partially refined hull flagged as Partial instead of an error when the context is cancelled
+	AllowPartial bool
+	// With the functions that report errors, fail instead of returning a questionable hull: points without area are reported
+	// as ErrDegenerateInput, a seglength raised to the precision of the coordinates as ErrInvalidSeglength, and a spent
+	// TimeBudget or MaxProbes as ErrTimeout or ErrBudgetExceeded
+	Strict bool
+	// If set, edges of the convex hull are refined from longest to shortest and edges that are not reached before the budget expires are left straight
+	TimeBudget time.Duration
+	// File the refined edges of the convex hull are saved to as they are refined, at most every CheckpointInterval, and when
+	// the computation is interrupted by the context, TimeBudget or MaxProbes. A computation of the same points with the same
+	// options resumes from it and removes it once complete. Edges are then refined from longest to shortest as with TimeBudget.
+	// The file belongs to a single hull, so it must not be shared by concurrent computations or the hulls of ComputeClusters.
+	// Options with hooks such as Metric or SpatialIndex cannot be told apart, so they are not checkpointed and a WarningCheckpoint
+	// is returned. Not supported by the prepared Concaver and streaming. "" means no checkpoint
+	CheckpointPath string
+	CheckpointInterval time.Duration
+	// Positive factors applied to the coordinates before the computation, so that axes with different units weigh alike,
+	// e.g. ScaleX 1000 for x in km and y in m. Lengths in the options are in scaled units, the hull is returned in the original ones.
+	// Zero means 1
+	ScaleX, ScaleY float64
+	// Affine transform applied to the points before the computation, the hull is mapped back with InverseTransform, which
+	// defaults to the inverse of Transform. Lengths in the options are in transformed units
+	Transform *Affine
+	InverseTransform *Affine
+	// Projection to a planar coordinate system in which the hull is computed, before Transform if both are set.
+	// The hull is returned in the coordinates of the input, see CoordinateTransformer
+	Projection CoordinateTransformer
+	// Coordinates are rounded to multiples of GridSize before the computation, in the units it is done in, and points that
+	// become coincident are merged, so input vertices of the hull are on the grid. 0 means no rounding
+	GridSize float64
+	// Points outside the box are discarded before sorting and indexing, so a regional hull can be computed from a larger
+	// dataset without copying the points of the region first. Kept points are moved to the front of the input slice and
+	// discarded ones are not reported in Hull.Dropped. nil means no clipping
+	ClipInputBBox *BBox
+	// Remove repeated vertices, zero area spikes and vertices within SpikeTolerance of the line through their neighbours from the output
+	RemoveSpikes bool
+	SpikeTolerance float64
+	// Vertices of the output closer than WeldTolerance to the previous one are merged, also the last with the first, see
+	// WeldVertices. Applied last, so it takes precedence over MaxEdgeLength. 0 means no welding
+	WeldTolerance float64
+	// Minimum angle in radians between consecutive edges of the output, sharper vertices are merged, see EnforceMinAngle
+	MinAngle float64
+	// Arc length of the moving average that smooths the output, see Smooth. Vertices at PreserveVertices stay in place and
+	// moved vertices are reported as interpolated in Hull.Provenance. 0 means no smoothing
+	SmoothWindow float64
+	// Output edges longer than MaxEdgeLength are snapped again to the points with MaxEdgeLength as seglength, and what is still
+	// too long is subdivided with interpolated vertices, reported in Hull.Provenance
+	MaxEdgeLength float64
+	// If the simplified hull has more vertices, it is simplified again with the smallest larger tolerance that brings it within
+	// MaxVertices, for systems with hard limits on the size of geometries. Vertices added by MaxEdgeLength are not counted
+	MaxVertices int
+	// Vertices of the boundary at these positions are kept by the simplification, for example the endpoints of constraints.
+	// Only positions of input points can be on the boundary
+	PreserveVertices FlatPoints
+	// The simplification keeps at least MinVertices vertices, adding back those that deviate most from the simplified boundary
+	MinVertices int
+	// The simplification never cuts off an input point that the unsimplified boundary has inside or on it, so it only removes
+	// vertices where the boundary turns inward
+	KeepInputsInside bool
+	// Limit on how far the boundary digs inward: points farther than MaxDepth from the edge of the convex hull being refined
+	// are not snapped to, so that edge stays straight there. Measured perpendicular to the edge in the units of the coordinates.
+	// An alternative to tuning seglength, 0 means no limit
+	MaxDepth float64
+	// Limit on the levels of the bisection of each edge of the convex hull, so a tiny seglength on a huge edge cannot explode
+	// into millions of probes. Edges that would need more levels are probed uniformly at 2^MaxBisectionDepth steps, a coarser
+	// seglength for them only. 0 means no limit
+	MaxBisectionDepth int
+	// Budget of nearest neighbour searches, a runtime limit that doesn't depend on the machine. Edges of the convex hull are
+	// refined from longest to shortest and, once it is spent, the remaining ones are left straight and the hull is Partial,
+	// with a warning. It is checked between edges, so the last refined edge may overrun it. A computation resumed
+	// from CheckpointPath gets the whole budget again. 0 means no limit
+	MaxProbes int
+	// Compute the convex hull, the snapping and the simplification with exact predicates on rational numbers instead of
+	// float64 arithmetic. Much slower, meant for verification runs and coordinates of extreme magnitudes, such as differences
+	// above 1e154 whose squares overflow float64 in the convex hull and the nearest neighbour search. Metric and SearchEpsilon are ignored
+	ExactArithmetic bool
+	// Pins the behaviours listed with the CompatVersion constants to those of a version, currently only the clamp of seglength
+	// below the precision of the coordinates. Other changes of the output, such as in tie breaking or the simplifier, are not
+	// versioned. 0 follows CompatLatest
+	CompatVersion CompatVersion
+	// Run every stage on the calling goroutine, for environments with strict thread budgets and for deterministic profiling
+	SingleThreaded bool
+	// Skip the linear time check of ComputeFromSortedContext that the points are sorted
+	SkipSortCheck bool
+	// Receives the duration, input and output sizes and error of each computation, see ExpvarInstrumentation and PrometheusInstrumentation
+	Instrumentation Instrumentation
+	// Creates spans around sorting, index build, convex hull, segmentize and simplification, see Tracer
+	Tracer Tracer
+	// Check invariants after each phase of AlgorithmSnapHull: convex hull containing the points, monotone progress of the
+	// bisection in segmentize, closed and simple output ring with input vertices. Violations are reported as an *InvariantError.
+	// Slow, meant for tuning parameters on new datasets
+	Debug bool
 }
 
 type concaveHullPoolElement struct {
 	fpbMem []float64
-	closestPointsMem []closestPoint
 	searchItemsMem []searchItem
 	rtreePool *sync.Pool // This will be passed down to rtree
 	convexHullPool *sync.Pool // This will be passed down to convex hull
@@ -43,15 +218,96 @@ func Compute (points FlatPoints) (concaveHull FlatPoints) {
 	return ComputeWithOptions(points, nil)
 }
 func ComputeWithOptions (points FlatPoints, o *Options) (concaveHull FlatPoints) {
-	sort.Sort(lexSorter(points))
+	points = clipInput(points, o)
+	span := startSpan(nil, o, SPAN_SORT)
+	sort.Sort(LexSorter(points))
+	span.End()
 	return ComputeFromSortedWithOptions(points, o)
 }
 func ComputeFromSorted (points FlatPoints) (concaveHull FlatPoints) {
 	return ComputeFromSortedWithOptions(points, nil)
 }
 
-// Compute concave hull from sorted points. Points are expected to be sorted lexicographically by (x,y)
+// Compute concave hull from sorted points. Points are expected to be sorted lexicographically by (x,y), see LexSorter.
+// Order is not checked, ComputeFromSortedContext reports unsorted points as an *UnsortedError, or use CheckSorted
 func ComputeFromSortedWithOptions (points FlatPoints, o *Options) (concaveHull FlatPoints) {
+	o = o.snapshot()
+	start, inputPoints := time.Now(), points.Len()
+	defer func () { observe(o, start, inputPoints, concaveHull, nil) }()
+	if o != nil && o.Cache != nil {
+		if key, ok := fingerprint(points, o); ok {
+			if concaveHull, found := o.Cache.Get(key); found {
+				return concaveHull
+			}
+			concaveHull = computeFromSortedWithContext(nil, points, o).Points
+			o.Cache.Set(key, concaveHull)
+			return concaveHull
+		}
+	}
+	return computeFromSortedWithContext(nil, points, o).Points
+}
+
+func computeFromSortedWithContext (ctx context.Context, points FlatPoints, o *Options) (hull Hull) {
+	hull, _ = computeFromSortedWithLevels(ctx, points, o, nil)
+	return hull
+}
+
+// Compute the hull and, from the same densified boundary, one simplified hull per tolerance in levels
+func computeFromSortedWithLevels (ctx context.Context, points FlatPoints, o *Options, levels []float64) (hull Hull, levelHulls []FlatPoints) {
+	if o != nil && o.ClipInputBBox != nil {
+		// the box is in input coordinates, it must not be applied again after projecting or transforming the points
+		points = clipInput(points, o)
+		unclipped := *o
+		unclipped.ClipInputBBox = nil
+		o = &unclipped
+	}
+	if o != nil && o.Projection != nil {
+		inner := *o
+		inner.Projection = nil
+		return computeMapped(ctx, points, &inner, levels, o.Projection.Forward, o.Projection.Inverse)
+	}
+	if o != nil && o.Transform != nil {
+		return computeAffine(ctx, points, o, levels)
+	}
+	if scaleX, scaleY, ok := axisScale(o); ok {
+		unscaled := *o
+		unscaled.ScaleX, unscaled.ScaleY = 0, 0
+		hull, levelHulls = computeFromSortedWithLevels(ctx, scalePoints(points, scaleX, scaleY), &unscaled, levels)
+		hull.Points = unscalePoints(hull.Points, points, scaleX, scaleY)
+		for i := range(levelHulls) {
+			levelHulls[i] = unscalePoints(levelHulls[i], points, scaleX, scaleY)
+		}
+		return hull, levelHulls
+	}
+	start := time.Now()
+	var snapped []int
+	if o != nil && o.GridSize > 0 {
+		points, snapped = snapToGrid(points, o.GridSize)
+	}
+	if keep := prefilter(points, o); keep != nil {
+		inputKeep := keep
+		if snapped != nil {
+			// a merged point is dropped with all the points it stands for
+			inputKeep = make([]bool, len(snapped))
+			for i, k := range(snapped) {
+				inputKeep[i] = keep[k]
+			}
+		}
+		hull.Dropped = droppedIndices(inputKeep)
+		points = filterPoints(points, keep)
+	}
+	if o != nil && o.Algorithm == AlgorithmEdgeLength {
+		var c concaver
+		c.configure(ctx, points, o, start)
+		hull.Points = edgeLengthHull(points, o)
+		hull.Points = c.capVertices(hull.Points, hull.Points, simplify)
+		for _, tolerance := range(levels) {
+			levelHulls = append(levelHulls, c.finishRing(simplify(hull.Points, tolerance), o))
+		}
+		hull.Points = c.finishRing(hull.Points, o)
+		hull.Provenance = c.provenance(hull.Points)
+		return hull, levelHulls
+	}
 	// Create a copy so that convex hull and index can modify the array in different ways
 	var pointsCopy FlatPoints
 	var rtreeOptions SimpleRTree.Options
@@ -79,43 +335,89 @@ func ComputeFromSortedWithOptions (points FlatPoints, o *Options) (concaveHull F
 	}
 	pointsCopy = append(pointsCopy, points...)
 	rtreeOptions.RTreePool = rtreePool
+	if o != nil {
+		rtreeOptions.MAX_ENTRIES = o.RTreeNodeSize
+	}
 	rtreeOptions.UnsafeConcurrencyMode = true // we only access from one goroutine at a time
 	rtree := SimpleRTree.NewWithOptions(rtreeOptions)
+	var sortedCopy FlatPoints
+	if o != nil && (o.Debug || o.KeepInputsInside) {
+		sortedCopy = append(FlatPoints{}, points...)
+	}
+	// keyed by the points before the convex hull replaces them
+	checkpoint, checkpointErr := newCheckpoint(points, o)
 	var wg sync.WaitGroup
 	wg.Add(2)
+	// a panic in the goroutine is raised again on the calling one, where it can be recovered
+	var convexHullPanic interface{}
 	// Convex hull
-	go func () {
-		points = go_convex_hull_2d.NewFromSortedArrayWithOptions(points, go_convex_hull_2d.Options{Pool: convexHullPool}).(FlatPoints)
-		wg.Done()
-	}()
+	convexHull := func () {
+		defer func () {
+			convexHullPanic = recover()
+			wg.Done()
+		}()
+		span := startSpan(ctx, o, SPAN_CONVEX_HULL)
+		defer span.End()
+		if o != nil && o.ExactArithmetic {
+			points = exactConvexHull(points)
+		} else {
+			points = go_convex_hull_2d.NewFromSortedArrayWithOptions(points, go_convex_hull_2d.Options{Pool: convexHullPool}).(FlatPoints)
+		}
+	}
+	if o != nil && o.SingleThreaded {
+		convexHull()
+	} else {
+		go convexHull()
+	}
 
+	var index SpatialIndex = rtree
 	func () {
+		span := startSpan(ctx, o, SPAN_INDEX)
+		defer span.End()
+		defer wg.Done()
+		if o != nil && o.SpatialIndex != nil {
+			index = o.SpatialIndex(pointsCopy)
+			return
+		}
 		rtree.LoadSortedArray(SimpleRTree.FlatPoints(pointsCopy))
-		wg.Done()
 	}()
 	wg.Wait()
+	if convexHullPanic != nil {
+		panic(convexHullPanic)
+	}
 	var c concaver
-	c.seglength = DEFAULT_SEGLENGTH
-	if o != nil && o.Seglength != 0 {
-		c.seglength = o.Seglength
+	c.configure(ctx, points, o, start)
+	c.rtree = index
+	c.levels = levels
+	c.inputs = sortedCopy
+	c.checkpoint = checkpoint
+	if checkpointErr != nil {
+		c.warnings = append(c.warnings, Warning{Kind: WarningCheckpoint, Detail: checkpointErr.Error()})
+	}
+	if o != nil && o.Debug {
+		c.startDebug(points)
+	}
+	if c.metric != nil || c.exact {
+		span := startSpan(ctx, o, SPAN_INDEX)
+		c.grid = newGridIndex(pointsCopy)
+		span.End()
 	}
-	c.rtree = rtree
+	hullBufferSize := c.bufferSize(points, pointsCopy.Len(), o)
 	if isConcaveHullPoolElementsSet {
-		c.closestPointsMem = poolEl.closestPointsMem
 		c.searchItemsMem = poolEl.searchItemsMem
 		c.flatPointBuffer = poolEl.fpbMem[0:0]
-
-	} else {
-		c.closestPointsMem = make([]closestPoint, 0 , 2)
-		c.searchItemsMem = make([]searchItem, 0 , 2)
-		estimatedProportionConcave2Convex := 4
-		if c.options != nil && c.options.EstimatedRatioConcaveConvex != 0 {
-			estimatedProportionConcave2Convex = c.options.EstimatedRatioConcaveConvex
-		}
-		c.flatPointBuffer = make([]float64, 0, (2 * points.Len() * estimatedProportionConcave2Convex))
+	}
+	if cap(c.flatPointBuffer) < hullBufferSize {
+		c.flatPointBuffer = make([]float64, 0, hullBufferSize)
 	}
 
-	result := c.computeFromSorted(points)
+	result := c.finishRing(c.computeFromSorted(points), o)
+	if c.debug {
+		c.checkRing("simplify", result)
+	}
+	for i := range(c.levelHulls) {
+		c.levelHulls[i] = c.finishRing(c.levelHulls[i], o)
+	}
 	rtree.Destroy() // free resources
 	if o != nil && o.ConcaveHullPool != nil {
 		o.ConcaveHullPool.Put(
@@ -123,71 +425,474 @@ func ComputeFromSortedWithOptions (points FlatPoints, o *Options) (concaveHull F
 				rtreePool: rtreePool,
 				convexHullPool: convexHullPool,
 				searchItemsMem: c.searchItemsMem,
-				closestPointsMem: c.closestPointsMem,
 				fpbMem: c.flatPointBuffer,
 				pointsCopy: pointsCopy,
 			},
 		)
 	}
-	return result
+	hull.Points = result
+	hull.Provenance = c.provenance(hull.Points)
+	hull.Partial = c.partial
+	hull.Stats = c.stats
+	hull.Warnings = c.warnings
+	return hull, c.levelHulls
+}
+
+// Parameters of the computation that don't depend on pooled memory. The convex hull has the same bounding box as the points
+func (c * concaver) configure (ctx context.Context, convexHull FlatPoints, o *Options, start time.Time) {
+	c.seglength = DEFAULT_SEGLENGTH
+	if o != nil && o.Seglength != 0 {
+		c.seglength = o.Seglength
+	} else if o != nil && o.SeglengthRelative != 0 {
+		if diagonal := bboxDiagonal(convexHull); diagonal > 0 {
+			c.seglength = o.SeglengthRelative * diagonal
+		}
+	}
+	if o != nil && o.SearchEpsilon != 0 {
+		c.searchEpsilon = o.SearchEpsilon
+	} else if o != nil && o.SearchEpsilonRelative != 0 {
+		c.searchEpsilon = o.SearchEpsilonRelative * bboxDiagonal(convexHull)
+	}
+	if o != nil && o.TimeBudget != 0 {
+		c.deadline = start.Add(o.TimeBudget)
+	}
+	if o != nil && o.Metric != nil {
+		c.metric = o.Metric
+	}
+	if o != nil && o.MaxEdgeLength > 0 {
+		c.maxEdgeLength = o.MaxEdgeLength
+	}
+	if o != nil && o.SmoothWindow > 0 {
+		c.smoothWindow, c.smoothFixed = o.SmoothWindow, o.PreserveVertices
+	}
+	if o != nil && o.MaxDepth > 0 {
+		c.maxDepth = o.MaxDepth
+	}
+	if o != nil && o.MaxProbes > 0 {
+		c.maxProbes = o.MaxProbes
+	}
+	if o != nil && o.MaxBisectionDepth > 0 {
+		c.maxSteps = math.Ldexp(1, o.MaxBisectionDepth)
+	}
+	if o != nil && o.MaxVertices > 0 {
+		c.maxVertices = o.MaxVertices
+	}
+	if o != nil && (len(o.PreserveVertices) > 0 || o.MinVertices > 0 || o.KeepInputsInside) {
+		c.constraints = &simplifyConstraints{minVertices: o.MinVertices}
+		if len(o.PreserveVertices) > 0 {
+			c.constraints.preserve = map[[2]float64]bool{}
+			for i := 0; i < o.PreserveVertices.Len(); i++ {
+				x, y := o.PreserveVertices.Take(i)
+				c.constraints.preserve[[2]float64{x, y}] = true
+			}
+		}
+		c.constraints.keepInputsInside = o.KeepInputsInside
+	}
+	if o != nil && o.ExactArithmetic {
+		c.exact = true
+		c.metric = nil
+	}
+	// contexts that can never be cancelled don't need to be checked
+	if ctx != nil && ctx.Done() != nil {
+		c.ctx = ctx
+	}
+	if o != nil && o.Tracer != nil {
+		c.tracer = o.Tracer
+		c.traceCtx = ctx
+		if ctx == nil {
+			c.traceCtx = context.Background()
+		}
+	}
+	c.compat = CompatLatest
+	if o != nil && o.CompatVersion != 0 {
+		c.compat = o.CompatVersion
+	}
+	c.guardSeglength(convexHull)
+}
+
+// Raise a seglength below the precision of the coordinates, which would only probe the same positions again, with a warning,
+// and warn about a seglength so long that the hull is the convex hull.
+// The smallest useful seglength is the distance to the next float64 at the largest coordinate, and steps of the bisection
+// stay exact integers in float64 up to 2^53
+func (c * concaver) guardSeglength (convexHull FlatPoints) {
+	minSeglength, longest := 0., 0.
+	for i := 0; i < convexHull.Len(); i++ {
+		x1, y1, x2, y2 := convexHullEdge(convexHull, i)
+		minSeglength = math.Max(minSeglength, c.distance(x1, y1, math.Nextafter(x1, math.Inf(1)), y1))
+		minSeglength = math.Max(minSeglength, c.distance(x1, y1, x1, math.Nextafter(y1, math.Inf(1))))
+		longest = math.Max(longest, c.distance(x1, y1, x2, y2))
+	}
+	minSeglength = math.Max(minSeglength, longest / (1 << 53))
+	// the clamp never raises seglength to infinity, whatever the metric
+	if c.compat >= CompatV2 && c.seglength < minSeglength && !math.IsInf(minSeglength, 0) && !math.IsNaN(minSeglength) {
+		c.warnings = append(c.warnings, Warning{Kind: WarningSeglengthClamped, Detail: fmt.Sprintf("seglength %v raised to %v, the precision of the coordinates", c.seglength, minSeglength)})
+		c.seglength = minSeglength
+	}
+	if convexHull.Len() >= 3 && c.seglength >= longest {
+		c.warnings = append(c.warnings, Warning{Kind: WarningConvexHull, Detail: fmt.Sprintf("seglength %v is not shorter than the longest edge %v of the convex hull", c.seglength, longest)})
+	}
+}
+
+// Vertices the hull buffer is allocated for at most, append grows it past them for the hulls that need more
+const maxHullBufferVertices = 1 << 16
+
+// Initial capacity of the hull buffer. Each segment of the convex hull yields at most one closest point and each point is a
+// vertex at most once, but a hull usually has far fewer vertices: about the points near the boundary, estimated as the vertices
+// of the convex hull plus 4 sqrt(points). The buffer is sized from Options.EstimatedRatioConcaveConvex if it is set
+func (c * concaver) bufferSize (convexHull FlatPoints, inputPoints int, o *Options) int {
+	if o != nil && o.EstimatedRatioConcaveConvex != 0 {
+		return 2 * convexHull.Len() * o.EstimatedRatioConcaveConvex
+	}
+	totalSegments := 0.
+	for i := 0; i < convexHull.Len(); i++ {
+		totalSegments += c.steps(c.distance(convexHullEdge(convexHull, i)))
+	}
+	estimate := math.Min(totalSegments, float64(convexHull.Len()) + 4 * math.Sqrt(float64(inputPoints)))
+	return 2 * (int(math.Min(estimate, maxHullBufferVertices)) + 1)
+}
+
+func (c * concaver) startSpan (name string) Span {
+	if c.tracer == nil {
+		return noopSpan{}
+	}
+	_, span := c.tracer.Start(c.traceCtx, name)
+	return span
 }
 
 func (c * concaver) computeFromSorted (convexHull FlatPoints) (concaveHull FlatPoints) {
 	// degerated case
 	if (convexHull.Len() < 3) {
+		for range(c.levels) {
+			c.levelHulls = append(c.levelHulls, append(FlatPoints{}, convexHull...))
+		}
 		return convexHull
 	}
 
 	x0, y0 := convexHull.Take(0)
 	concaveHullBuffer := c.flatPointBuffer
 	concaveHullBuffer = append(concaveHullBuffer, x0, y0)
-	for i := 0; i<convexHull.Len(); i++ {
-		x1, y1 := convexHull.Take(i)
-		var x2, y2 float64
-		if i == convexHull.Len() -1 {
-			x2, y2 = convexHull.Take(0)
-		} else {
-			x2, y2 = convexHull.Take(i + 1)
-		}
-		sideSplit := c.segmentize(x1, y1, x2, y2)
-		for _, p := range(sideSplit) {
-			concaveHullBuffer = append(concaveHullBuffer, p.x, p.y)
+	span := c.startSpan(SPAN_SEGMENTIZE)
+	if !c.deadline.IsZero() || c.ctx != nil || c.maxProbes > 0 || c.checkpoint != nil {
+		concaveHullBuffer = c.segmentizeLongestFirst(convexHull, concaveHullBuffer)
+	} else {
+		for i := 0; i<convexHull.Len(); i++ {
+			x1, y1, x2, y2 := convexHullEdge(convexHull, i)
+			c.segmentizeEach(x1, y1, x2, y2, func (_ int, x, y float64) {
+				concaveHullBuffer = append(concaveHullBuffer, x, y)
+			})
 		}
 	}
+	span.End()
+	// keep the buffer if it grew, for the pool
+	c.flatPointBuffer = concaveHullBuffer[:0]
+	if c.debug {
+		c.checkRing("segmentize", concaveHullBuffer)
+	}
 	concaveHull = make([]float64, 0, len(concaveHullBuffer))
 	concaveHull = append(concaveHull, concaveHullBuffer...)
-	path := reducers.DouglasPeucker(geo.NewPathFromFlatXYData(concaveHull), c.seglength)
-	// reused allocated array
-	concaveHull = concaveHull[0:0]
-	reducedPoints := path.Points()
+	span = c.startSpan(SPAN_SIMPLIFY)
+	defer span.End()
+	if c.exact || c.constraints != nil || c.metric != nil {
+		simplifier := c.simplifier()
+		for _, tolerance := range(c.levels) {
+			c.levelHulls = append(c.levelHulls, simplifier(concaveHull, tolerance))
+		}
+		return c.capVertices(concaveHull, nil, simplifier)
+	}
+	for _, tolerance := range(c.levels) {
+		c.levelHulls = append(c.levelHulls, simplify(concaveHull, tolerance))
+	}
+	reduced := simplify(concaveHull, c.seglength)
+	if c.maxVertices > 0 && reduced.Len() - 1 > c.maxVertices {
+		return c.capVertices(concaveHull, nil, simplify)
+	}
+	return reduced
+}
+
+// Cleanup, edge length limit, smoothing and welding of a simplified ring
+func (c * concaver) finishRing (ring FlatPoints, o *Options) FlatPoints {
+	ring = c.smooth(c.limitEdgeLength(finishRing(ring, o)))
+	if o != nil && o.WeldTolerance > 0 {
+		ring = WeldVertices(ring, o.WeldTolerance)
+	}
+	return ring
+}
+
+// Snap edges longer than maxEdgeLength again with maxEdgeLength as seglength, then subdivide what is still too long.
+// Without an index edges are only subdivided
+func (c * concaver) limitEdgeLength (ring FlatPoints) FlatPoints {
+	if c.maxEdgeLength == 0 || ring.Len() < 2 {
+		return ring
+	}
+	seglength := c.seglength
+	c.seglength = math.Min(seglength, c.maxEdgeLength)
+	result := make(FlatPoints, 0, len(ring))
+	result = append(result, ring[0], ring[1])
+	for i := 0; i + 1 < ring.Len(); i++ {
+		x1, y1 := ring.Take(i)
+		x2, y2 := ring.Take(i + 1)
+		if c.distance(x1, y1, x2, y2) <= c.maxEdgeLength {
+			result = append(result, x2, y2)
+			continue
+		}
+		if c.rtree == nil {
+			result = c.subdivide(result, x2, y2)
+			continue
+		}
+		c.segmentizeEach(x1, y1, x2, y2, func (_ int, x, y float64) {
+			result = c.subdivide(result, x, y)
+		})
+	}
+	c.seglength = seglength
+	return result
+}
 
-	for _, p := range(reducedPoints) {
-		concaveHull = append(concaveHull, p.Lng(), p.Lat())
+// Append (x, y) to the ring after as many evenly spaced vertices as needed to keep edges within maxEdgeLength
+func (c * concaver) subdivide (ring FlatPoints, x, y float64) FlatPoints {
+	x0, y0 := ring[len(ring) - 2], ring[len(ring) - 1]
+	n := math.Ceil(c.distance(x0, y0, x, y) / c.maxEdgeLength)
+	if n > 1 && c.interpolated == nil {
+		c.interpolated = map[[2]float64]bool{}
 	}
-	return concaveHull
+	for k := 1.; k < n; k++ {
+		px, py := x0 + (x - x0) * k / n, y0 + (y - y0) * k / n
+		c.interpolated[[2]float64{px, py}] = true
+		ring = append(ring, px, py)
+	}
+	return append(ring, x, y)
+}
+
+func (c * concaver) distance (x1, y1, x2, y2 float64) float64 {
+	if c.metric != nil {
+		return c.metric.Distance(x1, y1, x2, y2)
+	}
+	if d := math.Sqrt((x1 - x2) * (x1 - x2) + (y1 - y2) * (y1 - y2)); !math.IsInf(d, 0) {
+		return d
+	}
+	// the squares overflow for differences above 1e154
+	return math.Hypot(x2 - x1, y2 - y1)
 }
 
-// Split side in small edges, for each edge find closest point. Remove duplicates
-func (c * concaver) segmentize (x1, y1, x2, y2 float64) (points []closestPoint) {
-	dist := math.Sqrt((x1 - x2) * (x1 - x2) + (y1 - y2) * (y1 - y2))
-	nSegments := math.Ceil(dist / c.seglength)
+// Provenance of the vertices of the hull, nil if they are all input points
+func (c * concaver) provenance (ring FlatPoints) []VertexKind {
+	if len(c.interpolated) == 0 {
+		return nil
+	}
+	kinds := make([]VertexKind, ring.Len())
+	for i := range(kinds) {
+		x, y := ring.Take(i)
+		if c.interpolated[[2]float64{x, y}] {
+			kinds[i] = VertexInterpolated
+		}
+	}
+	return kinds
+}
+
+// Simplify the ring again with the smallest tolerance above seglength that leaves at most maxVertices vertices.
+// simplified is the ring simplified with seglength, nil to compute it
+func (c * concaver) capVertices (ring, simplified FlatPoints, simplifier func (FlatPoints, float64) FlatPoints) FlatPoints {
+	if simplified == nil {
+		simplified = simplifier(ring, c.seglength)
+	}
+	if c.maxVertices == 0 || openRing(simplified).Len() <= c.maxVertices {
+		return simplified
+	}
+	// double the tolerance until the ring is small enough, then bisect between the last two tolerances. The constraints of
+	// the simplification may keep it above maxVertices
+	low, high := c.seglength, 2 * c.seglength
+	simplified = simplifier(ring, high)
+	for i := 0; i < capVerticesIterations && openRing(simplified).Len() > c.maxVertices; i++ {
+		low, high = high, 2 * high
+		simplified = simplifier(ring, high)
+	}
+	if openRing(simplified).Len() > c.maxVertices {
+		return simplified
+	}
+	for i := 0; i < capVerticesIterations; i++ {
+		tolerance := (low + high) / 2
+		candidate := simplifier(ring, tolerance)
+		if openRing(candidate).Len() <= c.maxVertices {
+			high, simplified = tolerance, candidate
+		} else {
+			low = tolerance
+		}
+	}
+	return simplified
+}
+
+// Simplification of the boundary: Douglas Peucker, with exact predicates in exact mode, or honouring the constraints of the
+// options. With a Metric the tolerance, like seglength, is in its units, so distances to the chords are measured with it
+func (c * concaver) simplifier () func (FlatPoints, float64) FlatPoints {
+	if c.constraints == nil && c.metric == nil {
+		if c.exact {
+			return exactSimplify
+		}
+		return simplify
+	}
+	var constraints simplifyConstraints
+	if c.constraints != nil {
+		constraints = *c.constraints
+	}
+	constraints.metric = c.metric
+	if constraints.keepInputsInside {
+		constraints.inputs = c.inputs
+	}
+	return func (ring FlatPoints, tolerance float64) FlatPoints {
+		return simplifyConstrained(ring, tolerance, constraints)
+	}
+}
+
+// Douglas Peucker simplification into a new array
+func simplify (points FlatPoints, tolerance float64) FlatPoints {
+	return geomutil.DouglasPeucker(points, tolerance)
+}
+
+// Refine the edges of the convex hull from longest to shortest. Once the deadline is reached or the context is cancelled
+// the remaining edges are left straight, which yields the best hull we can get in the time available
+func (c * concaver) segmentizeLongestFirst (convexHull FlatPoints, concaveHullBuffer []float64) []float64 {
+	n := convexHull.Len()
+	order := make([]int, n)
+	lengths := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x1, y1, x2, y2 := convexHullEdge(convexHull, i)
+		order[i] = i
+		if c.metric != nil {
+			lengths[i] = c.metric.Distance(x1, y1, x2, y2)
+		} else {
+			lengths[i] = squaredDistance(x1, y1, x2, y2)
+		}
+	}
+	sort.Slice(order, func (i, j int) bool {
+		return lengths[order[i]] > lengths[order[j]]
+	})
+	// sides are refined out of order, so each one is kept until they are assembled in order
+	sides := make([][]float64, n)
+	if c.checkpoint != nil {
+		if saved, stats, err := c.checkpoint.load(n); err != nil {
+			c.warnings = append(c.warnings, Warning{Kind: WarningCheckpoint, Detail: fmt.Sprintf("not resumed: %v", err)})
+		} else if saved != nil {
+			sides, c.stats, c.resumedProbes = saved, stats, stats.Probes
+		}
+	}
+	for _, i := range(order) {
+		if sides[i] != nil {
+			continue
+		}
+		if c.expired() {
+			c.partial = true
+			break
+		}
+		x1, y1, x2, y2 := convexHullEdge(convexHull, i)
+		var side []float64
+		c.segmentizeEach(x1, y1, x2, y2, func (_ int, x, y float64) {
+			side = append(side, x, y)
+		})
+		sides[i] = side
+		c.saveCheckpoint(sides, false)
+	}
+	if c.partial {
+		c.saveCheckpoint(sides, true)
+	} else if c.checkpoint != nil {
+		if err := c.checkpoint.remove(); err != nil {
+			c.warnings = append(c.warnings, Warning{Kind: WarningCheckpoint, Detail: fmt.Sprintf("not removed: %v", err)})
+		}
+	}
+	for i, side := range(sides) {
+		if side == nil {
+			_, _, x2, y2 := convexHullEdge(convexHull, i)
+			concaveHullBuffer = append(concaveHullBuffer, x2, y2)
+			continue
+		}
+		concaveHullBuffer = append(concaveHullBuffer, side...)
+	}
+	return concaveHullBuffer
+}
+
+// Save the refined sides to the checkpoint, which is given up with a warning if it cannot be written
+func (c * concaver) saveCheckpoint (sides [][]float64, force bool) {
+	if c.checkpoint == nil {
+		return
+	}
+	if err := c.checkpoint.save(sides, c.stats, force); err != nil {
+		c.warnings = append(c.warnings, Warning{Kind: WarningCheckpoint, Detail: fmt.Sprintf("not saved: %v", err)})
+		c.checkpoint = nil
+	}
+}
+
+func (c * concaver) expired () bool {
+	if c.ctx != nil && c.ctx.Err() != nil {
+		return true
+	}
+	if probes := c.stats.Probes - c.resumedProbes; c.maxProbes > 0 && probes >= c.maxProbes {
+		c.warnings = append(c.warnings, Warning{Kind: WarningProbeLimit, Detail: fmt.Sprintf("%d probes reached, the remaining edges of the convex hull are left straight", probes)})
+		return true
+	}
+	if !c.deadline.IsZero() && time.Now().After(c.deadline) {
+		c.warnings = append(c.warnings, Warning{Kind: WarningTimeBudget, Detail: "the remaining edges of the convex hull are left straight"})
+		return true
+	}
+	return false
+}
+
+// Endpoints of the i-th edge of the convex hull, the last edge closes the ring
+func convexHullEdge (convexHull FlatPoints, i int) (x1, y1, x2, y2 float64) {
+	x1, y1 = convexHull.Take(i)
+	if i == convexHull.Len() - 1 {
+		x2, y2 = convexHull.Take(0)
+	} else {
+		x2, y2 = convexHull.Take(i + 1)
+	}
+	return
+}
+
+// Number of steps of the bisection of an edge of length dist, capped by MaxBisectionDepth
+func (c * concaver) steps (dist float64) float64 {
+	steps := math.Ceil(dist / c.seglength)
+	if c.maxSteps > 0 && steps > c.maxSteps {
+		return c.maxSteps
+	}
+	return steps
+}
+
+// Call emit with the closest points of side in order, after the start of side and up to its end. The bisection visits the
+// steps in order, so the points come out sorted and only the stack, which is logarithmic in the number of steps, is kept in
+// memory however long side is and however small seglength is
+func (c * concaver) segmentizeEach (x1, y1, x2, y2 float64, emit func (index int, x, y float64)) {
+	nSegments := c.steps(c.distance(x1, y1, x2, y2))
 	factor := 1 / nSegments
 	vX := factor * (x2 - x1)
 	vY := factor * (y2 - y1)
 
-	closestPoints := c.closestPointsMem[0: 0]
-	closestPoints = append(closestPoints, closestPoint{index: 0, x: x1, y: y1})
-	closestPoints = append(closestPoints, closestPoint{index: int(nSegments), x: x2, y: y2})
-
 	if (nSegments < 2) {
-		return closestPoints[1:]
+		emit(int(nSegments), x2, y2)
+		return
 	}
 
+	var depthFactor float64
+	if c.maxDepth > 0 {
+		depthFactor = 1 / math.Hypot(x2 - x1, y2 - y1)
+	}
+	lastIndex := 0
+	// the stack never outgrows its capacity, so the same array is used by every call
+	if cap(c.searchItemsMem) < searchStackSize {
+		c.searchItemsMem = make([]searchItem, 0, searchStackSize)
+	}
 	stack := c.searchItemsMem[0: 0]
-	stack = append(stack, searchItem{left: 0, right: int(nSegments), lastLeftIndex: 0, lastRightIndex: 1})
+	stack = append(stack, searchItem{left: 0, right: int(nSegments), lx: x1, ly: y1, rx: x2, ry: y2})
 	for len(stack) > 0 {
+		if len(stack) > c.stats.MaxStack {
+			c.stats.MaxStack = len(stack)
+		}
 		var item searchItem
 		item, stack = stack[len(stack)-1], stack[:len(stack)-1]
+		if item.emit {
+			if c.debug {
+				c.checkStep(lastIndex, item.left)
+			}
+			lastIndex = item.left
+			emit(item.left, item.lx, item.ly)
+			continue
+		}
 		if item.right - item.left <= 1 {
 			continue
 		}
@@ -195,48 +900,69 @@ func (c * concaver) segmentize (x1, y1, x2, y2 float64) (points []closestPoint)
 		fIndex := float64(index)
 		currentX := x1 + vX * fIndex
 		currentY := y1 + vY * fIndex
-		lx := closestPoints[item.lastLeftIndex].x
-		ly := closestPoints[item.lastLeftIndex].y
-		rx := closestPoints[item.lastRightIndex].x
-		ry := closestPoints[item.lastRightIndex].y
-
-		d1 := (currentX - lx) * (currentX - lx) + (currentY - ly) * (currentY - ly)
-		d2 := (currentX - rx) * (currentX - rx) + (currentY - ry) * (currentY - ry)
-		x, y, _, found := c.rtree.FindNearestPointWithin(currentX, currentY, math.Min(d1, d2))
+		lx, ly, rx, ry := item.lx, item.ly, item.rx, item.ry
+		c.stats.Probes++
+
+		var x, y float64
+		var found bool
+		if c.exact {
+			x, y, found = c.grid.nearestExact(currentX, currentY, lx, ly, rx, ry)
+		} else if c.metric != nil {
+			d1 := c.metric.Distance(currentX, currentY, lx, ly)
+			d2 := c.metric.Distance(currentX, currentY, rx, ry)
+			x, y, found = c.grid.nearestWithin(currentX, currentY, math.Min(d1, d2) + c.searchEpsilon, c.metric)
+		} else {
+			searchRadius := squaredDistance(currentX, currentY, lx, ly)
+			if d2 := squaredDistance(currentX, currentY, rx, ry); d2 < searchRadius {
+				searchRadius = d2
+			}
+			if c.searchEpsilon != 0 {
+				r := math.Sqrt(searchRadius) + c.searchEpsilon
+				searchRadius = r * r
+			}
+			x, y, _, found = c.rtree.FindNearestPointWithin(currentX, currentY, searchRadius)
+		}
 		if !found {
 			continue
 		}
+		if depthFactor != 0 && math.Abs(orientation(x1, y1, x2, y2, x, y)) * depthFactor > c.maxDepth {
+			// only the candidate is rejected, shallower points may still be closest to the steps of either half
+			stack = append(stack, searchItem{left: index, right: item.right, lx: lx, ly: ly, rx: rx, ry: ry})
+			stack = append(stack, searchItem{left: item.left, right: index, lx: lx, ly: ly, rx: rx, ry: ry})
+			continue
+		}
 		isNewLeft := x != lx || y != ly
 		isNewRight := x != rx || y != ry
 
-		// we don't know the point
+		// the stack is last in first out: the right half is pushed first so that the left half and the point come out before it
 		if isNewLeft && isNewRight {
-			newResultIndex := len(closestPoints)
-			closestPoints = append(closestPoints, closestPoint{index: index, x: x, y: y})
-			stack = append(stack, searchItem{left: item.left, right: index, lastLeftIndex: item.lastLeftIndex, lastRightIndex: newResultIndex})
-			// alloc
-			stack = append(stack, searchItem{left: index, right: item.right, lastLeftIndex: newResultIndex, lastRightIndex: item.lastRightIndex})
+			// we don't know the point
+			stack = append(stack, searchItem{left: index, right: item.right, lx: x, ly: y, rx: rx, ry: ry})
+			stack = append(stack, searchItem{emit: true, left: index, lx: x, ly: y})
+			stack = append(stack, searchItem{left: item.left, right: index, lx: lx, ly: ly, rx: x, ry: y})
 		} else if (isNewLeft) {
-			stack = append(stack, searchItem{left: item.left, right: index, lastLeftIndex: item.lastLeftIndex, lastRightIndex: item.lastRightIndex})
+			stack = append(stack, searchItem{left: item.left, right: index, lx: lx, ly: ly, rx: rx, ry: ry})
 		} else {
 			// don't add point to closest points, but we need to keep looking on the right side
-			stack = append(stack, searchItem{left: index, right: item.right, lastLeftIndex: item.lastLeftIndex, lastRightIndex: item.lastRightIndex})
+			stack = append(stack, searchItem{left: index, right: item.right, lx: lx, ly: ly, rx: rx, ry: ry})
 		}
 	}
-	closestPointSorter(closestPoints).cpSort()
-	c.searchItemsMem = stack
-	c.closestPointsMem = closestPoints
-	return closestPoints[1:]
+	if c.debug {
+		c.checkStep(lastIndex, int(nSegments))
+	}
+	emit(int(nSegments), x2, y2)
 }
 
-type closestPoint struct {
-	index int
-	x, y float64
-}
+// Bound on the items of the bisection stack: ranges are halved at each level, at most 64 levels for an int number of steps,
+// and each level leaves at most a range and a point on the stack
+const searchStackSize = 2 * 64 + 2
 
+// Steps left to right of the bisection, between the closest points found at left and right, or, if emit is set, the point
+// found at step left
 type searchItem struct {
-	left, right, lastLeftIndex, lastRightIndex int
-
+	left, right int
+	lx, ly, rx, ry float64
+	emit bool
 }
 
 
@@ -13,7 +13,13 @@ import (
 	"strconv"
 	"path/filepath"
 	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
 	"sync"
+	"sort"
+	"github.com/furstenheim/go-convex-hull-2d"
+	"time"
+	"context"
+	"errors"
 )
 
 func TestCompute_concaveHullInAntiClockwiseOrder(t *testing.T) {
@@ -54,6 +60,19 @@ func TestCompute_simpleConcaveHull(t *testing.T) {
 	compareConcaveHulls(t, result, points2)
 }
 
+type closestPoint struct {
+	index int
+	x, y float64
+}
+
+// Closest points of side collected from segmentizeEach
+func (c * concaver) segmentize (x1, y1, x2, y2 float64) (points []closestPoint) {
+	c.segmentizeEach(x1, y1, x2, y2, func (index int, x, y float64) {
+		points = append(points, closestPoint{index: index, x: x, y: y})
+	})
+	return points
+}
+
 func TestConcaveHull_segmentize (t *testing.T) {
 	const size = 200
 	points := make([]float64, size * 2)
@@ -64,7 +83,6 @@ func TestConcaveHull_segmentize (t *testing.T) {
 	r := SimpleRTree.New().Load(SimpleRTree.FlatPoints(fp))
 	c := new(concaver)
 	c.rtree = r
-	c.closestPointsMem = make([]closestPoint, 0 , 2)
 	c.searchItemsMem = make([]searchItem, 0 , 2)
 
 	c.seglength = DEFAULT_SEGLENGTH
@@ -303,7 +321,7 @@ func (c * concaver) segmentizeLinear (x1, y1, x2, y2 float64) (points []float64)
 	latestX := x1
 	latestY := y1
 	for i := 0; i < int(nSegments); i++ {
-		x, y, _, _ := c.rtree.FindNearestPoint(currentX, currentY)
+		x, y, _, _ := c.rtree.FindNearestPointWithin(currentX, currentY, math.Inf(1))
 		if x != latestX || y != latestY {
 			flatPoints = append(flatPoints, x, y)
 			latestX = x
@@ -314,3 +332,216 @@ func (c * concaver) segmentizeLinear (x1, y1, x2, y2 float64) (points []float64)
 	}
 	return flatPoints
 }
+
+func TestComputeWithOptions_seglengthRelative (t *testing.T) {
+	const size = 300
+	points := make([]float64, size * 2)
+	scaled := make([]float64, size * 2)
+	for i := 0; i < 2 * size; i++ {
+		points[i] = rand.Float64()
+		scaled[i] = points[i] * 1000
+	}
+	result := ComputeWithOptions(FlatPoints(points), &Options{SeglengthRelative: 0.05})
+	resultScaled := ComputeWithOptions(FlatPoints(scaled), &Options{SeglengthRelative: 0.05})
+	assert.Equal(t, len(result), len(resultScaled))
+	for i := range(result) {
+		assert.InDelta(t, result[i] * 1000, resultScaled[i], 1e-6)
+	}
+}
+
+func TestBBoxDiagonal_largeMagnitude (t *testing.T) {
+	// the squares of the sides overflow float64
+	square := FlatPoints{0, 0, 1e160, 0, 1e160, 1e160, 0, 1e160}
+	assert.InDelta(t, math.Sqrt2 * 1e160, bboxDiagonal(square), 1e148)
+}
+
+func TestComputeWithOptions_timeBudget (t *testing.T) {
+	points := FlatPoints{1./3., 0.5, 0.0, 0.0, 1.0, 0.0, 0.0, 1.0, 1.0, 1.0}
+	expected := Compute(append(FlatPoints{}, points...))
+	// generous budget gives the same result as an unbounded computation
+	result := ComputeWithOptions(append(FlatPoints{}, points...), &Options{TimeBudget: time.Minute})
+	compareConcaveHulls(t, result, expected)
+	// expired budget leaves every edge straight, that is, the convex hull
+	result = ComputeWithOptions(append(FlatPoints{}, points...), &Options{TimeBudget: time.Nanosecond})
+	compareConcaveHulls(t, result, FlatPoints{0.0, 0.0, 1.0, 0.0, 1.0, 1.0, 0.0, 1.0, 0., 0.})
+}
+
+func TestCompute_validHulls (t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for _, points := range([][]float64{
+		hulltest.Random(r, 500),
+		hulltest.Clustered(r, 500, 3, 0.05),
+		hulltest.Ring(r, 500, 0.3, 0.5),
+		hulltest.Grid(r, 500, 10),
+	}) {
+		input := append([]float64{}, points...)
+		hull := ComputeWithOptions(FlatPoints(points), &Options{Seglength: 0.01})
+		hulltest.AssertValid(t, input, hull)
+	}
+}
+
+func TestComputeWithOptions_searchEpsilon (t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	points := hulltest.Grid(r, 500, 20)
+	input := append([]float64{}, points...)
+	hull := ComputeWithOptions(FlatPoints(points), &Options{Seglength: 0.01, SearchEpsilonRelative: 1e-9})
+	hulltest.AssertValid(t, input, hull)
+	assert.Equal(t, []float64(ComputeWithOptions(FlatPoints(input), &Options{Seglength: 0.01})), []float64(hull))
+}
+
+func TestComputeWithOptions_axisScale (t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	points := hulltest.Ring(r, 1000, 0.3, 0.5)
+	expected := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.02})
+	// stretch x by a power of two so that scaling back is exact
+	stretched := append([]float64{}, points...)
+	for i := 0; i < len(stretched); i += 2 {
+		stretched[i] *= 1024
+	}
+	input := append([]float64{}, stretched...)
+	result := ComputeWithOptions(FlatPoints(stretched), &Options{Seglength: 0.02, ScaleX: 1. / 1024})
+	hulltest.AssertValid(t, input, result)
+	assert.Equal(t, len(expected), len(result))
+	for i := 0; i < len(result); i += 2 {
+		assert.Equal(t, expected[i] * 1024, result[i])
+		assert.Equal(t, expected[i + 1], result[i + 1])
+	}
+}
+
+func TestComputeWithOptions_maxDepth (t *testing.T) {
+	r := rand.New(rand.NewSource(16))
+	// square with a wide notch coming down from the top edge to y = 0.75
+	points := []float64{0, 0, 1, 0, 1, 1, 0, 1}
+	for len(points) < 4000 {
+		x, y := r.Float64(), r.Float64()
+		if x > 0.2 && x < 0.8 && y > 0.75 {
+			continue
+		}
+		points = append(points, x, y)
+	}
+	inNotch := func (hull FlatPoints) (deepest float64) {
+		deepest = 1
+		for i := 0; i < hull.Len(); i++ {
+			if x, y := hull.Take(i); x > 0.25 && x < 0.75 && y > 0.5 {
+				deepest = math.Min(deepest, y)
+			}
+		}
+		return deepest
+	}
+	hull := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.02})
+	assert.True(t, inNotch(hull) < 0.8)
+	hull = ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.02, MaxDepth: 0.1})
+	assert.True(t, inNotch(hull) >= 0.9)
+	hulltest.AssertValid(t, points, hull)
+}
+
+func TestComputeWithOptions_maxDepthMixed (t *testing.T) {
+	// below the top edge, shoulders 0.2 deep on both sides of a notch 0.8 deep, which is closest to the middle of the edge
+	var points []float64
+	for i := 0; i <= 40; i++ {
+		x := float64(i) / 10
+		top := 12
+		if x <= 0.2 || x >= 3.8 {
+			top = 20
+		} else if x <= 1 || x >= 3 {
+			top = 18
+		}
+		for j := 0; j <= top; j++ {
+			points = append(points, x, float64(j) / 10)
+		}
+	}
+	hull := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.1, MaxDepth: 0.5})
+	hulltest.AssertValid(t, points, hull)
+	shoulders := 0
+	for i := 0; i < hull.Len(); i++ {
+		x, y := hull.Take(i)
+		assert.False(t, x > 0 && x < 4 && y < 1.5 && y > 0, "vertex in the notch: %v %v", x, y)
+		if y == 1.8 {
+			shoulders++
+		}
+	}
+	assert.Equal(t, 2, shoulders)
+}
+
+func TestComputeWithOptions_singleThreaded (t *testing.T) {
+	r := rand.New(rand.NewSource(22))
+	points := hulltest.Clustered(r, 1000, 4, 0.05)
+	expected := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.01})
+	result := ComputeWithOptions(FlatPoints(points), &Options{Seglength: 0.01, SingleThreaded: true})
+	assert.Equal(t, expected, result)
+}
+
+func TestComputeWithOptions_maxVertices (t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	points := hulltest.Ring(r, 2000, 0.3, 0.5)
+	uncapped := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.005})
+	assert.True(t, uncapped.Len() - 1 > 20)
+	hull := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.005, MaxVertices: 20})
+	assert.True(t, hull.Len() - 1 <= 20)
+	assert.True(t, hull.Len() - 1 >= 10)
+	hulltest.AssertValid(t, points, hull)
+	// a cap that isn't reached changes nothing
+	hull = ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.005, MaxVertices: uncapped.Len()})
+	assert.Equal(t, uncapped, hull)
+}
+
+func TestConcaver_searchStackSize (t *testing.T) {
+	r := rand.New(rand.NewSource(24))
+	points := hulltest.Random(r, 5000)
+	rtree := SimpleRTree.NewWithOptions(SimpleRTree.Options{UnsafeConcurrencyMode: true}).Load(SimpleRTree.FlatPoints(points))
+	convexHull := FlatPoints{0, 0, 1, 0, 1, 1, 0, 1}
+	for _, seglength := range([]float64{0.1, 0.001, 0.00001}) {
+		c := concaver{rtree: rtree, seglength: seglength}
+		for i := 0; i < convexHull.Len(); i++ {
+			x1, y1, x2, y2 := convexHullEdge(convexHull, i)
+			c.segmentizeEach(x1, y1, x2, y2, func (int, float64, float64) {})
+			assert.Equal(t, searchStackSize, cap(c.searchItemsMem))
+		}
+		assert.True(t, c.stats.MaxStack > 0 && c.stats.MaxStack <= searchStackSize)
+	}
+}
+
+func TestComputeWithOptions_rtreeNodeSize (t *testing.T) {
+	r := rand.New(rand.NewSource(25))
+	points := hulltest.Clustered(r, 2000, 3, 0.05)
+	expected := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.01})
+	for _, size := range([]int{4, 64}) {
+		hull := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), NewOptions(WithSeglength(0.01), WithRTreeNodeSize(size)))
+		assert.Equal(t, expected, hull)
+	}
+	_, err := ComputeContext(context.Background(), FlatPoints(points), &Options{RTreeNodeSize: 1})
+	assert.True(t, errors.Is(err, ErrInvalidOptions))
+}
+
+func TestConcaver_maxBisectionDepth (t *testing.T) {
+	r := rand.New(rand.NewSource(26))
+	points := hulltest.Random(r, 3000)
+	rtree := SimpleRTree.NewWithOptions(SimpleRTree.Options{UnsafeConcurrencyMode: true}).Load(SimpleRTree.FlatPoints(points))
+	capped := concaver{rtree: rtree, seglength: 1e-9, maxSteps: 16}
+	uniform := concaver{rtree: rtree, seglength: 1. / 16}
+	assert.Equal(t, append([]closestPoint{}, uniform.segmentize(0, 0, 1, 0)...), capped.segmentize(0, 0, 1, 0))
+	// edges short enough are not affected
+	capped.seglength, uniform.seglength = 0.1, 0.1
+	assert.Equal(t, append([]closestPoint{}, uniform.segmentize(0, 0, 1, 1)...), capped.segmentize(0, 0, 1, 1))
+	_, err := ComputeContext(context.Background(), FlatPoints(points), &Options{MaxBisectionDepth: -1})
+	assert.True(t, errors.Is(err, ErrInvalidOptions))
+}
+
+// Memory allocated by the segmentation and simplification of a hull without a pool, where the hull buffer is allocated by
+// every computation. A short seglength has far more segments than the hull has vertices
+func Benchmark_hullBuffer (b *testing.B) {
+	points := hulltest.Random(rand.New(rand.NewSource(27)), 100000)
+	sort.Sort(LexSorter(points))
+	rtree := SimpleRTree.NewWithOptions(SimpleRTree.Options{UnsafeConcurrencyMode: true}).Load(SimpleRTree.FlatPoints(append(FlatPoints{}, points...)))
+	convexHull := go_convex_hull_2d.NewFromSortedArray(append(FlatPoints{}, points...)).(FlatPoints)
+	for _, seglength := range([]float64{0.001, 0.00001}) {
+		b.Run(fmt.Sprint(seglength), func (b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				c := concaver{rtree: rtree, seglength: seglength}
+				c.flatPointBuffer = make([]float64, 0, c.bufferSize(convexHull, len(points) / 2, nil))
+				_ = c.computeFromSorted(convexHull)
+			}
+		})
+	}
+}
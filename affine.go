@@ -0,0 +1,120 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+// Affine transform x' = A x + B y + C, y' = D x + E y + F
+type Affine struct {
+	A, B, C float64
+	D, E, F float64
+}
+
+func Translation (dx, dy float64) Affine {
+	return Affine{A: 1, C: dx, E: 1, F: dy}
+}
+
+func Scaling (sx, sy float64) Affine {
+	return Affine{A: sx, E: sy}
+}
+
+// Counter clockwise rotation around the origin, angle in radians
+func Rotation (angle float64) Affine {
+	sin, cos := math.Sincos(angle)
+	return Affine{A: cos, B: -sin, D: sin, E: cos}
+}
+
+func (t Affine) Apply (x, y float64) (float64, float64) {
+	return t.A * x + t.B * y + t.C, t.D * x + t.E * y + t.F
+}
+
+// Transform that applies t and then next
+func (t Affine) Then (next Affine) Affine {
+	return Affine{
+		A: next.A * t.A + next.B * t.D, B: next.A * t.B + next.B * t.E, C: next.A * t.C + next.B * t.F + next.C,
+		D: next.D * t.A + next.E * t.D, E: next.D * t.B + next.E * t.E, F: next.D * t.C + next.E * t.F + next.F,
+	}
+}
+
+// Inverse transform, ok is false if t is singular
+func (t Affine) Inverse () (inverse Affine, ok bool) {
+	det := t.A * t.E - t.B * t.D
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Affine{}, false
+	}
+	inverse.A, inverse.B = t.E / det, -t.B / det
+	inverse.D, inverse.E = -t.D / det, t.A / det
+	inverse.C = -(inverse.A * t.C + inverse.B * t.F)
+	inverse.F = -(inverse.D * t.C + inverse.E * t.F)
+	return inverse, true
+}
+
+// Hull of the points after Options.Transform, mapped back to the original coordinates
+func computeAffine (ctx context.Context, points FlatPoints, o *Options, levels []float64) (Hull, []FlatPoints) {
+	inverse, ok := Affine{}, false
+	if o.InverseTransform != nil {
+		inverse, ok = *o.InverseTransform, true
+	} else {
+		inverse, ok = o.Transform.Inverse()
+	}
+	inner := *o
+	inner.Transform, inner.InverseTransform = nil, nil
+	var backward func (x, y float64) (float64, float64)
+	if ok {
+		backward = inverse.Apply
+	}
+	return computeMapped(ctx, points, &inner, levels, o.Transform.Apply, backward)
+}
+
+// Hull of the points mapped by forward, mapped back with backward. Vertices that are input points are returned exactly,
+// others go through backward, or are left as they are if it is nil
+func computeMapped (ctx context.Context, points FlatPoints, o *Options, levels []float64, forward, backward func (x, y float64) (float64, float64)) (hull Hull, levelHulls []FlatPoints) {
+	n := points.Len()
+	transformed := make(FlatPoints, len(points))
+	index := make([]int, n)
+	for i := 0; i < n; i++ {
+		transformed[2 * i], transformed[2 * i + 1] = forward(points.Take(i))
+		index[i] = i
+	}
+	sort.Sort(indexedLexSorter{transformed, index})
+	// the computation reorders its input
+	lookup := append(FlatPoints{}, transformed...)
+	hull, levelHulls = computeFromSortedWithLevels(ctx, transformed, o, levels)
+	for i, d := range(hull.Dropped) {
+		hull.Dropped[i] = index[d]
+	}
+	sort.Ints(hull.Dropped)
+	untransform := func (ring FlatPoints) FlatPoints {
+		result := make(FlatPoints, len(ring))
+		for i := 0; i < len(ring); i += 2 {
+			x, y := ring[i], ring[i + 1]
+			j := sort.Search(n, func (j int) bool {
+				return lookup[2 * j] > x || (lookup[2 * j] == x && lookup[2 * j + 1] >= y)
+			})
+			if j < n && lookup[2 * j] == x && lookup[2 * j + 1] == y {
+				result[i], result[i + 1] = points.Take(index[j])
+			} else if backward != nil {
+				result[i], result[i + 1] = backward(x, y)
+			} else {
+				result[i], result[i + 1] = x, y
+			}
+		}
+		return result
+	}
+	hull.Points = untransform(hull.Points)
+	// mappings that reflect, such as Scaling(-1, 1), turn the counter clockwise rings clockwise
+	if SignedArea(hull.Points) < 0 {
+		hull.Points = reverseRing(hull.Points)
+		for i, j := 0, len(hull.Provenance) - 1; i < j; i, j = i + 1, j - 1 {
+			hull.Provenance[i], hull.Provenance[j] = hull.Provenance[j], hull.Provenance[i]
+		}
+	}
+	for i := range(levelHulls) {
+		if levelHulls[i] = untransform(levelHulls[i]); SignedArea(levelHulls[i]) < 0 {
+			levelHulls[i] = reverseRing(levelHulls[i])
+		}
+	}
+	return hull, levelHulls
+}
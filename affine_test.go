@@ -0,0 +1,66 @@
+package ConcaveHull
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestAffine (t *testing.T) {
+	transform := Rotation(math.Pi / 3).Then(Scaling(2, 3)).Then(Translation(5, -1))
+	inverse, ok := transform.Inverse()
+	assert.True(t, ok)
+	x, y := transform.Apply(0.3, 0.7)
+	x, y = inverse.Apply(x, y)
+	assert.InDelta(t, 0.3, x, 1e-12)
+	assert.InDelta(t, 0.7, y, 1e-12)
+	_, ok = Scaling(0, 1).Inverse()
+	assert.False(t, ok)
+}
+
+func TestComputeWithOptions_transform (t *testing.T) {
+	r := rand.New(rand.NewSource(12))
+	points := hulltest.Ring(r, 1000, 0.3, 0.5)
+	// quarter turn, exact in floating point
+	quarter := Affine{B: -1, D: 1}
+	rotated := make([]float64, len(points))
+	for i := 0; i < len(points); i += 2 {
+		rotated[i], rotated[i + 1] = quarter.Apply(points[i], points[i + 1])
+	}
+	expected := ComputeWithOptions(FlatPoints(rotated), &Options{Seglength: 0.02})
+	input := append([]float64{}, points...)
+	result := ComputeWithOptions(FlatPoints(points), &Options{Seglength: 0.02, Transform: &quarter})
+	hulltest.AssertValid(t, input, result)
+	assert.Equal(t, len(expected), len(result))
+	for i := 0; i < len(result); i += 2 {
+		x, y := quarter.Apply(result[i], result[i + 1])
+		assert.Equal(t, []float64{expected[i], expected[i + 1]}, []float64{x, y})
+	}
+}
+
+// Projection that swaps the axes, which reflects
+type swapAxes struct{}
+
+func (swapAxes) Forward (x, y float64) (float64, float64) {
+	return y, x
+}
+
+func (swapAxes) Inverse (x, y float64) (float64, float64) {
+	return y, x
+}
+
+func TestComputeWithOptions_reflection (t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	points := hulltest.Ring(r, 1000, 0.3, 0.5)
+	mirror := Scaling(-1, 1)
+	for _, o := range([]*Options{{Seglength: 0.02, Transform: &mirror}, {Seglength: 0.02, Projection: swapAxes{}}}) {
+		hull := ComputeWithOptions(append(FlatPoints{}, points...), o)
+		hulltest.AssertValid(t, points, hull)
+		assert.True(t, IsCCW(hull))
+		for _, level := range(ComputeLevels(append(FlatPoints{}, points...), []float64{0.05}, o)) {
+			assert.True(t, IsCCW(level))
+		}
+	}
+}
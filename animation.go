@@ -0,0 +1,121 @@
+package ConcaveHull
+
+import "math"
+
+// Hull between a and b, a at t = 0 and b at t = 1, as a closed ring of the given number of vertices. Both hulls are resampled
+// to that many points at regular arc length, counter clockwise, and the start of b is chosen to minimize the distance between
+// corresponding points, so the frames do not twist. If vertices is 0, the larger number of vertices of the two is used
+func InterpolateHulls (a, b FlatPoints, t float64, vertices int) FlatPoints {
+	if vertices <= 0 {
+		vertices = openRing(a).Len()
+		if n := openRing(b).Len(); n > vertices {
+			vertices = n
+		}
+	}
+	ra, rb := correspondingRings(a, b, vertices)
+	return blendRings(ra, rb, t)
+}
+
+// Frames of an animation of a series of hulls, such as those of ComputeSeries: framesPerStep frames from each hull to the next,
+// the first being the hull itself, followed by the last hull, see InterpolateHulls. If vertices is 0, all frames have the
+// largest number of vertices of the series
+func AnimationFrames (hulls []FlatPoints, framesPerStep, vertices int) []FlatPoints {
+	if len(hulls) == 0 {
+		return nil
+	}
+	if framesPerStep < 1 {
+		framesPerStep = 1
+	}
+	if vertices <= 0 {
+		for _, hull := range(hulls) {
+			if n := openRing(hull).Len(); n > vertices {
+				vertices = n
+			}
+		}
+	}
+	frames := make([]FlatPoints, 0, (len(hulls) - 1) * framesPerStep + 1)
+	for i := 0; i + 1 < len(hulls); i++ {
+		ra, rb := correspondingRings(hulls[i], hulls[i + 1], vertices)
+		for f := 0; f < framesPerStep; f++ {
+			frames = append(frames, blendRings(ra, rb, float64(f) / float64(framesPerStep)))
+		}
+	}
+	last := resampleRing(hulls[len(hulls) - 1], vertices)
+	return append(frames, blendRings(last, last, 0))
+}
+
+// Open rings of n points resampled from a and b, where point i of one corresponds to point i of the other. An empty ring
+// takes the place of the other one
+func correspondingRings (a, b FlatPoints, n int) (ra, rb FlatPoints) {
+	ra, rb = resampleRing(a, n), resampleRing(b, n)
+	if ra == nil {
+		ra = rb
+	}
+	if rb == nil {
+		return ra, ra
+	}
+	best, shift := math.Inf(1), 0
+	for s := 0; s < n; s++ {
+		d := 0.
+		for i := 0; i < n && d < best; i++ {
+			xa, ya := ra.Take(i)
+			xb, yb := rb.Take((i + s) % n)
+			d += (xa - xb) * (xa - xb) + (ya - yb) * (ya - yb)
+		}
+		if d < best {
+			best, shift = d, s
+		}
+	}
+	return ra, append(append(FlatPoints{}, rb[2 * shift:]...), rb[:2 * shift]...)
+}
+
+// n points at regular arc length along the counter clockwise ring, starting at its first vertex, nil for an empty ring
+func resampleRing (ring FlatPoints, n int) FlatPoints {
+	ring = openRing(ring)
+	if ring.Len() == 0 || n <= 0 {
+		return nil
+	}
+	if SignedArea(ring) < 0 {
+		ring = reverseRing(ring)
+	}
+	m := ring.Len()
+	perimeter := 0.
+	for i := 0; i < m; i++ {
+		x1, y1 := ring.Take(i)
+		x2, y2 := ring.Take((i + 1) % m)
+		perimeter += math.Hypot(x2 - x1, y2 - y1)
+	}
+	resampled := make(FlatPoints, 0, 2 * n)
+	edge, walked := 0, 0.
+	for k := 0; k < n; k++ {
+		target := perimeter * float64(k) / float64(n)
+		for {
+			x1, y1 := ring.Take(edge)
+			x2, y2 := ring.Take((edge + 1) % m)
+			length := math.Hypot(x2 - x1, y2 - y1)
+			if walked + length >= target || edge == m - 1 {
+				t := 0.
+				if length > 0 {
+					t = math.Min(1, (target - walked) / length)
+				}
+				resampled = append(resampled, x1 + (x2 - x1) * t, y1 + (y2 - y1) * t)
+				break
+			}
+			walked += length
+			edge++
+		}
+	}
+	return resampled
+}
+
+// Closed ring blending corresponding open rings
+func blendRings (a, b FlatPoints, t float64) FlatPoints {
+	if a == nil {
+		return FlatPoints{}
+	}
+	blended := make(FlatPoints, 0, len(a) + 2)
+	for i := range(a) {
+		blended = append(blended, a[i] + (b[i] - a[i]) * t)
+	}
+	return append(blended, blended[0], blended[1])
+}
@@ -0,0 +1,37 @@
+package ConcaveHull
+
+import (
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolateHulls (t *testing.T) {
+	square := FlatPoints{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}
+	// same square, clockwise and starting elsewhere, moved by (10, 0)
+	moved := FlatPoints{12, 2, 12, 0, 10, 0, 10, 2, 12, 2}
+	assert.Equal(t, square, InterpolateHulls(square, moved, 0, 0))
+	assert.Equal(t, FlatPoints{10, 0, 12, 0, 12, 2, 10, 2, 10, 0}, InterpolateHulls(square, moved, 1, 0))
+	assert.Equal(t, FlatPoints{5, 0, 7, 0, 7, 2, 5, 2, 5, 0}, InterpolateHulls(square, moved, 0.5, 0))
+
+	resampled := InterpolateHulls(square, square, 0, 8)
+	assert.Equal(t, FlatPoints{0, 0, 1, 0, 2, 0, 2, 1, 2, 2, 1, 2, 0, 2, 0, 1, 0, 0}, resampled)
+}
+
+func TestAnimationFrames (t *testing.T) {
+	small := FlatPoints{0, 0, 1, 0, 0, 1, 0, 0}
+	large := FlatPoints{0, 0, 4, 0, 4, 4, 0, 4, 0, 0}
+	frames := AnimationFrames([]FlatPoints{small, large, {5, 5}}, 4, 0)
+	assert.Len(t, frames, 9)
+	for _, frame := range(frames) {
+		assert.Equal(t, 5, frame.Len())
+	}
+	// resampling the triangle cuts its corners
+	assert.True(t, SignedArea(frames[0]) > 0 && SignedArea(frames[0]) <= 0.5)
+	assert.True(t, math.Abs(SignedArea(frames[4]) - 16) < 1e-9)
+	for i := 1; i < 4; i++ {
+		assert.True(t, SignedArea(frames[i]) > SignedArea(frames[i - 1]))
+	}
+	assert.Equal(t, FlatPoints{5, 5, 5, 5, 5, 5, 5, 5, 5, 5}, frames[8])
+	assert.Nil(t, AnimationFrames(nil, 4, 0))
+}
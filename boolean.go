@@ -0,0 +1,39 @@
+package ConcaveHull
+
+import "github.com/USACE/concavehull/geomutil"
+
+// Union of two hull polygons. The result is a list of closed rings, exterior rings are counter clockwise and holes clockwise
+func Union (a, b FlatPoints) []FlatPoints {
+	return flatRings(geomutil.Union(a, b))
+}
+
+// Intersection of two hull polygons. The result is a list of closed rings, exterior rings are counter clockwise and holes clockwise
+func Intersection (a, b FlatPoints) []FlatPoints {
+	return flatRings(geomutil.Intersection(a, b))
+}
+
+// Part of a that is not covered by b. The result is a list of closed rings, exterior rings are counter clockwise and holes clockwise
+func Difference (a, b FlatPoints) []FlatPoints {
+	return flatRings(geomutil.Difference(a, b))
+}
+
+// Chain directed edges into closed rings, see geomutil.ChainEdges
+func chainEdges (vertices [][2]float64, edges []geomutil.Edge) []FlatPoints {
+	return flatRings(geomutil.ChainEdges(vertices, edges))
+}
+
+// Open, counter clockwise copy of the ring
+func counterClockwise (ring FlatPoints) FlatPoints {
+	return geomutil.CounterClockwise(ring)
+}
+
+func flatRings (rings [][]float64) []FlatPoints {
+	if rings == nil {
+		return nil
+	}
+	flat := make([]FlatPoints, len(rings))
+	for i, ring := range(rings) {
+		flat[i] = ring
+	}
+	return flat
+}
@@ -0,0 +1,46 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func totalArea (rings []FlatPoints) float64 {
+	area := 0.
+	for _, r := range(rings) {
+		area += SignedArea(r)
+	}
+	return area
+}
+
+func TestOverlay_overlappingSquares (t *testing.T) {
+	a := FlatPoints{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}
+	b := FlatPoints{1, 1, 3, 1, 3, 3, 1, 3, 1, 1}
+	assert.InDelta(t, 7, totalArea(Union(a, b)), 1e-12)
+	assert.InDelta(t, 1, totalArea(Intersection(a, b)), 1e-12)
+	assert.InDelta(t, 3, totalArea(Difference(a, b)), 1e-12)
+	assert.Equal(t, []FlatPoints{{1, 1, 2, 1, 2, 2, 1, 2, 1, 1}}, Intersection(a, b))
+}
+
+func TestOverlay_sharedEdges (t *testing.T) {
+	a := FlatPoints{0, 0, 2, 0, 2, 2, 0, 2}
+	// clockwise, sharing the edge x = 2
+	b := FlatPoints{2, 0, 2, 2, 4, 2, 4, 0}
+	assert.Equal(t, []FlatPoints{{0, 0, 4, 0, 4, 2, 0, 2, 0, 0}}, Union(a, b))
+	assert.Len(t, Intersection(a, b), 0)
+	assert.InDelta(t, 4, totalArea(Difference(a, b)), 1e-12)
+	// same polygon
+	assert.InDelta(t, 4, totalArea(Union(a, a)), 1e-12)
+	assert.InDelta(t, 4, totalArea(Intersection(a, a)), 1e-12)
+	assert.Len(t, Difference(a, a), 0)
+}
+
+func TestOverlay_hole (t *testing.T) {
+	a := FlatPoints{0, 0, 4, 0, 4, 4, 0, 4}
+	b := FlatPoints{1, 1, 2, 1, 2, 2, 1, 2}
+	difference := Difference(a, b)
+	assert.Len(t, difference, 2)
+	assert.InDelta(t, 15, totalArea(difference), 1e-12)
+	assert.InDelta(t, 16, totalArea(Union(a, b)), 1e-12)
+	assert.InDelta(t, 1, totalArea(Intersection(a, b)), 1e-12)
+}
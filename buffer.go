@@ -0,0 +1,49 @@
+package ConcaveHull
+
+import "math"
+
+// Number of segments used to approximate half a circle around the vertices of the hull
+const bufferArcSegments = 8
+
+// Shrink the hull polygon inward by distance. Parts narrower than twice the distance collapse, so the result may have
+// several rings or none at all. Rings are closed and counter clockwise.
+// Erosion is computed as the hull minus the buffer of each of its edges, with circles approximated by polygons
+func Erode (ring FlatPoints, distance float64) []FlatPoints {
+	ring = counterClockwise(ring)
+	if ring.Len() < 3 {
+		return nil
+	}
+	if distance <= 0 {
+		return []FlatPoints{closeRing(ring)}
+	}
+	parts := []FlatPoints{ring}
+	n := ring.Len()
+	for i := 0; i < n && len(parts) > 0; i++ {
+		x1, y1 := ring.Take(i)
+		x2, y2 := ring.Take((i + 1) % n)
+		edgeBuffer := stadium(x1, y1, x2, y2, distance)
+		var eroded []FlatPoints
+		for _, part := range(parts) {
+			eroded = append(eroded, Difference(part, edgeBuffer)...)
+		}
+		parts = eroded
+	}
+	return parts
+}
+
+// Counter clockwise polygon of the points within distance of the segment
+func stadium (x1, y1, x2, y2, distance float64) FlatPoints {
+	angle := math.Atan2(y2 - y1, x2 - x1)
+	polygon := make(FlatPoints, 0, 4 * (bufferArcSegments + 1))
+	// half circle around the end, from the right side of the segment to the left side
+	for k := 0; k <= bufferArcSegments; k++ {
+		a := angle - math.Pi / 2 + math.Pi * float64(k) / bufferArcSegments
+		polygon = append(polygon, x2 + distance * math.Cos(a), y2 + distance * math.Sin(a))
+	}
+	// half circle around the start, back to the right side
+	for k := 0; k <= bufferArcSegments; k++ {
+		a := angle + math.Pi / 2 + math.Pi * float64(k) / bufferArcSegments
+		polygon = append(polygon, x1 + distance * math.Cos(a), y1 + distance * math.Sin(a))
+	}
+	return polygon
+}
@@ -0,0 +1,24 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErode (t *testing.T) {
+	square := FlatPoints{0, 0, 4, 0, 4, 4, 0, 4, 0, 0}
+	core := Erode(square, 1)
+	assert.Len(t, core, 1)
+	assert.InDelta(t, 4, totalArea(core), 1e-9)
+	assert.Len(t, Erode(square, 2.5), 0)
+}
+
+func TestErode_splitsNarrowParts (t *testing.T) {
+	// two 4x4 squares joined by a corridor 1 unit wide
+	dumbbell := FlatPoints{0, 0, 4, 0, 4, 1.5, 6, 1.5, 6, 0, 10, 0, 10, 4, 6, 4, 6, 2.5, 4, 2.5, 4, 4, 0, 4}
+	core := Erode(dumbbell, 1)
+	assert.Len(t, core, 2)
+	// each core is a 2x2 square plus a small lens bulging towards the corridor between the rounded corners
+	area := totalArea(core)
+	assert.True(t, area > 8 && area < 8.5, area)
+}
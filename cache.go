@@ -0,0 +1,216 @@
+package ConcaveHull
+
+import (
+	"container/list"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+)
+
+// Storage of computed hulls, see Options.Cache. Implementations must be safe for concurrent use
+type Cache interface {
+	Get (key string) (hull FlatPoints, ok bool)
+	Set (key string, hull FlatPoints)
+}
+
+// Key identifying a computation: a hash of the sorted points and of the options that affect the result.
+// Computations that cannot be cached, because they depend on time or on functions in the options, return false
+func fingerprint (sorted FlatPoints, o *Options) (string, bool) {
+	h := fnv.New128a()
+	var buffer [8]byte
+	for _, v := range(sorted) {
+		binary.LittleEndian.PutUint64(buffer[:], math.Float64bits(v))
+		h.Write(buffer[:])
+	}
+	if o != nil {
+		value := reflect.ValueOf(*o)
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Field(i)
+			name := value.Type().Field(i).Name
+			switch name {
+			case "ConcaveHullPool", "Cache", "Instrumentation", "Tracer", "Workers", "CheckpointPath", "CheckpointInterval":
+				continue
+			case "TimeBudget":
+				if field.Int() != 0 {
+					return "", false
+				}
+				continue
+			case "CompatVersion":
+				// 0 follows the latest version, so its hulls are those of the version that is latest when they are computed
+				if field.Int() == 0 {
+					field = reflect.ValueOf(CompatLatest)
+				}
+			case "ClipInputBBox":
+				// hashed by value, unlike the other pointers
+				if !field.IsNil() {
+					field = field.Elem()
+				}
+			}
+			h.Write([]byte(name))
+			if !hashValue(h, field) {
+				return "", false
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// Write a value of the options in the hash. Returns false for kinds whose behaviour cannot be hashed, like functions
+func hashValue (h interface{ Write ([]byte) (int, error) }, v reflect.Value) bool {
+	var buffer [8]byte
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			buffer[0] = 1
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		binary.LittleEndian.PutUint64(buffer[:], uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		binary.LittleEndian.PutUint64(buffer[:], v.Uint())
+	case reflect.Float32, reflect.Float64:
+		binary.LittleEndian.PutUint64(buffer[:], math.Float64bits(v.Float()))
+	case reflect.String:
+		h.Write([]byte(v.String()))
+	case reflect.Slice, reflect.Array:
+		binary.LittleEndian.PutUint64(buffer[:], uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if !hashValue(h, v.Index(i)) {
+				return false
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !hashValue(h, v.Field(i)) {
+				return false
+			}
+		}
+	case reflect.Ptr, reflect.Interface, reflect.Func, reflect.Map, reflect.Chan:
+		// unset hooks don't change the result, set ones might in ways that cannot be hashed
+		return v.IsNil()
+	}
+	h.Write(buffer[:])
+	return true
+}
+
+// In memory cache keeping the most recently used hulls
+type LRUCache struct {
+	mu sync.Mutex
+	capacity int
+	entries map[string]*list.Element
+	order *list.List
+}
+
+type lruEntry struct {
+	key string
+	hull FlatPoints
+}
+
+func NewLRUCache (capacity int) *LRUCache {
+	return &LRUCache{capacity: capacity, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+func (c *LRUCache) Get (key string) (FlatPoints, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return append(FlatPoints{}, element.Value.(*lruEntry).hull...), true
+}
+
+func (c *LRUCache) Set (key string, hull FlatPoints) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hull = append(FlatPoints{}, hull...)
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruEntry).hull = hull
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, hull: hull})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Key value storage of raw bytes, e.g. a thin wrapper around a Redis client
+type ByteStore interface {
+	Get (key string) (value []byte, ok bool)
+	Set (key string, value []byte)
+}
+
+// Cache storing hulls as little endian float64 in a ByteStore
+func NewByteCache (store ByteStore) Cache {
+	return byteCache{store}
+}
+
+type byteCache struct {
+	store ByteStore
+}
+
+func (c byteCache) Get (key string) (FlatPoints, bool) {
+	value, ok := c.store.Get(key)
+	if !ok || len(value) % 8 != 0 {
+		return nil, false
+	}
+	return decodeFloats(value), true
+}
+
+func (c byteCache) Set (key string, hull FlatPoints) {
+	c.store.Set(key, encodeFloats(hull))
+}
+
+// Cache storing one file per hull in a directory. Errors are ignored, a failed read is a cache miss
+func NewFileCache (dir string) Cache {
+	return byteCache{fileStore(dir)}
+}
+
+type fileStore string
+
+func (dir fileStore) Get (key string) ([]byte, bool) {
+	value, err := os.ReadFile(filepath.Join(string(dir), key + ".hull"))
+	return value, err == nil
+}
+
+func (dir fileStore) Set (key string, value []byte) {
+	// write and rename so that concurrent readers never see partial files
+	tmp, err := os.CreateTemp(string(dir), key + ".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(value)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(string(dir), key + ".hull"))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func encodeFloats (values []float64) []byte {
+	data := make([]byte, 8 * len(values))
+	for i, v := range(values) {
+		binary.LittleEndian.PutUint64(data[8 * i:], math.Float64bits(v))
+	}
+	return data
+}
+
+func decodeFloats (data []byte) FlatPoints {
+	values := make(FlatPoints, len(data) / 8)
+	for i := range(values) {
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8 * i:]))
+	}
+	return values
+}
@@ -0,0 +1,59 @@
+package ConcaveHull
+
+import (
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+type countingCache struct {
+	Cache
+	hits int
+}
+
+func (c *countingCache) Get (key string) (FlatPoints, bool) {
+	hull, ok := c.Cache.Get(key)
+	if ok {
+		c.hits++
+	}
+	return hull, ok
+}
+
+func TestComputeWithOptions_cache (t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	points := hulltest.Random(r, 500)
+	for _, cache := range([]Cache{NewLRUCache(2), NewFileCache(t.TempDir())}) {
+		counting := &countingCache{Cache: cache}
+		first := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.01, Cache: counting})
+		second := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.01, Cache: counting})
+		assert.Equal(t, 1, counting.hits)
+		assert.Equal(t, first, second)
+		// different options are a different entry
+		ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.02, Cache: counting})
+		assert.Equal(t, 1, counting.hits)
+	}
+}
+
+func TestFingerprint_compatVersion (t *testing.T) {
+	points := FlatPoints{0, 0, 0, 1, 1, 0}
+	latest, ok := fingerprint(points, &Options{})
+	assert.True(t, ok)
+	pinned, _ := fingerprint(points, &Options{CompatVersion: CompatLatest})
+	assert.Equal(t, latest, pinned)
+	v1, _ := fingerprint(points, &Options{CompatVersion: CompatV1})
+	assert.NotEqual(t, latest, v1)
+}
+
+func TestLRUCache_evicts (t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", FlatPoints{1})
+	cache.Set("b", FlatPoints{2})
+	cache.Get("a")
+	cache.Set("c", FlatPoints{3})
+	_, ok := cache.Get("b")
+	assert.False(t, ok)
+	hull, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, FlatPoints{1}, hull)
+}
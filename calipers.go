@@ -0,0 +1,82 @@
+package ConcaveHull
+
+import "math"
+
+// Farthest pair of the points and their distance, found with rotating calipers on the convex hull. Points are not modified
+func Diameter (points FlatPoints) (x1, y1, x2, y2, diameter float64) {
+	hull := counterClockwiseHull(points)
+	n := hull.Len()
+	if n == 0 {
+		return
+	}
+	x1, y1 = hull.Take(0)
+	x2, y2 = x1, y1
+	best := 0.
+	check := func (i, j int) {
+		ax, ay := hull.Take(i)
+		bx, by := hull.Take(j)
+		if d := (ax - bx) * (ax - bx) + (ay - by) * (ay - by); d > best {
+			best, x1, y1, x2, y2 = d, ax, ay, bx, by
+		}
+	}
+	if n < 3 {
+		check(0, n - 1)
+		return x1, y1, x2, y2, math.Sqrt(best)
+	}
+	j := 1
+	for i := 0; i < n; i++ {
+		next := (i + 1) % n
+		for edgeDistance(hull, i, next, (j + 1) % n) > edgeDistance(hull, i, next, j) {
+			j = (j + 1) % n
+		}
+		check(i, j)
+		check(next, j)
+	}
+	return x1, y1, x2, y2, math.Sqrt(best)
+}
+
+// Smallest distance between two parallel lines enclosing the points, and the angle in radians of those lines with the x axis.
+// One of the lines always contains an edge of the convex hull. Points are not modified
+func Width (points FlatPoints) (width, angle float64) {
+	hull := counterClockwiseHull(points)
+	n := hull.Len()
+	if n < 3 {
+		if n == 2 {
+			x1, y1 := hull.Take(0)
+			x2, y2 := hull.Take(1)
+			angle = math.Atan2(y2 - y1, x2 - x1)
+		}
+		return 0, angle
+	}
+	width = math.Inf(1)
+	j := 1
+	for i := 0; i < n; i++ {
+		next := (i + 1) % n
+		for edgeDistance(hull, i, next, (j + 1) % n) > edgeDistance(hull, i, next, j) {
+			j = (j + 1) % n
+		}
+		if d := edgeDistance(hull, i, next, j); d < width {
+			x1, y1 := hull.Take(i)
+			x2, y2 := hull.Take(next)
+			width, angle = d, math.Atan2(y2 - y1, x2 - x1)
+		}
+	}
+	return width, angle
+}
+
+// Distance from vertex k to the line through vertices i and j
+func edgeDistance (ring FlatPoints, i, j, k int) float64 {
+	x1, y1 := ring.Take(i)
+	x2, y2 := ring.Take(j)
+	x, y := ring.Take(k)
+	return math.Abs(orientation(x1, y1, x2, y2, x, y)) / math.Hypot(x2 - x1, y2 - y1)
+}
+
+// Open counter clockwise convex hull of the points
+func counterClockwiseHull (points FlatPoints) FlatPoints {
+	hull := openRing(convexRing(points))
+	if SignedArea(hull) < 0 {
+		hull = reverseRing(hull)
+	}
+	return hull
+}
@@ -0,0 +1,34 @@
+package ConcaveHull
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestDiameter (t *testing.T) {
+	r := rand.New(rand.NewSource(18))
+	points := FlatPoints(hulltest.Clustered(r, 300, 3, 0.1))
+	expected := farthestPair(points)
+	_, _, _, _, diameter := Diameter(points)
+	assert.InDelta(t, math.Hypot(expected[2] - expected[0], expected[3] - expected[1]), diameter, 1e-12)
+	x1, y1, x2, y2, diameter := Diameter(FlatPoints{0, 0, 3, 4})
+	assert.Equal(t, []float64{0, 0, 3, 4, 5}, []float64{x1, y1, x2, y2, diameter})
+}
+
+func TestWidth (t *testing.T) {
+	// rectangle 4 by 1 rotated by 30 degrees, with points inside
+	var points FlatPoints
+	rotation := Rotation(math.Pi / 6)
+	for _, p := range([][2]float64{{0, 0}, {4, 0}, {4, 1}, {0, 1}, {2, 0.5}, {1, 0.2}}) {
+		x, y := rotation.Apply(p[0], p[1])
+		points = append(points, x, y)
+	}
+	width, angle := Width(points)
+	assert.InDelta(t, 1, width, 1e-12)
+	assert.InDelta(t, 0, math.Sin(angle - math.Pi / 6), 1e-12)
+	width, _ = Width(FlatPoints{0, 0, 1, 1, 2, 2})
+	assert.Equal(t, 0., width)
+}
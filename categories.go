@@ -0,0 +1,181 @@
+package ConcaveHull
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Hull of the points of one category, see ComputeCategories
+type CategoryHull struct {
+	Hull
+	Category int
+	// Indices of the points of the category in the input
+	Indices []int
+}
+
+// One hull per category of points, such as the species of a survey, sorted by category. categories holds the category of
+// each point. The index for the nearest neighbour search is built once over all the points, and each computation only
+// sees the points of its category, instead of building one index per category. Options.SpatialIndex is replaced, except
+// with options that move or drop points before the search, such as Transform, GridSize or OutlierRejection, or that search
+// without the index, such as Metric, for which each category is computed on its own. Unlike Compute, the input is not modified
+func ComputeCategories (ctx context.Context, points FlatPoints, categories []int, o *Options) ([]CategoryHull, error) {
+	o = o.snapshot()
+	if err := validatePoints(points); err != nil {
+		return nil, err
+	}
+	if len(categories) != points.Len() {
+		return nil, fmt.Errorf("%w: %d categories for %d points", ErrMalformedPoints, len(categories), points.Len())
+	}
+	indices := make(map[int][]int)
+	for i, category := range(categories) {
+		indices[category] = append(indices[category], i)
+	}
+	hulls := make([]CategoryHull, 0, len(indices))
+	for category, members := range(indices) {
+		hulls = append(hulls, CategoryHull{Category: category, Indices: members})
+	}
+	sort.Slice(hulls, func (i, j int) bool { return hulls[i].Category < hulls[j].Category })
+	sharesIndex := o == nil || !(o.Algorithm != AlgorithmSnapHull || o.Metric != nil || o.ScaleX > 0 || o.ScaleY > 0 || o.Transform != nil || o.Projection != nil || o.GridSize > 0 || o.ClipInputBBox != nil || o.ExactArithmetic)
+	var index *categoryIndex
+	if sharesIndex {
+		index = newCategoryIndex(points, categories)
+	}
+	for k := range(hulls) {
+		members := hulls[k].Indices
+		sort.Slice(members, func (i, j int) bool {
+			xi, yi := points.Take(members[i])
+			xj, yj := points.Take(members[j])
+			return xi < xj || xi == xj && yi < yj
+		})
+		sorted := make(FlatPoints, 0, 2 * len(members))
+		for _, i := range(members) {
+			sorted = append(sorted, points[2 * i], points[2 * i + 1])
+		}
+		categoryOptions := o
+		if sharesIndex && prefilter(sorted, o) == nil {
+			shared := Options{}
+			if o != nil {
+				shared = *o
+			}
+			category := hulls[k].Category
+			shared.SpatialIndex = func (FlatPoints) SpatialIndex { return categoryView{index, category} }
+			categoryOptions = &shared
+		}
+		hull, err := computeFromSortedContext(ctx, sorted, categoryOptions)
+		if err != nil {
+			return nil, err
+		}
+		for j, d := range(hull.Dropped) {
+			hull.Dropped[j] = members[d]
+		}
+		hulls[k].Hull = hull
+	}
+	return hulls, nil
+}
+
+// Uniform grid of points whose cells list their points by category, so a search for one category skips the others
+type categoryIndex struct {
+	cellGrid
+	points FlatPoints
+	// nil if all the points are in category 0
+	categories []int
+	// points of cell i are indices[start[i]:start[i + 1]], sorted by category
+	start []int
+	indices []int
+}
+
+func newCategoryIndex (points FlatPoints, categories []int) *categoryIndex {
+	n := points.Len()
+	minX, minY, maxX, maxY := bbox(points)
+	index := &categoryIndex{cellGrid: newCellGrid(minX, minY, maxX, maxY, math.Sqrt(float64(n))), points: points, categories: categories}
+	cells := make([]int, n)
+	index.start = make([]int, index.nx * index.ny + 1)
+	for i := 0; i < n; i++ {
+		cx, cy := index.cell(points.Take(i))
+		cells[i] = cy * index.nx + cx
+		index.start[cells[i] + 1]++
+	}
+	for i := 1; i < len(index.start); i++ {
+		index.start[i] += index.start[i - 1]
+	}
+	index.indices = make([]int, n)
+	for i := range(index.indices) {
+		index.indices[i] = i
+	}
+	sort.Slice(index.indices, func (i, j int) bool {
+		a, b := index.indices[i], index.indices[j]
+		ca, cb := index.category(a), index.category(b)
+		return cells[a] < cells[b] || cells[a] == cells[b] && (ca < cb || ca == cb && a < b)
+	})
+	return index
+}
+
+func (g *categoryIndex) category (k int) int {
+	if g.categories == nil {
+		return 0
+	}
+	return g.categories[k]
+}
+
+// Points of one category of a shared index
+type categoryView struct {
+	index *categoryIndex
+	category int
+}
+
+func (v categoryView) FindNearestPointWithin (x, y, maxSquaredDistance float64) (float64, float64, float64, bool) {
+	return v.index.nearest(x, y, maxSquaredDistance, v.category, nil)
+}
+
+// Points of a shared index that pass a filter, see Concaver.ComputeSubset
+type subsetView struct {
+	index *categoryIndex
+	filter func (k int) bool
+}
+
+func (v subsetView) FindNearestPointWithin (x, y, maxSquaredDistance float64) (float64, float64, float64, bool) {
+	return v.index.nearest(x, y, maxSquaredDistance, 0, v.filter)
+}
+
+// Closest point of the category for which filter, if not nil, is true. Rings of cells are visited around the cell of the
+// point until no point further away can be closer
+func (g *categoryIndex) nearest (x, y, maxSquaredDistance float64, category int, filter func (k int) bool) (px, py, best float64, found bool) {
+	best = maxSquaredDistance
+	cx, cy := g.cell(x, y)
+	// distance from (x, y) to the grid, for points outside it
+	ox := math.Max(0, math.Max(g.minX - x, x - (g.minX + float64(g.nx) * g.cellSize)))
+	oy := math.Max(0, math.Max(g.minY - y, y - (g.minY + float64(g.ny) * g.cellSize)))
+	offset := math.Hypot(ox, oy)
+	maxRing := intMax(intMax(cx, g.nx - 1 - cx), intMax(cy, g.ny - 1 - cy))
+	for ring := 0; ring <= maxRing; ring++ {
+		// points of this ring are at least this far
+		if reach := math.Max(offset, float64(ring - 1) * g.cellSize); ring > 0 && reach * reach > best {
+			break
+		}
+		for j := intMax(cy - ring, 0); j <= intMin(cy + ring, g.ny - 1); j++ {
+			for i := intMax(cx - ring, 0); i <= intMin(cx + ring, g.nx - 1); i++ {
+				if i != cx - ring && i != cx + ring && j != cy - ring && j != cy + ring {
+					continue
+				}
+				c := j * g.nx + i
+				cell := g.indices[g.start[c]:g.start[c + 1]]
+				first := sort.Search(len(cell), func (k int) bool { return g.category(cell[k]) >= category })
+				for _, k := range(cell[first:]) {
+					if g.category(k) != category {
+						break
+					}
+					if filter != nil && !filter(k) {
+						continue
+					}
+					qx, qy := g.points.Take(k)
+					if d := squaredDistance(x, y, qx, qy); d < best || !found && d <= best {
+						best, px, py, found = d, qx, qy, true
+					}
+				}
+			}
+		}
+	}
+	return px, py, best, found
+}
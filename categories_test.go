@@ -0,0 +1,61 @@
+package ConcaveHull
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestComputeCategories (t *testing.T) {
+	r := rand.New(rand.NewSource(21))
+	points := FlatPoints(hulltest.Clustered(r, 3000, 6, 0.1))
+	categories := make([]int, points.Len())
+	for i := range(categories) {
+		categories[i] = r.Intn(3) * 10
+	}
+	input := append(FlatPoints{}, points...)
+	o := &Options{SeglengthRelative: 0.01}
+	hulls, err := ComputeCategories(context.Background(), points, categories, o)
+	assert.NoError(t, err)
+	assert.Equal(t, input, points)
+	assert.Len(t, hulls, 3)
+	for k, hull := range(hulls) {
+		assert.Equal(t, 10 * k, hull.Category)
+		var own FlatPoints
+		for _, i := range(hull.Indices) {
+			assert.Equal(t, hull.Category, categories[i])
+			own = append(own, points[2 * i], points[2 * i + 1])
+		}
+		// same hull as computed from the points of the category alone
+		alone, err := ComputeContext(context.Background(), own, o)
+		assert.NoError(t, err)
+		assert.Equal(t, alone.Points, hull.Points)
+	}
+
+	// options that move points compute each category on its own
+	scaled, err := ComputeCategories(context.Background(), points, categories, &Options{SeglengthRelative: 0.01, GridSize: 0.001})
+	assert.NoError(t, err)
+	assert.Len(t, scaled, 3)
+
+	_, err = ComputeCategories(context.Background(), points, categories[1:], o)
+	assert.True(t, errors.Is(err, ErrMalformedPoints))
+}
+
+func TestCategoryView (t *testing.T) {
+	points := FlatPoints{0, 0, 1, 0, 2, 0, 5, 5, 9, 9}
+	index := newCategoryIndex(points, []int{1, 2, 1, 2, 1})
+	x, y, d, found := categoryView{index, 2}.FindNearestPointWithin(1.9, 0, 100)
+	assert.True(t, found)
+	assert.Equal(t, []float64{1, 0}, []float64{x, y})
+	assert.InDelta(t, 0.81, d, 1e-12)
+	x, y, _, found = categoryView{index, 1}.FindNearestPointWithin(20, 20, 250)
+	assert.True(t, found)
+	assert.Equal(t, []float64{9, 9}, []float64{x, y})
+	_, _, _, found = categoryView{index, 1}.FindNearestPointWithin(20, 20, 240)
+	assert.False(t, found)
+	_, _, _, found = categoryView{index, 3}.FindNearestPointWithin(0, 0, 100)
+	assert.False(t, found)
+}
@@ -0,0 +1,255 @@
+package ConcaveHull
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const DEFAULT_KMEANS_ITERATIONS = 20
+
+// How points are aggregated by Centroids
+type CentroidMethod int
+
+const (
+	CentroidGrid CentroidMethod = iota // mean of the points of each square cell of CellSize
+	CentroidKMeans // K clusters found by Lloyd's algorithm from a k-means++ seeding
+)
+
+// Aggregation of points into weighted centroids, see Centroids
+type CentroidOptions struct {
+	Method CentroidMethod
+	// Used by CentroidGrid, side of the cells in the units of the coordinates
+	CellSize float64
+	// Used by CentroidKMeans, number of clusters, and iterations, DEFAULT_KMEANS_ITERATIONS if 0
+	K int
+	Iterations int
+	// Used by CentroidKMeans to pick the initial centers, so results are reproducible
+	Seed int64
+	// Centroids of fewer points are discarded, so that no centroid reveals small groups of points, e.g. 5
+	MinWeight float64
+}
+
+// Centroids of groups of points and the number of points of each, sorted lexicographically. Empty groups and groups lighter
+// than CentroidOptions.MinWeight are left out
+func Centroids (points FlatPoints, co CentroidOptions) (centroids FlatPoints, weights []float64, err error) {
+	var groups []int
+	switch co.Method {
+	case CentroidGrid:
+		if !(co.CellSize > 0) || math.IsInf(co.CellSize, 0) {
+			return nil, nil, fmt.Errorf("%w: CellSize is %v", ErrInvalidOptions, co.CellSize)
+		}
+		groups = gridGroups(points, co.CellSize)
+	case CentroidKMeans:
+		if co.K <= 0 || co.Iterations < 0 {
+			return nil, nil, fmt.Errorf("%w: K is %d and Iterations %d", ErrInvalidOptions, co.K, co.Iterations)
+		}
+		iterations := co.Iterations
+		if iterations == 0 {
+			iterations = DEFAULT_KMEANS_ITERATIONS
+		}
+		groups = kMeansGroups(points, co.K, iterations, rand.New(rand.NewSource(co.Seed)))
+	default:
+		return nil, nil, fmt.Errorf("%w: centroid method %d", ErrInvalidOptions, co.Method)
+	}
+	sumX, sumY, counts := groupSums(points, groups)
+	type centroid struct{ x, y, weight float64 }
+	var kept []centroid
+	for g, count := range(counts) {
+		if count > 0 && float64(count) >= co.MinWeight {
+			kept = append(kept, centroid{sumX[g] / float64(count), sumY[g] / float64(count), float64(count)})
+		}
+	}
+	sort.Slice(kept, func (i, j int) bool {
+		return kept[i].x < kept[j].x || kept[i].x == kept[j].x && kept[i].y < kept[j].y
+	})
+	for _, c := range(kept) {
+		centroids = append(centroids, c.x, c.y)
+		weights = append(weights, c.weight)
+	}
+	return centroids, weights, nil
+}
+
+// Hull of the weighted centroids of the points, for coverage outlines that must not follow individual points. Heavier
+// centroids pull the boundary more: a centroid of relative weight w, its weight over the mean weight, is snapped to as if it
+// were sqrt(w) times closer. Options.SpatialIndex is replaced, and Metric and ExactArithmetic ignore the weights
+func ComputeCentroidHull (ctx context.Context, points FlatPoints, co CentroidOptions, o *Options) (Hull, error) {
+	centroids, weights, err := Centroids(points, co)
+	if err != nil {
+		return Hull{}, err
+	}
+	weighted := Options{}
+	if o != nil {
+		weighted = *o.snapshot()
+	}
+	weighted.SpatialIndex = weightedIndexBuilder(centroids, weights)
+	return ComputeContext(ctx, centroids, &weighted)
+}
+
+// Group of each point in the cells of the grid, numbered in order of first appearance
+func gridGroups (points FlatPoints, cellSize float64) []int {
+	groups := make([]int, points.Len())
+	cells := make(map[[2]float64]int)
+	for i := range(groups) {
+		x, y := points.Take(i)
+		key := [2]float64{math.Floor(x / cellSize), math.Floor(y / cellSize)}
+		g, ok := cells[key]
+		if !ok {
+			g = len(cells)
+			cells[key] = g
+		}
+		groups[i] = g
+	}
+	return groups
+}
+
+// Cluster of each point after k-means, stopping early when no point changes cluster
+func kMeansGroups (points FlatPoints, k, iterations int, r *rand.Rand) []int {
+	n := points.Len()
+	groups := make([]int, n)
+	if n == 0 {
+		return groups
+	}
+	// k-means++: each center is a point drawn with probability proportional to the squared distance to the closest center
+	centers := make(FlatPoints, 0, 2 * k)
+	first := r.Intn(n)
+	centers = append(centers, points[2 * first], points[2 * first + 1])
+	closest := make([]float64, n)
+	for i := range(closest) {
+		closest[i] = math.Inf(1)
+	}
+	for centers.Len() < k {
+		cx, cy := centers.Take(centers.Len() - 1)
+		total := 0.
+		for i := range(closest) {
+			x, y := points.Take(i)
+			closest[i] = math.Min(closest[i], squaredDistance(x, y, cx, cy))
+			total += closest[i]
+		}
+		if total == 0 {
+			break
+		}
+		target, next := r.Float64() * total, n - 1
+		for i, d := range(closest) {
+			if target -= d; target < 0 {
+				next = i
+				break
+			}
+		}
+		centers = append(centers, points[2 * next], points[2 * next + 1])
+	}
+	for iteration := 0; iteration < iterations; iteration++ {
+		changed := iteration == 0
+		for i := range(groups) {
+			x, y := points.Take(i)
+			best, group := math.Inf(1), 0
+			for c := 0; c < centers.Len(); c++ {
+				cx, cy := centers.Take(c)
+				if d := squaredDistance(x, y, cx, cy); d < best {
+					best, group = d, c
+				}
+			}
+			if group != groups[i] {
+				groups[i], changed = group, true
+			}
+		}
+		if !changed {
+			break
+		}
+		sumX, sumY, counts := groupSums(points, groups)
+		for c, count := range(counts) {
+			if count > 0 {
+				centers[2 * c], centers[2 * c + 1] = sumX[c] / float64(count), sumY[c] / float64(count)
+			}
+		}
+	}
+	return groups
+}
+
+// Sum of the coordinates and number of the points of each group
+func groupSums (points FlatPoints, groups []int) (sumX, sumY []float64, counts []int) {
+	for i, g := range(groups) {
+		for len(counts) <= g {
+			sumX, sumY, counts = append(sumX, 0), append(sumY, 0), append(counts, 0)
+		}
+		sumX[g] += points[2 * i]
+		sumY[g] += points[2 * i + 1]
+		counts[g]++
+	}
+	return sumX, sumY, counts
+}
+
+// Index returning the point with the smallest squared distance divided by its relative weight
+type weightedIndex struct {
+	cellGrid
+	points FlatPoints
+	relative []float64
+	maxRelative float64
+	// points of cell i are those of indices[start[i]:start[i + 1]]
+	start []int
+	indices []int
+}
+
+func weightedIndexBuilder (centroids FlatPoints, weights []float64) SpatialIndexBuilder {
+	mean := 0.
+	for _, w := range(weights) {
+		mean += w / float64(len(weights))
+	}
+	relative := make(map[[2]float64]float64, len(weights))
+	for i, w := range(weights) {
+		x, y := centroids.Take(i)
+		relative[[2]float64{x, y}] = w / mean
+	}
+	return func (points FlatPoints) SpatialIndex {
+		n := points.Len()
+		minX, minY, maxX, maxY := bbox(points)
+		index := &weightedIndex{cellGrid: newCellGrid(minX, minY, maxX, maxY, math.Sqrt(float64(n))), points: points}
+		index.relative = make([]float64, n)
+		cells := make([]int, n)
+		index.start = make([]int, index.nx * index.ny + 1)
+		for i := 0; i < n; i++ {
+			x, y := points.Take(i)
+			index.relative[i] = relative[[2]float64{x, y}]
+			index.maxRelative = math.Max(index.maxRelative, index.relative[i])
+			cx, cy := index.cell(x, y)
+			cells[i] = cy * index.nx + cx
+			index.start[cells[i] + 1]++
+		}
+		for i := 1; i < len(index.start); i++ {
+			index.start[i] += index.start[i - 1]
+		}
+		next := append([]int{}, index.start[:len(index.start) - 1]...)
+		index.indices = make([]int, n)
+		for i, c := range(cells) {
+			index.indices[next[c]] = i
+			next[c]++
+		}
+		return index
+	}
+}
+
+func (w *weightedIndex) FindNearestPointWithin (x, y, maxSquaredDistance float64) (float64, float64, float64, bool) {
+	// the heaviest point within the weighted bound can be this far
+	reach := math.Sqrt(maxSquaredDistance * w.maxRelative)
+	ax, ay := w.cell(x - reach, y - reach)
+	bx, by := w.cell(x + reach, y + reach)
+	var px, py float64
+	best, found := maxSquaredDistance, false
+	for cy := ay; cy <= by; cy++ {
+		for cx := ax; cx <= bx; cx++ {
+			c := cy * w.nx + cx
+			for _, i := range(w.indices[w.start[c]:w.start[c + 1]]) {
+				if w.relative[i] == 0 {
+					continue
+				}
+				qx, qy := w.points.Take(i)
+				if d := squaredDistance(x, y, qx, qy) / w.relative[i]; d < best || !found && d <= best {
+					best, px, py, found = d, qx, qy, true
+				}
+			}
+		}
+	}
+	return px, py, best, found
+}
@@ -0,0 +1,78 @@
+package ConcaveHull
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestCentroids_grid (t *testing.T) {
+	points := FlatPoints{0.1, 0.1, 0.3, 0.5, 0.2, 0.3, 1.5, 0.5, 5.5, 5.5, 5.7, 5.1}
+	centroids, weights, err := Centroids(points, CentroidOptions{CellSize: 1})
+	assert.NoError(t, err)
+	assert.Len(t, weights, 3)
+	assert.InDelta(t, 0.2, centroids[0], 1e-12)
+	assert.InDelta(t, 0.3, centroids[1], 1e-12)
+	assert.Equal(t, []float64{3, 1, 2}, weights)
+
+	// the lone point is too revealing
+	centroids, weights, err = Centroids(points, CentroidOptions{CellSize: 1, MinWeight: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 2}, weights)
+	assert.Len(t, centroids, 4)
+
+	_, _, err = Centroids(points, CentroidOptions{})
+	assert.True(t, errors.Is(err, ErrInvalidOptions))
+}
+
+func TestCentroids_kMeans (t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	var points FlatPoints
+	for _, center := range([][2]float64{{0, 0}, {10, 0}, {5, 8}}) {
+		for i := 0; i < 100; i++ {
+			points = append(points, center[0] + r.NormFloat64() * 0.3, center[1] + r.NormFloat64() * 0.3)
+		}
+	}
+	centroids, weights, err := Centroids(points, CentroidOptions{Method: CentroidKMeans, K: 3, Seed: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{100, 100, 100}, weights)
+	assert.InDelta(t, 0, centroids[0], 0.2)
+	assert.InDelta(t, 5, centroids[2], 0.2)
+	assert.InDelta(t, 8, centroids[3], 0.2)
+	assert.InDelta(t, 10, centroids[4], 0.2)
+
+	again, _, _ := Centroids(points, CentroidOptions{Method: CentroidKMeans, K: 3, Seed: 1})
+	assert.Equal(t, centroids, again)
+	_, _, err = Centroids(points, CentroidOptions{Method: CentroidKMeans})
+	assert.True(t, errors.Is(err, ErrInvalidOptions))
+}
+
+func TestComputeCentroidHull (t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	points := FlatPoints(hulltest.Random(r, 5000))
+	hull, err := ComputeCentroidHull(context.Background(), points, CentroidOptions{CellSize: 0.05, MinWeight: 3}, &Options{Seglength: 0.05})
+	assert.NoError(t, err)
+	centroids, _, _ := Centroids(points, CentroidOptions{CellSize: 0.05, MinWeight: 3})
+	assert.True(t, hull.Points.Len() > 3)
+	for i := 0; i < hull.Points.Len(); i++ {
+		x, y := hull.Points.Take(i)
+		assert.True(t, ringContainsVertex(centroids, x, y))
+	}
+}
+
+func TestWeightedIndex (t *testing.T) {
+	centroids := FlatPoints{0, 0, 1, 0, 3, 0}
+	index := weightedIndexBuilder(centroids, []float64{1, 1, 10})(centroids)
+	// (3, 0) weighs 2.5 times the mean and the others a quarter of it, so (3, 0) is found though (1, 0) is closer
+	x, y, _, found := index.FindNearestPointWithin(1.9, 0, 4)
+	assert.True(t, found)
+	assert.Equal(t, []float64{3, 0}, []float64{x, y})
+	x, y, _, found = index.FindNearestPointWithin(1.2, 0, 4)
+	assert.True(t, found)
+	assert.Equal(t, []float64{1, 0}, []float64{x, y})
+	_, _, _, found = index.FindNearestPointWithin(10, 0, 1)
+	assert.False(t, found)
+}
@@ -0,0 +1,115 @@
+package ConcaveHull
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+var checkpointMagic = [4]byte{'C', 'H', 'K', '1'}
+
+// Edges of the convex hull refined by a computation with Options.CheckpointPath, saved to resume it after an interruption
+type checkpoint struct {
+	path string
+	interval time.Duration
+	// fingerprint of the points and the options, so that a checkpoint of another computation is not resumed
+	key string
+	saved time.Time
+}
+
+// Checkpoint of the computation of the hull of the sorted points, nil without Options.CheckpointPath. Limits on the
+// refinement are left out of the key, so a computation stopped by TimeBudget or MaxProbes can be resumed with other limits.
+// Options with hooks cannot be fingerprinted, a checkpoint of a computation with other hooks would be resumed, so there is
+// no checkpoint and an error
+func newCheckpoint (points FlatPoints, o *Options) (*checkpoint, error) {
+	if o == nil || o.CheckpointPath == "" {
+		return nil, nil
+	}
+	keyed := *o
+	keyed.TimeBudget, keyed.MaxProbes = 0, 0
+	key, ok := fingerprint(points, &keyed)
+	if !ok {
+		return nil, errors.New("not saved: options with hooks such as Metric or SpatialIndex cannot be identified")
+	}
+	return &checkpoint{path: o.CheckpointPath, interval: o.CheckpointInterval, key: key}, nil
+}
+
+// Refined sides of the n edges of the convex hull, nil for the edges to refine, and the stats of the interrupted
+// computation. No file is not an error
+func (cp *checkpoint) load (n int) (sides [][]float64, stats SegmentizeStats, err error) {
+	data, err := os.ReadFile(cp.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, stats, nil
+	}
+	if err != nil {
+		return nil, stats, err
+	}
+	if len(data) < len(checkpointMagic) || [4]byte(data[:4]) != checkpointMagic {
+		return nil, stats, ErrInvalidEncoding
+	}
+	r := binaryReader{data: data[4:]}
+	key := make([]byte, r.length(1))
+	if r.err == nil {
+		r.data = r.data[copy(key, r.data):]
+	}
+	if r.err == nil && string(key) != cp.key {
+		return nil, stats, fmt.Errorf("%s was saved by a computation of other points or options", cp.path)
+	}
+	stats.Probes, stats.MaxStack = int(r.uint64()), int(r.uint64())
+	sides = make([][]float64, n)
+	for count := r.length(16); r.err == nil && count > 0; count-- {
+		edge := r.uint64()
+		side := r.floats()
+		if r.err == nil && (edge >= uint64(n) || len(side) < 2 || len(side) % 2 != 0) {
+			r.err = ErrInvalidEncoding
+		}
+		if r.err == nil {
+			sides[edge] = side
+		}
+	}
+	if r.err != nil {
+		return nil, SegmentizeStats{}, r.err
+	}
+	return sides, stats, nil
+}
+
+// Save the refined sides unless the interval since the last save hasn't elapsed, or whatever the interval if force is set
+func (cp *checkpoint) save (sides [][]float64, stats SegmentizeStats, force bool) error {
+	now := time.Now()
+	if !force && now.Sub(cp.saved) < cp.interval {
+		return nil
+	}
+	data := append([]byte{}, checkpointMagic[:]...)
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(cp.key)))
+	data = append(data, cp.key...)
+	data = binary.LittleEndian.AppendUint64(data, uint64(stats.Probes))
+	data = binary.LittleEndian.AppendUint64(data, uint64(stats.MaxStack))
+	refined := 0
+	for _, side := range(sides) {
+		if side != nil {
+			refined++
+		}
+	}
+	data = binary.LittleEndian.AppendUint64(data, uint64(refined))
+	for edge, side := range(sides) {
+		if side != nil {
+			data = binary.LittleEndian.AppendUint64(data, uint64(edge))
+			data = appendFloats(data, side)
+		}
+	}
+	if err := writeFileAtomic(cp.path, data, 0644); err != nil {
+		return err
+	}
+	cp.saved = now
+	return nil
+}
+
+// Remove the checkpoint of a completed computation
+func (cp *checkpoint) remove () error {
+	if err := os.Remove(cp.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestComputeContext_checkpoint (t *testing.T) {
+	r := rand.New(rand.NewSource(31))
+	points := hulltest.Grid(r, 2000, 100)
+	full, err := ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 0.001})
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "hull.checkpoint")
+	// interrupted by the probe budget, the refined edges are saved
+	interrupted, err := ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 0.001, MaxProbes: 100, CheckpointPath: path})
+	assert.NoError(t, err)
+	assert.True(t, interrupted.Partial)
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+
+	// other options don't resume it
+	other, err := ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 0.002, MaxProbes: 100, CheckpointPath: path})
+	assert.NoError(t, err)
+	assert.Equal(t, WarningCheckpoint, other.Warnings[0].Kind)
+
+	interrupted, err = ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 0.001, MaxProbes: 100, CheckpointPath: path})
+	assert.NoError(t, err)
+	assert.True(t, interrupted.Partial)
+	resumed, err := ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 0.001, CheckpointPath: path})
+	assert.NoError(t, err)
+	assert.False(t, resumed.Partial)
+	assert.Len(t, resumed.Warnings, 0)
+	assert.Equal(t, full.Points, resumed.Points)
+	assert.Equal(t, full.Stats.Probes, resumed.Stats.Probes)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCheckpoint_invalid (t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hull.checkpoint")
+	assert.NoError(t, os.WriteFile(path, []byte("CHK1garbage"), 0644))
+	points := FlatPoints{0, 0, 0, 1, 1, 0, 1, 1}
+	hull, err := ComputeContext(context.Background(), points, &Options{CheckpointPath: path})
+	assert.NoError(t, err)
+	assert.Len(t, hull.Warnings, 1)
+	assert.Equal(t, WarningCheckpoint, hull.Warnings[0].Kind)
+	assert.Equal(t, FlatPoints{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}, hull.Points)
+}
+
+func TestComputeContext_checkpointRepeatedBudget (t *testing.T) {
+	r := rand.New(rand.NewSource(37))
+	points := hulltest.Grid(r, 2000, 100)
+	full, err := ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 0.001})
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "hull.checkpoint")
+	// each run refines at least one edge, so the runs end before the edges of the convex hull are exhausted
+	var hull Hull
+	runs := 0
+	for hull.Partial || runs == 0 {
+		hull, err = ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 0.001, MaxProbes: 100, CheckpointPath: path})
+		assert.NoError(t, err)
+		runs++
+		if !assert.True(t, runs <= 200) {
+			return
+		}
+	}
+	assert.True(t, runs > 1)
+	assert.Equal(t, full.Points, hull.Points)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestComputeContext_checkpointHooks (t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hull.checkpoint")
+	points := hulltest.Grid(rand.New(rand.NewSource(41)), 500, 100)
+	hull, err := ComputeContext(context.Background(), points, &Options{Seglength: 0.001, MaxProbes: 10, CheckpointPath: path, Metric: EuclideanMetric{}})
+	assert.NoError(t, err)
+	assert.True(t, hull.Partial)
+	assert.Equal(t, WarningCheckpoint, hull.Warnings[0].Kind)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,43 @@
+package ConcaveHull
+
+import "github.com/USACE/concavehull/geomutil"
+
+// Where a point is relative to a hull
+type Location uint8
+
+const (
+	LocationExterior Location = iota
+	LocationBoundary // within the tolerance of an edge
+	LocationInterior
+)
+
+func (l Location) String () string {
+	switch l {
+	case LocationExterior:
+		return "exterior"
+	case LocationBoundary:
+		return "boundary"
+	case LocationInterior:
+		return "interior"
+	}
+	return "unknown"
+}
+
+// Location of (x, y) relative to the hull. Points within tolerance of the boundary are LocationBoundary whichever side they are
+// on, so a tolerance of 0 only classifies points exactly on an edge as such
+func (h Hull) Classify (x, y, tolerance float64) Location {
+	ring := openRing(h.Points)
+	if _, _, _, d := closestOnRing(ring, x, y); d <= tolerance * tolerance {
+		return LocationBoundary
+	}
+	if ringContains(ring, x, y) {
+		return LocationInterior
+	}
+	return LocationExterior
+}
+
+// Closest point to (x, y) on the edges of an open ring, the index of its edge, from vertex edge to the next one, and the squared
+// distance. The distance is infinite for an empty ring
+func closestOnRing (ring FlatPoints, x, y float64) (px, py float64, edge int, squared float64) {
+	return geomutil.ClosestOnRing(ring, x, y)
+}
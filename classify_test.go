@@ -0,0 +1,25 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHull_Classify (t *testing.T) {
+	// square with a notch down to (1, 1)
+	hull := Hull{Points: FlatPoints{0, 0, 2, 0, 2, 2, 1, 1, 0, 2, 0, 0}}
+	assert.Equal(t, LocationInterior, hull.Classify(1, 0.5, 0))
+	assert.Equal(t, LocationExterior, hull.Classify(1, 1.5, 0))
+	assert.Equal(t, LocationExterior, hull.Classify(3, 1, 0))
+	assert.Equal(t, LocationBoundary, hull.Classify(1, 0, 0))
+	assert.Equal(t, LocationBoundary, hull.Classify(1.5, 1.5, 0))
+	assert.Equal(t, LocationBoundary, hull.Classify(2, 2, 0))
+
+	// inside and outside within the tolerance
+	assert.Equal(t, LocationBoundary, hull.Classify(1, 0.05, 0.1))
+	assert.Equal(t, LocationBoundary, hull.Classify(1, -0.05, 0.1))
+	assert.Equal(t, LocationInterior, hull.Classify(1, 0.5, 0.1))
+	assert.Equal(t, "boundary", LocationBoundary.String())
+
+	assert.Equal(t, LocationExterior, Hull{}.Classify(0, 0, 1))
+}
@@ -0,0 +1,181 @@
+package ConcaveHull
+
+import "math"
+
+// Post processing of the outline requested in the options, applied to the hull and to every level
+func finishRing (ring FlatPoints, o *Options) FlatPoints {
+	if o == nil {
+		return ring
+	}
+	if o.RemoveSpikes {
+		ring = RemoveSpikes(ring, o.SpikeTolerance)
+	}
+	if o.MinAngle > 0 {
+		ring = EnforceMinAngle(ring, o.MinAngle)
+	}
+	return ring
+}
+
+// Remove repeated vertices, zero area spikes and collinear vertices from a closed ring. A vertex is removed when it is
+// within tolerance of the line through its neighbours, which covers both a vertex in the middle of a straight run and the tip
+// of a spike that goes out and back along the same line. Rings that would collapse below three vertices are returned unchanged
+func RemoveSpikes (ring FlatPoints, tolerance float64) FlatPoints {
+	open := openRing(ring)
+	n := open.Len()
+	if n < 3 {
+		return ring
+	}
+	kept := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		kept = append(kept, i)
+		// removing a vertex can make the previous one removable
+		for len(kept) >= 3 && removableVertex(open, kept[len(kept) - 3], kept[len(kept) - 2], kept[len(kept) - 1], tolerance) {
+			kept = append(kept[:len(kept) - 2], kept[len(kept) - 1])
+		}
+	}
+	// vertices around the start of the ring
+	for changed := true; changed && len(kept) >= 3; {
+		changed = false
+		m := len(kept)
+		if removableVertex(open, kept[m - 2], kept[m - 1], kept[0], tolerance) {
+			kept = kept[:m - 1]
+			changed = true
+		} else if removableVertex(open, kept[m - 1], kept[0], kept[1], tolerance) {
+			kept = kept[1:]
+			changed = true
+		}
+	}
+	if len(kept) < 3 {
+		return ring
+	}
+	result := make(FlatPoints, 0, 2 * len(kept) + 2)
+	for _, i := range(kept) {
+		result = append(result, open[2 * i], open[2 * i + 1])
+	}
+	return append(result, result[0], result[1])
+}
+
+// Merge vertices of a closed ring closer than tolerance to the previous kept vertex, including the last and the first, which
+// removes the near duplicates that strict validators reject. The first vertex of each run is kept, so vertices stay where
+// they were. Rings that would collapse below three vertices are returned unchanged
+func WeldVertices (ring FlatPoints, tolerance float64) FlatPoints {
+	open := openRing(ring)
+	n := open.Len()
+	if n < 3 {
+		return ring
+	}
+	squared := tolerance * tolerance
+	welded := make(FlatPoints, 0, len(open) + 2)
+	welded = append(welded, open[0], open[1])
+	for i := 1; i < n; i++ {
+		x, y := open.Take(i)
+		if squaredDistance(welded[len(welded) - 2], welded[len(welded) - 1], x, y) > squared {
+			welded = append(welded, x, y)
+		}
+	}
+	for welded.Len() > 1 && squaredDistance(welded[len(welded) - 2], welded[len(welded) - 1], welded[0], welded[1]) <= squared {
+		welded = welded[:len(welded) - 2]
+	}
+	if welded.Len() < 3 {
+		return ring
+	}
+	return append(welded, welded[0], welded[1])
+}
+
+func removableVertex (ring FlatPoints, previous, current, next int, tolerance float64) bool {
+	px, py := ring.Take(previous)
+	cx, cy := ring.Take(current)
+	nx, ny := ring.Take(next)
+	if (cx == px && cy == py) || (cx == nx && cy == ny) {
+		return true
+	}
+	base := math.Hypot(nx - px, ny - py)
+	if base == 0 {
+		// spike going out and coming back to the same point
+		return true
+	}
+	return math.Abs(orientation(px, py, cx, cy, nx, ny)) / base <= tolerance
+}
+
+// Widen the angles of a closed ring that are sharper than minAngle, in radians, by removing reflex vertices. The tip of an
+// inward needle is removed itself, an outward needle loses its reflex neighbours. Removing reflex vertices only grows the
+// polygon, so the ring keeps containing whatever it contained, and removals that would make it self intersect are skipped.
+// Sharp convex corners without reflex neighbours can't be widened this way and are kept
+func EnforceMinAngle (ring FlatPoints, minAngle float64) FlatPoints {
+	open := openRing(ring)
+	n := open.Len()
+	if n < 4 {
+		return ring
+	}
+	// +1 if the ring is counter clockwise, so that convex vertices turn left
+	sign := 1.
+	if SignedArea(open) < 0 {
+		sign = -1
+	}
+	vertices := make([]int, n)
+	for i := range(vertices) {
+		vertices[i] = i
+	}
+	turn := func (i int) float64 {
+		m := len(vertices)
+		px, py := open.Take(vertices[(i + m - 1) % m])
+		cx, cy := open.Take(vertices[i])
+		nx, ny := open.Take(vertices[(i + 1) % m])
+		return sign * orientation(px, py, cx, cy, nx, ny)
+	}
+	angle := func (i int) float64 {
+		m := len(vertices)
+		px, py := open.Take(vertices[(i + m - 1) % m])
+		cx, cy := open.Take(vertices[i])
+		nx, ny := open.Take(vertices[(i + 1) % m])
+		return math.Abs(math.Atan2(orientation(cx, cy, px, py, nx, ny), (px - cx) * (nx - cx) + (py - cy) * (ny - cy)))
+	}
+	for changed := true; changed && len(vertices) > 3; {
+		changed = false
+		for i := 0; i < len(vertices) && len(vertices) > 3; i++ {
+			if angle(i) >= minAngle {
+				continue
+			}
+			m := len(vertices)
+			candidates := []int{i}
+			if turn(i) > 0 {
+				candidates = []int{(i + m - 1) % m, (i + 1) % m}
+			}
+			for _, j := range(candidates) {
+				if turn(j) < 0 && removalKeepsSimple(open, vertices, j) {
+					vertices = append(vertices[:j], vertices[j + 1:]...)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	if len(vertices) == n {
+		return ring
+	}
+	result := make(FlatPoints, 0, 2 * len(vertices) + 2)
+	for _, i := range(vertices) {
+		result = append(result, open[2 * i], open[2 * i + 1])
+	}
+	return append(result, result[0], result[1])
+}
+
+// Whether the edge that replaces vertex j doesn't touch the rest of the ring
+func removalKeepsSimple (ring FlatPoints, vertices []int, j int) bool {
+	m := len(vertices)
+	previous, next := (j + m - 1) % m, (j + 1) % m
+	ax, ay := ring.Take(vertices[previous])
+	bx, by := ring.Take(vertices[next])
+	for k := 0; k < m; k++ {
+		l := (k + 1) % m
+		if k == previous || k == j || k == next || l == previous {
+			continue
+		}
+		cx, cy := ring.Take(vertices[k])
+		dx, dy := ring.Take(vertices[l])
+		if segmentsTouch(ax, ay, bx, by, cx, cy, dx, dy) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestRemoveSpikes (t *testing.T) {
+	// square with a repeated vertex, a collinear vertex, a spike and a collinear vertex at the start
+	ring := FlatPoints{0.5, 0, 1, 0, 1, 0, 1, 0.5, 1, 1, 2, 1, 1, 1, 0, 1, 0, 0, 0.5, 0}
+	assert.Equal(t, FlatPoints{1, 0, 1, 1, 0, 1, 0, 0, 1, 0}, RemoveSpikes(ring, 0))
+	// within tolerance
+	ring = FlatPoints{0, 0, 0.5, 0.01, 1, 0, 1, 1, 0, 1, 0, 0}
+	assert.Equal(t, ring, RemoveSpikes(ring, 0.001))
+	assert.Equal(t, FlatPoints{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}, RemoveSpikes(ring, 0.02))
+	// degenerate rings are left alone
+	ring = FlatPoints{0, 0, 1, 0, 2, 0, 0, 0}
+	assert.Equal(t, ring, RemoveSpikes(ring, 0))
+}
+
+func TestComputeWithOptions_removeSpikes (t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	points := hulltest.Grid(r, 400, 20)
+	input := append([]float64{}, points...)
+	hull := ComputeWithOptions(FlatPoints(points), &Options{Seglength: 0.01, RemoveSpikes: true})
+	hulltest.AssertValid(t, input, hull)
+	open := openRing(hull)
+	for i := 0; i < open.Len(); i++ {
+		assert.False(t, removableVertex(open, (i + open.Len() - 1) % open.Len(), i, (i + 1) % open.Len(), 0))
+	}
+}
+
+func TestEnforceMinAngle (t *testing.T) {
+	// square with a needle going inward from the top edge
+	ring := FlatPoints{0, 0, 1, 0, 1, 1, 0.51, 1, 0.5, 0.1, 0.49, 1, 0, 1, 0, 0}
+	assert.Equal(t, FlatPoints{0, 0, 1, 0, 1, 1, 0.51, 1, 0.49, 1, 0, 1, 0, 0}, EnforceMinAngle(ring, 0.1))
+	// same ring clockwise
+	assert.Equal(t, reverseRing(FlatPoints{0, 0, 1, 0, 1, 1, 0.51, 1, 0.49, 1, 0, 1, 0, 0}), EnforceMinAngle(reverseRing(ring), 0.1))
+	// outward needle, its reflex base vertices are removed
+	ring = FlatPoints{0, 0, 1, 0, 1, 1, 0.6, 1, 0.5, 3, 0.4, 1, 0, 1, 0, 0}
+	assert.Equal(t, FlatPoints{0, 0, 1, 0, 1, 1, 0.5, 3, 0, 1, 0, 0}, EnforceMinAngle(ring, 0.35))
+	// nothing sharp
+	assert.Equal(t, ring, EnforceMinAngle(ring, 0.01))
+}
+
+func TestComputeWithOptions_minAngle (t *testing.T) {
+	r := rand.New(rand.NewSource(14))
+	points := hulltest.Ring(r, 1000, 0.3, 0.5)
+	input := append([]float64{}, points...)
+	hull := ComputeWithOptions(FlatPoints(points), &Options{Seglength: 0.01, MinAngle: 0.3})
+	hulltest.AssertValid(t, input, hull)
+}
+
+func TestWeldVertices (t *testing.T) {
+	// near duplicates in the middle and across the start of the ring
+	ring := FlatPoints{0, 0, 1, 0, 1.001, 0.001, 1, 1, 0, 1, 0.0005, 0, 0, 0}
+	assert.Equal(t, FlatPoints{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}, WeldVertices(ring, 0.01))
+	assert.Equal(t, ring, WeldVertices(ring, 0.0001))
+	// rings that would collapse are left alone
+	ring = FlatPoints{0, 0, 0.001, 0, 0, 0.001, 0, 0}
+	assert.Equal(t, ring, WeldVertices(ring, 0.01))
+
+	points := FlatPoints(hulltest.Random(rand.New(rand.NewSource(5)), 3000))
+	hull, err := ComputeContext(context.Background(), points, &Options{SeglengthRelative: 0.002, WeldTolerance: 0.02})
+	assert.NoError(t, err)
+	open := openRing(hull.Points)
+	for i := 0; i < open.Len(); i++ {
+		x1, y1 := open.Take(i)
+		x2, y2 := open.Take((i + 1) % open.Len())
+		assert.True(t, squaredDistance(x1, y1, x2, y2) > 0.02 * 0.02)
+	}
+}
@@ -0,0 +1,30 @@
+package ConcaveHull
+
+// Axis aligned box, bounds included, see Options.ClipInputBBox
+type BBox struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+func (b BBox) Contains (x, y float64) bool {
+	return x >= b.MinX && x <= b.MaxX && y >= b.MinY && y <= b.MaxY
+}
+
+// Points inside Options.ClipInputBBox, moved to the front of the slice in their original order, the others follow in any order.
+// Returns that prefix of points, or points itself if the option is not set
+func clipInput (points FlatPoints, o *Options) FlatPoints {
+	if o == nil || o.ClipInputBBox == nil {
+		return points
+	}
+	box := *o.ClipInputBBox
+	kept := 0
+	for i := 0; i < points.Len(); i++ {
+		x, y := points.Take(i)
+		if !box.Contains(x, y) {
+			continue
+		}
+		points[2 * i], points[2 * kept] = points[2 * kept], x
+		points[2 * i + 1], points[2 * kept + 1] = points[2 * kept + 1], y
+		kept++
+	}
+	return points[:2 * kept]
+}
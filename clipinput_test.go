@@ -0,0 +1,57 @@
+package ConcaveHull
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestClipInput (t *testing.T) {
+	points := FlatPoints{5, 5, 0, 0, 2, 1, -1, 0, 1, 2}
+	kept := clipInput(points, &Options{ClipInputBBox: &BBox{0, 0, 2, 2}})
+	assert.Equal(t, FlatPoints{0, 0, 2, 1, 1, 2}, kept)
+	assert.Equal(t, FlatPoints{0, 0, 2, 1, 1, 2, -1, 0, 5, 5}, points)
+	assert.Equal(t, points, clipInput(points, nil))
+}
+
+func TestComputeContext_clipInputBBox (t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	points := FlatPoints(hulltest.Grid(r, 900, 30))
+	box := BBox{0.2, 0.2, 0.7, 0.6}
+	var region FlatPoints
+	for i := 0; i < points.Len(); i++ {
+		if x, y := points.Take(i); box.Contains(x, y) {
+			region = append(region, x, y)
+		}
+	}
+	expected := ComputeWithOptions(append(FlatPoints{}, region...), &Options{Seglength: 0.05})
+	hull, err := ComputeContext(context.Background(), append(FlatPoints{}, points...), NewOptions(WithSeglength(0.05), WithClipInputBBox(box)))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, hull.Points)
+	assert.Len(t, hull.Dropped, 0)
+	assert.Equal(t, expected, ComputeWithOptions(points, &Options{Seglength: 0.05, ClipInputBBox: &box}))
+
+	// the box is in input coordinates, not transformed ones
+	o := &Options{Seglength: 0.05, ClipInputBBox: &box, Transform: &Affine{A: 2, E: 2}}
+	hull, err = ComputeContext(context.Background(), points, o)
+	assert.NoError(t, err)
+	hulltest.AssertValid(t, region, hull.Points)
+
+	_, err = ComputeContext(context.Background(), points, &Options{ClipInputBBox: &BBox{1, 0, 0, 1}})
+	assert.True(t, errors.Is(err, ErrInvalidOptions))
+}
+
+func TestFingerprint_clipInputBBox (t *testing.T) {
+	points := FlatPoints{0, 0, 1, 0, 1, 1}
+	a, ok := fingerprint(points, &Options{ClipInputBBox: &BBox{0, 0, 1, 1}})
+	assert.True(t, ok)
+	b, _ := fingerprint(points, &Options{ClipInputBBox: &BBox{0, 0, 1, 1}})
+	c, _ := fingerprint(points, &Options{ClipInputBBox: &BBox{0, 0, 2, 1}})
+	d, _ := fingerprint(points, &Options{})
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+	assert.NotEqual(t, a, d)
+}
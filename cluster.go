@@ -0,0 +1,179 @@
+package ConcaveHull
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Hull of one of the clusters found by ComputeClusters
+type ClusterHull struct {
+	Hull
+	// Indices of the points of the cluster in the input
+	Indices []int
+}
+
+// Split the points in clusters, linking points that are closer than Options.ClusterDistance, and compute the concave hull of each of them.
+// Clusters with fewer than Options.MinPoints points are dropped, unless Options.KeepSmallClusters is set, in which case their hull
+// is the single point or the segment between their two farthest points, so that noise does not produce sliver polygons.
+// With Options.Workers, that many hulls are computed concurrently. Unlike Compute, the input is not modified
+func ComputeClusters (points FlatPoints, o *Options) []ClusterHull {
+	o = o.snapshot()
+	var distance float64
+	var minPoints, workers int
+	var keepSmall bool
+	if o != nil {
+		distance, minPoints, keepSmall, workers = o.ClusterDistance, o.MinPoints, o.KeepSmallClusters, o.Workers
+	}
+	var hulls []ClusterHull
+	var clusterPoints []FlatPoints
+	for _, indices := range(clusterIndices(points, distance)) {
+		// sorted copy of the points of the cluster
+		sort.Slice(indices, func (i, j int) bool {
+			xi, yi := points.Take(indices[i])
+			xj, yj := points.Take(indices[j])
+			return xi < xj || xi == xj && yi < yj
+		})
+		sorted := make(FlatPoints, 0, 2 * len(indices))
+		for _, i := range(indices) {
+			sorted = append(sorted, points[2 * i], points[2 * i + 1])
+		}
+		if len(indices) < minPoints {
+			if keepSmall {
+				hulls = append(hulls, ClusterHull{Hull: Hull{Points: farthestPair(sorted)}, Indices: indices})
+				clusterPoints = append(clusterPoints, nil)
+			}
+			continue
+		}
+		hulls = append(hulls, ClusterHull{Indices: indices})
+		clusterPoints = append(clusterPoints, sorted)
+	}
+	compute := func (k int) {
+		hull := computeFromSortedWithContext(nil, clusterPoints[k], o)
+		for j, d := range(hull.Dropped) {
+			hull.Dropped[j] = hulls[k].Indices[d]
+		}
+		if hull.Provenance == nil {
+			hull.Provenance = make([]VertexKind, hull.Points.Len())
+		}
+		hulls[k].Hull = hull
+	}
+	forEachConcurrently(len(hulls), workers, func (k int) {
+		if clusterPoints[k] != nil {
+			compute(k)
+		}
+	})
+	return hulls
+}
+
+// Call f for each index from 0 to n - 1, on that many goroutines if workers > 1, each taking the next index until there are none left.
+// A panic is raised again on the calling goroutine
+func forEachConcurrently (n, workers int, f func (k int)) {
+	if workers <= 1 {
+		for k := 0; k < n; k++ {
+			f(k)
+		}
+		return
+	}
+	var next int64 = -1
+	var wg sync.WaitGroup
+	var workerPanic interface{}
+	var panicOnce sync.Once
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func () {
+			defer func () {
+				if r := recover(); r != nil {
+					panicOnce.Do(func () { workerPanic = r })
+				}
+				wg.Done()
+			}()
+			for k := int(atomic.AddInt64(&next, 1)); k < n; k = int(atomic.AddInt64(&next, 1)) {
+				f(k)
+			}
+		}()
+	}
+	wg.Wait()
+	if workerPanic != nil {
+		panic(workerPanic)
+	}
+}
+
+// Single linkage clustering: indices of the points of each connected component of the graph linking points closer than distance.
+// Clusters are ordered by their lowest index
+func clusterIndices (points FlatPoints, distance float64) [][]int {
+	n := points.Len()
+	parent := make([]int, n)
+	for i := range(parent) {
+		parent[i] = i
+	}
+	var find func (i int) int
+	find = func (i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	if distance > 0 {
+		cells := make(map[[2]int][]int)
+		cellOf := func (x, y float64) [2]int {
+			return [2]int{int(math.Floor(x / distance)), int(math.Floor(y / distance))}
+		}
+		for i := 0; i < n; i++ {
+			x, y := points.Take(i)
+			cell := cellOf(x, y)
+			for dx := -1; dx <= 1; dx++ {
+				for dy := -1; dy <= 1; dy++ {
+					for _, j := range(cells[[2]int{cell[0] + dx, cell[1] + dy}]) {
+						xj, yj := points.Take(j)
+						if (x - xj) * (x - xj) + (y - yj) * (y - yj) < distance * distance {
+							if ri, rj := find(i), find(j); ri != rj {
+								parent[ri] = rj
+							}
+						}
+					}
+				}
+			}
+			cells[cell] = append(cells[cell], i)
+		}
+	} else {
+		for i := range(parent) {
+			parent[i] = 0
+		}
+	}
+	var clusters [][]int
+	clusterOf := make(map[int]int)
+	for i := 0; i < n; i++ {
+		root := find(i)
+		c, ok := clusterOf[root]
+		if !ok {
+			c = len(clusters)
+			clusterOf[root] = c
+			clusters = append(clusters, nil)
+		}
+		clusters[c] = append(clusters[c], i)
+	}
+	return clusters
+}
+
+// The two points of the set that are the farthest apart, or the single point if they are all the same
+func farthestPair (points FlatPoints) FlatPoints {
+	best, bi, bj := -1., 0, 0
+	for i := 0; i < points.Len(); i++ {
+		xi, yi := points.Take(i)
+		for j := i + 1; j < points.Len(); j++ {
+			xj, yj := points.Take(j)
+			if d := (xi - xj) * (xi - xj) + (yi - yj) * (yi - yj); d > best {
+				best, bi, bj = d, i, j
+			}
+		}
+	}
+	x1, y1 := points.Take(bi)
+	if best <= 0 {
+		return FlatPoints{x1, y1}
+	}
+	x2, y2 := points.Take(bj)
+	return FlatPoints{x1, y1, x2, y2}
+}
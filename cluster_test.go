@@ -0,0 +1,40 @@
+package ConcaveHull
+
+import (
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeClusters (t *testing.T) {
+	points := FlatPoints{
+		0, 0, 1, 0, 1, 1, 0, 1,
+		10, 10, 11, 10, 11, 11, 10, 11,
+		20, 20, 20.5, 20,
+		30, 30,
+	}
+	clusters := ComputeClusters(points, &Options{ClusterDistance: 2, MinPoints: 3})
+	assert.Len(t, clusters, 2)
+	assert.Equal(t, []int{0, 3, 1, 2}, clusters[0].Indices)
+	assert.Equal(t, FlatPoints{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}, clusters[0].Points)
+	assert.Equal(t, FlatPoints{10, 10, 11, 10, 11, 11, 10, 11, 10, 10}, clusters[1].Points)
+
+	clusters = ComputeClusters(points, &Options{ClusterDistance: 2, MinPoints: 3, KeepSmallClusters: true})
+	assert.Len(t, clusters, 4)
+	assert.Equal(t, FlatPoints{20, 20, 20.5, 20}, clusters[2].Points)
+	assert.Equal(t, FlatPoints{30, 30}, clusters[3].Points)
+}
+
+func TestComputeClusters_workers (t *testing.T) {
+	var points FlatPoints
+	for c := 0; c < 20; c++ {
+		for i := 0; i < 50; i++ {
+			angle := float64(i) * 0.7
+			points = append(points, float64(c * 10) + math.Cos(angle) * float64(i % 7) / 7, math.Sin(angle) * float64(i % 5) / 5)
+		}
+	}
+	sequential := ComputeClusters(points, &Options{ClusterDistance: 2, MinPoints: 3, Seglength: 0.1})
+	concurrent := ComputeClusters(points, &Options{ClusterDistance: 2, MinPoints: 3, Seglength: 0.1, Workers: 4})
+	assert.Len(t, concurrent, 20)
+	assert.Equal(t, sequential, concurrent)
+}
@@ -0,0 +1,34 @@
+package ConcaveHull
+
+import "fmt"
+
+// Points stored as separate columns of x and y, as in columnar stores and dataframes. Extra values of the longer column
+// are ignored
+type ColumnPoints struct {
+	X, Y []float64
+}
+
+func (c ColumnPoints) Len () int {
+	if len(c.Y) < len(c.X) {
+		return len(c.Y)
+	}
+	return len(c.X)
+}
+
+func (c ColumnPoints) Take (i int) (x, y float64) {
+	return c.X[i], c.Y[i]
+}
+
+// Concave hull of points given as columns, returned as columns of the closed ring, see ComputeSource. The columns are left
+// untouched, and columns of different lengths are reported as ErrMalformedPoints
+func ComputeColumns (xs, ys []float64, o *Options) (hullXs, hullYs []float64, err error) {
+	if len(xs) != len(ys) {
+		return nil, nil, fmt.Errorf("%w: %d x and %d y", ErrMalformedPoints, len(xs), len(ys))
+	}
+	hull := ComputeSource(ColumnPoints{X: xs, Y: ys}, o)
+	hullXs, hullYs = make([]float64, hull.Len()), make([]float64, hull.Len())
+	for i := range(hullXs) {
+		hullXs[i], hullYs[i] = hull.Take(i)
+	}
+	return hullXs, hullYs, nil
+}
@@ -0,0 +1,25 @@
+package ConcaveHull
+
+import (
+	"errors"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeColumns (t *testing.T) {
+	xs := []float64{1./3., 0, 1, 0, 1}
+	ys := []float64{0.5, 0, 0, 1, 1}
+	hullXs, hullYs, err := ComputeColumns(xs, ys, nil)
+	assert.NoError(t, err)
+	expected := ComputeWithOptions(FlatPoints{1./3., 0.5, 0.0, 0.0, 1.0, 0.0, 0.0, 1.0, 1.0, 1.0}, nil)
+	assert.Len(t, hullXs, expected.Len())
+	for i := range(hullXs) {
+		x, y := expected.Take(i)
+		assert.Equal(t, []float64{x, y}, []float64{hullXs[i], hullYs[i]})
+	}
+	assert.Equal(t, []float64{1./3., 0, 1, 0, 1}, xs)
+
+	_, _, err = ComputeColumns(xs, ys[:4], nil)
+	assert.True(t, errors.Is(err, ErrMalformedPoints))
+	assert.Equal(t, 4, ColumnPoints{X: xs, Y: ys[:4]}.Len())
+}
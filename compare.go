@@ -0,0 +1,61 @@
+package ConcaveHull
+
+import (
+	"context"
+	"time"
+)
+
+// Hull computed with one of the configurations of Compare, with measures to choose between them
+type Comparison struct {
+	Options *Options
+	Hull Hull
+	Err error
+	Duration time.Duration
+	Vertices int
+	// Fraction of the points inside or on the hull
+	Coverage float64
+	Descriptors ShapeDescriptors
+}
+
+// Compute the hull of the points with each configuration, sharing the sorting, convex hull and index, see Concaver.Compare.
+// The input is not modified
+func Compare (points FlatPoints, configs []*Options) []Comparison {
+	p := Prepare(points)
+	defer p.Close()
+	return p.Compare(context.Background(), configs)
+}
+
+// Compute the hull of the prepared points with each configuration, in order. Results are aligned with configs and a failed
+// configuration reports its error in Comparison.Err, the others still run. Cancelling ctx fails the remaining configurations
+func (p *Concaver) Compare (ctx context.Context, configs []*Options) []Comparison {
+	comparisons := make([]Comparison, len(configs))
+	for i, o := range(configs) {
+		start := time.Now()
+		hull, err := p.ComputeContext(ctx, o)
+		comparisons[i] = Comparison{Options: o, Hull: hull, Err: err, Duration: time.Since(start)}
+		if err != nil {
+			continue
+		}
+		comparisons[i].Vertices = openRing(hull.Points).Len()
+		comparisons[i].Coverage = p.coverage(hull.Points)
+		comparisons[i].Descriptors = Descriptors(hull.Points)
+	}
+	return comparisons
+}
+
+// Fraction of the prepared points inside or on the ring
+func (p *Concaver) coverage (ring FlatPoints) float64 {
+	n := p.sorted.Len()
+	if n == 0 {
+		return 0
+	}
+	closed := closeRing(ring)
+	covered := 0
+	for i := 0; i < n; i++ {
+		x, y := p.sorted.Take(i)
+		if ringContains(closed, x, y) || onRing(closed, x, y) {
+			covered++
+		}
+	}
+	return float64(covered) / float64(n)
+}
@@ -0,0 +1,37 @@
+package ConcaveHull
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestCompare (t *testing.T) {
+	r := rand.New(rand.NewSource(25))
+	points := FlatPoints(hulltest.Ring(r, 1000, 0.3, 0.5))
+	input := append(FlatPoints{}, points...)
+	configs := []*Options{
+		{Seglength: 0.01},
+		{Seglength: 0.05},
+		{Algorithm: AlgorithmEdgeLength, EdgeLengthRatio: 0.3},
+		{Seglength: math.NaN()},
+	}
+	comparisons := Compare(points, configs)
+	assert.Equal(t, input, points)
+	assert.Len(t, comparisons, 4)
+	expected, _ := ComputeContext(context.Background(), append(FlatPoints{}, points...), configs[0])
+	assert.Equal(t, expected.Points, comparisons[0].Hull.Points)
+	assert.Equal(t, configs[0], comparisons[0].Options)
+	assert.Equal(t, comparisons[0].Hull.Points.Len() - 1, comparisons[0].Vertices)
+	// a larger seglength simplifies more
+	assert.True(t, comparisons[1].Vertices < comparisons[0].Vertices)
+	assert.True(t, comparisons[0].Coverage > 0.9)
+	assert.True(t, comparisons[0].Descriptors.Solidity < 1)
+	// the edge length algorithm always contains the points
+	assert.Equal(t, 1., comparisons[2].Coverage)
+	assert.True(t, errors.Is(comparisons[3].Err, ErrInvalidOptions))
+}
@@ -0,0 +1,16 @@
+package ConcaveHull
+
+// Algorithmic behaviour a computation is pinned to, see Options.CompatVersion. A version pins only the behaviour listed
+// with it. Everything else, such as the choice between equidistant closest points, SearchEpsilon and the simplifier, is not
+// versioned and may change in any release
+type CompatVersion int
+
+const (
+	// seglength is used as given, even below the precision of the coordinates
+	CompatV1 CompatVersion = iota + 1
+	// seglength below the precision of the coordinates is raised to it, see WarningSeglengthClamped. Otherwise as CompatV1
+	CompatV2
+)
+
+// Version that Options.CompatVersion 0 follows
+const CompatLatest = CompatV2
@@ -0,0 +1,88 @@
+package ConcaveHull
+
+import (
+	"sort"
+	"github.com/furstenheim/go-convex-hull-2d"
+)
+
+// Successive convex hulls of the points, from the outermost inward: each layer is the convex hull of the points that are not
+// on a previous layer. Layers are closed rings, except for a last layer of one or two points or of collinear points which is
+// returned as the points themselves. Points are sorted in place
+func ConvexLayers (points FlatPoints) []FlatPoints {
+	sort.Sort(LexSorter(points))
+	return ConvexLayersFromSorted(points)
+}
+
+// Same as ConvexLayers for points sorted lexicographically by (x,y). The points are not modified
+func ConvexLayersFromSorted (points FlatPoints) []FlatPoints {
+	var layers []FlatPoints
+	for _, layer := range(convexLayerIndices(points, -1)) {
+		ring := make(FlatPoints, 0, 2 * len(layer) + 2)
+		for _, i := range(layer) {
+			ring = append(ring, points[2 * i], points[2 * i + 1])
+		}
+		if len(layer) >= 3 {
+			ring = append(ring, ring[0], ring[1])
+		}
+		layers = append(layers, ring)
+	}
+	return layers
+}
+
+// Indices of the vertices of the first maxLayers convex layers of sorted points, in ring order. All layers if maxLayers is negative
+func convexLayerIndices (sorted FlatPoints, maxLayers int) (layers [][]int) {
+	remaining := make([]int, sorted.Len())
+	for i := range(remaining) {
+		remaining[i] = i
+	}
+	buffer := make(FlatPoints, 0, len(sorted))
+	for len(remaining) > 0 && (maxLayers < 0 || len(layers) < maxLayers) {
+		buffer = buffer[:0]
+		for _, i := range(remaining) {
+			buffer = append(buffer, sorted[2 * i], sorted[2 * i + 1])
+		}
+		// the convex hull reorders its input, so vertices are looked up by coordinates in the sorted remaining points
+		lookup := append(FlatPoints{}, buffer...)
+		hull := go_convex_hull_2d.NewFromSortedArray(buffer).(FlatPoints)
+		if hull.Len() < 3 {
+			hull = lookup
+		}
+		used := make([]bool, len(remaining))
+		layer := make([]int, 0, hull.Len())
+		for k := 0; k < hull.Len(); k++ {
+			x, y := hull.Take(k)
+			j := sort.Search(len(remaining), func (j int) bool {
+				return lookup[2 * j] > x || (lookup[2 * j] == x && lookup[2 * j + 1] >= y)
+			})
+			// duplicates of a vertex belong to the next layers
+			for used[j] {
+				j++
+			}
+			used[j] = true
+			layer = append(layer, remaining[j])
+		}
+		layers = append(layers, layer)
+		next := remaining[:0]
+		for j, i := range(remaining) {
+			if !used[j] {
+				next = append(next, i)
+			}
+		}
+		remaining = next
+	}
+	return layers
+}
+
+// Mark all points except the vertices of the outermost convex layers
+func peelKeep (sorted FlatPoints, layers int) []bool {
+	keep := make([]bool, sorted.Len())
+	for i := range(keep) {
+		keep[i] = true
+	}
+	for _, layer := range(convexLayerIndices(sorted, layers)) {
+		for _, i := range(layer) {
+			keep[i] = false
+		}
+	}
+	return keep
+}
@@ -0,0 +1,30 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvexLayers (t *testing.T) {
+	// two nested squares and a repeated center point
+	points := FlatPoints{0, 0, 4, 0, 4, 4, 0, 4, 1, 1, 3, 1, 3, 3, 1, 3, 2, 2, 2, 2}
+	layers := ConvexLayers(points)
+	assert.Equal(t, 3, len(layers))
+	assert.Equal(t, 5, layers[0].Len())
+	assert.Equal(t, 16., SignedArea(layers[0]))
+	assert.Equal(t, 5, layers[1].Len())
+	assert.Equal(t, 4., SignedArea(layers[1]))
+	assert.Equal(t, FlatPoints{2, 2, 2, 2}, layers[2])
+	assert.Equal(t, 0, len(ConvexLayers(FlatPoints{})))
+}
+
+func TestComputeContext_peelLayers (t *testing.T) {
+	points := FlatPoints{0, 0, 4, 0, 4, 4, 0, 4, 1, 1, 3, 1, 3, 3, 1, 3, 2, 2}
+	hull, err := ComputeContext(context.Background(), points, &Options{PeelLayers: 1, Seglength: 0.5})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 7, 8}, hull.Dropped)
+	area := math.Abs(SignedArea(hull.Points))
+	assert.True(t, area > 0 && area <= 4)
+}
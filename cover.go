@@ -0,0 +1,70 @@
+package ConcaveHull
+
+import (
+	"math"
+	"github.com/USACE/concavehull/geomutil"
+)
+
+// Which cells a cover of a hull returns
+type CoverMode int
+
+const (
+	CoverIntersecting CoverMode = iota // cells that share some area with the hull, so that together they contain it
+	CoverContained // cells entirely inside the hull
+)
+
+// Whether the rectangle shares area with the closed ring and whether it lies entirely inside it
+func rectRelation (ring FlatPoints, minX, minY, maxX, maxY float64) (intersects, contained bool) {
+	crosses := false
+	for i := 0; i + 1 < ring.Len(); i++ {
+		x1, y1 := ring.Take(i)
+		x2, y2 := ring.Take(i + 1)
+		if segmentCrossesRect(x1, y1, x2, y2, minX, minY, maxX, maxY) {
+			crosses = true
+			break
+		}
+	}
+	centerInside := ringContains(ring, (minX + maxX) / 2, (minY + maxY) / 2)
+	if !crosses {
+		// either the rectangle is inside, or outside, or it contains the whole ring
+		if centerInside {
+			return true, true
+		}
+		if ring.Len() > 0 {
+			x, y := ring.Take(0)
+			return minX < x && x < maxX && minY < y && y < maxY, false
+		}
+		return false, false
+	}
+	return true, false
+}
+
+// Whether the segment passes through the interior of the rectangle
+func segmentCrossesRect (x1, y1, x2, y2, minX, minY, maxX, maxY float64) bool {
+	_, _, crosses := geomutil.ClipSegment(x1, y1, x2, y2, minX, minY, maxX, maxY)
+	return crosses
+}
+
+// Cells of a regular grid of cells of the given size anchored at (originX, originY) selected by mode, as column, row pairs
+func gridCover (ring FlatPoints, originX, originY, cellWidth, cellHeight float64, columns, rows int, mode CoverMode) (cells [][2]int) {
+	ring = closeRing(ring)
+	if ring.Len() < 4 {
+		return nil
+	}
+	minX, minY, maxX, maxY := bbox(ring)
+	clamp := func (v float64, n int) int {
+		return int(math.Max(0, math.Min(float64(n - 1), v)))
+	}
+	firstColumn, lastColumn := clamp(math.Floor((minX - originX) / cellWidth), columns), clamp(math.Floor((maxX - originX) / cellWidth), columns)
+	firstRow, lastRow := clamp(math.Floor((minY - originY) / cellHeight), rows), clamp(math.Floor((maxY - originY) / cellHeight), rows)
+	for row := firstRow; row <= lastRow; row++ {
+		for column := firstColumn; column <= lastColumn; column++ {
+			x0, y0 := originX + float64(column) * cellWidth, originY + float64(row) * cellHeight
+			intersects, contained := rectRelation(ring, x0, y0, x0 + cellWidth, y0 + cellHeight)
+			if contained || (mode == CoverIntersecting && intersects) {
+				cells = append(cells, [2]int{column, row})
+			}
+		}
+	}
+	return cells
+}
@@ -0,0 +1,115 @@
+package ConcaveHull
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidCSV = errors.New("ConcaveHull: invalid delimited text")
+
+// Layout of delimited text read by ReadCSV and ScanCSV
+type CSVOptions struct {
+	Delimiter rune // defaults to ',', '\t' for TSV
+	Comment rune // lines starting with it are ignored, 0 for none
+	SkipRows int // lines skipped before the header or the first record, such as banners of legacy exports
+	Header bool // the first record names the columns
+	// Zero based columns of the coordinates, used unless XName and YName are set. If both are zero, x and y are the first two columns
+	XColumn, YColumn int
+	// Names of the columns of the coordinates in the header, which implies Header
+	XName, YName string
+	DecimalSeparator rune // defaults to '.', for example ',' for many European locales
+}
+
+// Read the coordinates of every record, see ScanCSV
+func ReadCSV (r io.Reader, o CSVOptions) (FlatPoints, error) {
+	var points FlatPoints
+	err := ScanCSV(r, o, func (x, y float64) error {
+		points = append(points, x, y)
+		return nil
+	})
+	return points, err
+}
+
+// Call f with the coordinates of each record as it is read, so the text is never held in memory as a whole. Empty lines are
+// skipped. Unparsable records are reported as ErrInvalidCSV with their line, and errors returned by f stop the scan
+func ScanCSV (r io.Reader, o CSVOptions, f func (x, y float64) error) error {
+	buffered := bufio.NewReader(r)
+	for i := 0; i < o.SkipRows; i++ {
+		if _, err := buffered.ReadString('\n'); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	reader := csv.NewReader(buffered)
+	if o.Delimiter != 0 {
+		reader.Comma = o.Delimiter
+	}
+	reader.Comment = o.Comment
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	xColumn, yColumn := o.XColumn, o.YColumn
+	if xColumn == 0 && yColumn == 0 {
+		yColumn = 1
+	}
+	if o.Header || o.XName != "" || o.YName != "" {
+		header, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		if o.XName != "" || o.YName != "" {
+			xColumn, yColumn = -1, -1
+			for i, name := range(header) {
+				switch strings.TrimSpace(name) {
+				case o.XName:
+					xColumn = i
+				case o.YName:
+					yColumn = i
+				}
+			}
+			if xColumn < 0 || yColumn < 0 {
+				return fmt.Errorf("%w: columns %q and %q not found in header", ErrInvalidCSV, o.XName, o.YName)
+			}
+		}
+	}
+	parse := func (field string) (float64, error) {
+		field = strings.TrimSpace(field)
+		if o.DecimalSeparator != 0 && o.DecimalSeparator != '.' {
+			field = strings.Replace(field, string(o.DecimalSeparator), ".", 1)
+		}
+		return strconv.ParseFloat(field, 64)
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		line, _ := reader.FieldPos(0)
+		line += o.SkipRows
+		if xColumn >= len(record) || yColumn >= len(record) {
+			return fmt.Errorf("%w: line %d has %d fields", ErrInvalidCSV, line, len(record))
+		}
+		x, err := parse(record[xColumn])
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidCSV, line, err)
+		}
+		y, err := parse(record[yColumn])
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidCSV, line, err)
+		}
+		if err := f(x, y); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package ConcaveHull
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCSV (t *testing.T) {
+	points, err := ReadCSV(strings.NewReader("1,2\n3.5,-4\n\n5,6\n"), CSVOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, FlatPoints{1, 2, 3.5, -4, 5, 6}, points)
+
+	legacy := "EXPORT 2021-03-04\nrows follow\nid;lat;lon\n# comment\n7;45,5;-3,25\n8; 46,0 ;-3,5\n"
+	points, err = ReadCSV(strings.NewReader(legacy), CSVOptions{Delimiter: ';', Comment: '#', SkipRows: 2, XName: "lon", YName: "lat", DecimalSeparator: ','})
+	assert.Nil(t, err)
+	assert.Equal(t, FlatPoints{-3.25, 45.5, -3.5, 46}, points)
+
+	points, err = ReadCSV(strings.NewReader("a\tx\ty\nq\t1\t2\n"), CSVOptions{Delimiter: '\t', Header: true, XColumn: 1, YColumn: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, FlatPoints{1, 2}, points)
+}
+
+func TestReadCSV_errors (t *testing.T) {
+	_, err := ReadCSV(strings.NewReader("1,2\n3,x\n"), CSVOptions{})
+	assert.True(t, errors.Is(err, ErrInvalidCSV))
+	assert.Contains(t, err.Error(), "line 2")
+	_, err = ReadCSV(strings.NewReader("1,2\n3\n"), CSVOptions{})
+	assert.True(t, errors.Is(err, ErrInvalidCSV))
+	_, err = ReadCSV(strings.NewReader("a,b\n1,2\n"), CSVOptions{XName: "x", YName: "y"})
+	assert.True(t, errors.Is(err, ErrInvalidCSV))
+	stop := errors.New("stop")
+	calls := 0
+	err = ScanCSV(strings.NewReader("1,2\n3,4\n"), CSVOptions{}, func (x, y float64) error {
+		calls++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+}
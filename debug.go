@@ -0,0 +1,99 @@
+package ConcaveHull
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// An invariant checked by Options.Debug does not hold after a phase of the computation. The functions that report errors
+// return it, the others panic with it
+type InvariantError struct {
+	Phase string // "convex hull", "segmentize" or "simplify"
+	Invariant string
+	Detail string
+}
+
+func (e *InvariantError) Error () string {
+	return fmt.Sprintf("ConcaveHull: invariant %q violated after %s: %s", e.Invariant, e.Phase, e.Detail)
+}
+
+func invariantViolated (phase, invariant, format string, args ...interface{}) {
+	panic(&InvariantError{Phase: phase, Invariant: invariant, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Start checking invariants against the sorted input points, c.inputs, and their convex hull
+func (c * concaver) startDebug (convexHull FlatPoints) {
+	sorted := c.inputs
+	c.debug = true
+	c.debugConvexHull = convexHull
+	if convexHull.Len() < 3 {
+		return
+	}
+	tolerance := 1e-9 * bboxDiagonal(sorted)
+	sign := math.Copysign(1, SignedArea(convexHull))
+	for i := 0; i < convexHull.Len(); i++ {
+		x1, y1, x2, y2 := convexHullEdge(convexHull, i)
+		_, _, x3, y3 := convexHullEdge(convexHull, (i + 1) % convexHull.Len())
+		slack := tolerance * math.Hypot(x2 - x1, y2 - y1)
+		if sign * orientation(x1, y1, x2, y2, x3, y3) < -slack {
+			invariantViolated("convex hull", "convexity", "reflex vertex %d at (%v, %v)", (i + 1) % convexHull.Len(), x2, y2)
+		}
+		for j := 0; j < sorted.Len(); j++ {
+			x, y := sorted.Take(j)
+			if sign * orientation(x1, y1, x2, y2, x, y) < -slack {
+				invariantViolated("convex hull", "containment of inputs", "point %d at (%v, %v) is outside edge %d", j, x, y, i)
+			}
+		}
+	}
+}
+
+// Bisection of an edge must find points at strictly increasing steps, from step previous to step index
+func (c * concaver) checkStep (previous, index int) {
+	if index <= previous {
+		invariantViolated("segmentize", "monotone progress", "step %d found after step %d", index, previous)
+	}
+}
+
+// The ring is closed, has no crossing edges, its vertices are input points or interpolated, and lie in the convex hull
+func (c * concaver) checkRing (phase string, ring FlatPoints) {
+	n := ring.Len()
+	if n == 0 {
+		return
+	}
+	if c.debugConvexHull.Len() >= 3 && (ring[0] != ring[2 * n - 2] || ring[1] != ring[2 * n - 1]) {
+		invariantViolated(phase, "ring closedness", "first vertex (%v, %v) and last vertex (%v, %v) differ", ring[0], ring[1], ring[2 * n - 2], ring[2 * n - 1])
+	}
+	for i := 0; i < n; i++ {
+		x, y := ring.Take(i)
+		if !c.interpolated[[2]float64{x, y}] && !containsPoint(c.inputs, x, y) {
+			invariantViolated(phase, "vertices are inputs", "vertex %d at (%v, %v) is not an input point", i, x, y)
+		}
+	}
+	for i := 0; i + 1 < n; i++ {
+		ax, ay := ring.Take(i)
+		bx, by := ring.Take(i + 1)
+		for j := i + 2; j + 1 < n; j++ {
+			cx, cy := ring.Take(j)
+			dx, dy := ring.Take(j + 1)
+			if edgesCross(ax, ay, bx, by, cx, cy, dx, dy) {
+				invariantViolated(phase, "simplicity", "edge %d from (%v, %v) to (%v, %v) crosses edge %d from (%v, %v) to (%v, %v)", i, ax, ay, bx, by, j, cx, cy, dx, dy)
+			}
+		}
+	}
+}
+
+// Whether (x, y) is one of the points, which are sorted lexicographically
+func containsPoint (sorted FlatPoints, x, y float64) bool {
+	i := sort.Search(sorted.Len(), func (i int) bool {
+		px, py := sorted.Take(i)
+		return px > x || px == x && py >= y
+	})
+	return i < sorted.Len() && sorted[2 * i] == x && sorted[2 * i + 1] == y
+}
+
+// Whether two segments cross at a point interior to both
+func edgesCross (ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	return orientation(cx, cy, dx, dy, ax, ay) * orientation(cx, cy, dx, dy, bx, by) < 0 &&
+		orientation(ax, ay, bx, by, cx, cy) * orientation(ax, ay, bx, by, dx, dy) < 0
+}
@@ -0,0 +1,68 @@
+package ConcaveHull
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestDebug_validHull (t *testing.T) {
+	r := rand.New(rand.NewSource(10))
+	for _, o := range([]*Options{
+		{Seglength: 0.02, Debug: true},
+		{Seglength: 0.02, Debug: true, MaxEdgeLength: 0.05},
+		{Seglength: 0.02, Debug: true, ExactArithmetic: true},
+	}) {
+		points := hulltest.Random(r, 500)
+		debugged, err := ComputeContext(context.Background(), FlatPoints(append([]float64{}, points...)), o)
+		assert.Nil(t, err)
+		plain := *o
+		plain.Debug = false
+		expected, _ := ComputeContext(context.Background(), FlatPoints(points), &plain)
+		assert.Equal(t, expected.Points, debugged.Points)
+	}
+}
+
+func TestDebug_prepared (t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	concaver := Prepare(FlatPoints(hulltest.Random(r, 300)))
+	defer concaver.Close()
+	_, err := concaver.ComputeContext(context.Background(), &Options{Seglength: 0.05, Debug: true})
+	assert.Nil(t, err)
+}
+
+func TestDebug_violations (t *testing.T) {
+	inputs := FlatPoints{0, 0, 0, 1, 1, 0, 1, 1}
+	c := concaver{inputs: inputs}
+	check := func (f func ()) (err error) {
+		defer recoverPanic(&err)
+		f()
+		return nil
+	}
+	var invariant *InvariantError
+
+	err := check(func () { c.startDebug(FlatPoints{0, 0, 1, 0, 0, 1}) })
+	assert.True(t, errors.As(err, &invariant))
+	assert.Equal(t, "containment of inputs", invariant.Invariant)
+
+	c.startDebug(FlatPoints{0, 0, 1, 0, 1, 1, 0, 1})
+	err = check(func () { c.checkRing("simplify", FlatPoints{0, 0, 1, 1, 1, 0, 0, 1, 0, 0}) })
+	assert.True(t, errors.As(err, &invariant))
+	assert.Equal(t, "simplicity", invariant.Invariant)
+	assert.Equal(t, "simplify", invariant.Phase)
+
+	err = check(func () { c.checkRing("segmentize", FlatPoints{0, 0, 1, 0, 1, 1, 0, 1}) })
+	assert.True(t, errors.As(err, &invariant))
+	assert.Equal(t, "ring closedness", invariant.Invariant)
+
+	err = check(func () { c.checkRing("simplify", FlatPoints{0, 0, 1, 0, 0.5, 0.5, 0, 0}) })
+	assert.True(t, errors.As(err, &invariant))
+	assert.Equal(t, "vertices are inputs", invariant.Invariant)
+
+	err = check(func () { c.checkStep(2, 2) })
+	assert.True(t, errors.As(err, &invariant))
+	assert.Equal(t, "monotone progress", invariant.Invariant)
+}
@@ -0,0 +1,100 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Weight below which the points of a DecayingHull expire, about 4.3 half lives
+const DEFAULT_DECAY_MIN_WEIGHT = 0.05
+
+// Hull of a stream of timestamped points, such as sensor positions, whose weight halves every half life. Points whose weight
+// falls below the minimum weight expire, so the hull reflects recent coverage without managing a window. Safe for concurrent use
+type DecayingHull struct {
+	halfLife time.Duration
+	minWeight float64
+	o *Options
+	mu sync.Mutex
+	points FlatPoints
+	times []time.Time
+	hull FlatPoints
+	// points were added or expired since hull was computed
+	changed bool
+}
+
+// Decaying hull computed with the options, which are copied. If minWeight is not in (0, 1), DEFAULT_DECAY_MIN_WEIGHT is used
+func NewDecayingHull (halfLife time.Duration, minWeight float64, o *Options) *DecayingHull {
+	if !(minWeight > 0 && minWeight < 1) {
+		minWeight = DEFAULT_DECAY_MIN_WEIGHT
+	}
+	return &DecayingHull{halfLife: halfLife, minWeight: minWeight, o: o.snapshot()}
+}
+
+// Add a point observed at t
+func (d *DecayingHull) Add (x, y float64, t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.points = append(d.points, x, y)
+	d.times = append(d.times, t)
+	d.changed = true
+}
+
+// Weight at now of a point observed at t, 1 for points not older than now
+func (d *DecayingHull) weight (t, now time.Time) float64 {
+	age := now.Sub(t)
+	if age <= 0 {
+		return 1
+	}
+	if d.halfLife <= 0 {
+		return 0
+	}
+	return math.Exp2(-float64(age) / float64(d.halfLife))
+}
+
+// Drop the points whose weight at now is below the minimum weight
+func (d *DecayingHull) expire (now time.Time) {
+	kept := 0
+	for i, t := range(d.times) {
+		if d.weight(t, now) < d.minWeight {
+			continue
+		}
+		d.points[2 * kept], d.points[2 * kept + 1] = d.points[2 * i], d.points[2 * i + 1]
+		d.times[kept] = t
+		kept++
+	}
+	if kept < len(d.times) {
+		d.points, d.times = d.points[:2 * kept], d.times[:kept]
+		d.changed = true
+	}
+}
+
+// Hull at now of the points that have not expired. It is computed again only if points were added or expired since the
+// previous call, and the returned ring must not be modified
+func (d *DecayingHull) Hull (now time.Time) (FlatPoints, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expire(now)
+	if !d.changed {
+		return d.hull, nil
+	}
+	hull, err := ComputeContext(context.Background(), append(FlatPoints{}, d.points...), d.o)
+	if err != nil {
+		return nil, err
+	}
+	d.hull, d.changed = hull.Points, false
+	return d.hull, nil
+}
+
+// Copy of the points that have not expired at now, in the order they were added, and their weights, for example to shade them
+func (d *DecayingHull) Points (now time.Time) (points FlatPoints, weights []float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expire(now)
+	weights = make([]float64, len(d.times))
+	for i, t := range(d.times) {
+		weights[i] = d.weight(t, now)
+	}
+	return append(FlatPoints{}, d.points...), weights
+}
@@ -0,0 +1,38 @@
+package ConcaveHull
+
+import (
+	"math"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecayingHull (t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	d := NewDecayingHull(time.Hour, 0.2, nil)
+	for _, p := range([][2]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}}) {
+		d.Add(p[0], p[1], start)
+	}
+	for _, p := range([][2]float64{{10, 10}, {11, 10}, {11, 11}, {10, 11}}) {
+		d.Add(p[0], p[1], start.Add(2 * time.Hour))
+	}
+	hull, err := d.Hull(start.Add(2 * time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, FlatPoints{0, 0, 1, 0, 1, 1, 10, 10, 11, 10, 11, 11, 10, 11, 10, 10, 1, 1, 0, 1, 0, 0}, hull)
+
+	points, weights := d.Points(start.Add(2 * time.Hour))
+	assert.Equal(t, 16, len(points))
+	assert.True(t, math.Abs(weights[0] - 0.25) < 1e-12)
+	assert.Equal(t, 1., weights[7])
+
+	// the first points weigh 0.125 < 0.2 after 3 hours
+	hull, err = d.Hull(start.Add(3 * time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, FlatPoints{10, 10, 11, 10, 11, 11, 10, 11, 10, 10}, hull)
+	again, _ := d.Hull(start.Add(3 * time.Hour))
+	assert.Equal(t, &hull[0], &again[0])
+
+	hull, err = d.Hull(start.Add(24 * time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, hull.Len())
+}
@@ -0,0 +1,79 @@
+package ConcaveHull
+
+import "fmt"
+
+// Ways a hull can be questionable even though the computation succeeded, for pipelines to flag results without analysing
+// their geometry
+type Degeneracy struct {
+	// Every turn of the ring is to the same side, so the hull is the convex hull of the points and concavity had no effect
+	ConvexHull bool
+	// Fewer than three distinct vertices or no area, such as the point or segment of a small cluster
+	Collapsed bool
+	// Edges of zero length and spikes, where the ring goes back along the edge it came from
+	CollapsedEdges int
+	// Points discarded before computing the hull, see Hull.Dropped
+	DroppedPoints int
+	// Parameters adjusted during the computation, see Hull.Warnings
+	Clamped bool
+	// Refinement stopped early, see Hull.Partial
+	Partial bool
+}
+
+// Whether any degeneracy was found
+func (d Degeneracy) Any () bool {
+	return d.ConvexHull || d.Collapsed || d.CollapsedEdges > 0 || d.DroppedPoints > 0 || d.Clamped || d.Partial
+}
+
+func (d Degeneracy) String () string {
+	if !d.Any() {
+		return "none"
+	}
+	return fmt.Sprintf("convex hull %t, collapsed %t, collapsed edges %d, dropped points %d, clamped %t, partial %t",
+		d.ConvexHull, d.Collapsed, d.CollapsedEdges, d.DroppedPoints, d.Clamped, d.Partial)
+}
+
+// Degeneracies of the hull, computed from its ring, Dropped, Warnings and Partial
+func (h Hull) Degeneracy () Degeneracy {
+	d := Degeneracy{DroppedPoints: len(h.Dropped), Partial: h.Partial}
+	for _, w := range(h.Warnings) {
+		switch w.Kind {
+		case WarningSeglengthClamped:
+			d.Clamped = true
+		case WarningConvexHull:
+			d.ConvexHull = true
+		case WarningProbeLimit, WarningTimeBudget:
+			d.Partial = true
+		}
+	}
+	ring := openRing(h.Points)
+	n := ring.Len()
+	distinct := 0
+	left, right := false, false
+	for i := 0; i < n; i++ {
+		x1, y1 := ring.Take((i + n - 1) % n)
+		x2, y2 := ring.Take(i)
+		x3, y3 := ring.Take((i + 1) % n)
+		if x2 == x3 && y2 == y3 {
+			d.CollapsedEdges++
+			continue
+		}
+		distinct++
+		turn := orientation(x1, y1, x2, y2, x3, y3)
+		switch {
+		case turn > 0:
+			left = true
+		case turn < 0:
+			right = true
+		case (x2 - x1) * (x3 - x2) + (y2 - y1) * (y3 - y2) < 0:
+			d.CollapsedEdges++
+		}
+	}
+	if distinct < 3 || SignedArea(ring) == 0 {
+		d.Collapsed = true
+		return d
+	}
+	if left != right {
+		d.ConvexHull = true
+	}
+	return d
+}
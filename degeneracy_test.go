@@ -0,0 +1,33 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHull_Degeneracy (t *testing.T) {
+	// concave ring
+	d := Hull{Points: FlatPoints{0, 0, 2, 0, 2, 2, 1, 1, 0, 2, 0, 0}}.Degeneracy()
+	assert.False(t, d.Any())
+	assert.Equal(t, "none", d.String())
+
+	d = Hull{Points: FlatPoints{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}, Dropped: []int{4, 7}}.Degeneracy()
+	assert.True(t, d.ConvexHull)
+	assert.Equal(t, 2, d.DroppedPoints)
+	assert.False(t, d.Collapsed)
+
+	// a repeated vertex and a spike to (3, 0)
+	d = Hull{Points: FlatPoints{0, 0, 2, 0, 2, 0, 3, 0, 2, 0, 2, 2, 1, 1, 0, 2, 0, 0}}.Degeneracy()
+	assert.Equal(t, 2, d.CollapsedEdges)
+	assert.False(t, d.ConvexHull)
+
+	// point and segment of small clusters
+	assert.True(t, Hull{Points: FlatPoints{1, 1}}.Degeneracy().Collapsed)
+	assert.True(t, Hull{Points: FlatPoints{1, 1, 2, 2, 1, 1}}.Degeneracy().Collapsed)
+	assert.True(t, Hull{Points: FlatPoints{0, 0, 1, 1, 2, 2, 0, 0}}.Degeneracy().Collapsed)
+
+	d = Hull{Points: FlatPoints{0, 0, 2, 0, 2, 2, 1, 1, 0, 2, 0, 0}, Warnings: []Warning{{Kind: WarningSeglengthClamped}, {Kind: WarningProbeLimit}}}.Degeneracy()
+	assert.True(t, d.Clamped)
+	assert.True(t, d.Partial)
+	assert.True(t, d.Any())
+}
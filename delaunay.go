@@ -0,0 +1,152 @@
+package ConcaveHull
+
+import "math"
+
+// Size of the initial triangle with respect to the extent of the points
+const superTriangleScale = 100
+
+type delaunayTriangle struct {
+	// vertices in counter clockwise order
+	v [3]int
+	// neighbour opposite to each vertex, -1 if none
+	n [3]int
+	alive bool
+}
+
+// Delaunay triangulation computed by incremental insertion (Bowyer Watson). Vertices are indices in points,
+// followed by the three vertices of a super triangle enclosing all of them
+type delaunay struct {
+	points FlatPoints
+	triangles []delaunayTriangle
+	nPoints int
+}
+
+// Triangulate the points, which are expected to be free of duplicates. Sorted input makes point location fast
+func newDelaunay (input FlatPoints) *delaunay {
+	n := input.Len()
+	minX, minY, maxX, maxY := bbox(input)
+	span := math.Max(math.Max(maxX - minX, maxY - minY), 1e-9) * superTriangleScale
+	cx, cy := (minX + maxX) / 2, (minY + maxY) / 2
+	points := make(FlatPoints, 0, len(input) + 6)
+	points = append(points, input...)
+	points = append(points, cx - 2 * span, cy - span, cx + 2 * span, cy - span, cx, cy + 2 * span)
+	d := &delaunay{points: points, nPoints: n}
+	d.triangles = append(d.triangles, delaunayTriangle{v: [3]int{n, n + 1, n + 2}, n: [3]int{-1, -1, -1}, alive: true})
+	last := 0
+	for p := 0; p < n; p++ {
+		last = d.insert(p, last)
+	}
+	return d
+}
+
+func (d *delaunay) orient (a, b, c int) float64 {
+	ax, ay := d.points.Take(a)
+	bx, by := d.points.Take(b)
+	cx, cy := d.points.Take(c)
+	return orientation(ax, ay, bx, by, cx, cy)
+}
+
+// Whether p is strictly inside the circumcircle of the counter clockwise triangle t
+func (d *delaunay) inCircumcircle (t int, p int) bool {
+	tri := &d.triangles[t]
+	px, py := d.points.Take(p)
+	ax, ay := d.points.Take(tri.v[0])
+	bx, by := d.points.Take(tri.v[1])
+	cx, cy := d.points.Take(tri.v[2])
+	ax, ay, bx, by, cx, cy = ax - px, ay - py, bx - px, by - py, cx - px, cy - py
+	det := (ax * ax + ay * ay) * (bx * cy - cx * by) - (bx * bx + by * by) * (ax * cy - cx * ay) + (cx * cx + cy * cy) * (ax * by - bx * ay)
+	return det > 0
+}
+
+// Walk from triangle start to the triangle containing p
+func (d *delaunay) locate (p int, start int) int {
+	t := start
+	for steps := 0; steps < len(d.triangles); steps++ {
+		tri := &d.triangles[t]
+		moved := false
+		for k := 0; k < 3; k++ {
+			if d.orient(tri.v[(k + 1) % 3], tri.v[(k + 2) % 3], p) < 0 && tri.n[k] != -1 {
+				t = tri.n[k]
+				moved = true
+				break
+			}
+		}
+		if !moved {
+			return t
+		}
+	}
+	// walking can cycle with degenerate configurations, fall back to a scan
+	for i := range(d.triangles) {
+		tri := &d.triangles[i]
+		if tri.alive && d.orient(tri.v[0], tri.v[1], p) >= 0 && d.orient(tri.v[1], tri.v[2], p) >= 0 && d.orient(tri.v[2], tri.v[0], p) >= 0 {
+			return i
+		}
+	}
+	return start
+}
+
+// Insert point p, returns one of the new triangles
+func (d *delaunay) insert (p int, start int) int {
+	first := d.locate(p, start)
+	// cavity of triangles whose circumcircle contains p
+	bad := map[int]bool{first: true}
+	stack := []int{first}
+	cavity := []int{first}
+	for len(stack) > 0 {
+		t := stack[len(stack) - 1]
+		stack = stack[:len(stack) - 1]
+		for _, nb := range(d.triangles[t].n) {
+			if nb != -1 && !bad[nb] && d.inCircumcircle(nb, p) {
+				bad[nb] = true
+				stack = append(stack, nb)
+				cavity = append(cavity, nb)
+			}
+		}
+	}
+	// new triangles join p to the boundary edges of the cavity
+	startingAt := make(map[int]int)
+	endingAt := make(map[int]int)
+	var created []int
+	for _, t := range(cavity) {
+		tri := d.triangles[t]
+		for k := 0; k < 3; k++ {
+			if tri.n[k] != -1 && bad[tri.n[k]] {
+				continue
+			}
+			a, b := tri.v[(k + 1) % 3], tri.v[(k + 2) % 3]
+			id := len(d.triangles)
+			d.triangles = append(d.triangles, delaunayTriangle{v: [3]int{a, b, p}, n: [3]int{-1, -1, tri.n[k]}, alive: true})
+			if outside := tri.n[k]; outside != -1 {
+				for m := 0; m < 3; m++ {
+					if d.triangles[outside].n[m] == t {
+						d.triangles[outside].n[m] = id
+					}
+				}
+			}
+			startingAt[a] = id
+			endingAt[b] = id
+			created = append(created, id)
+		}
+	}
+	for _, t := range(cavity) {
+		d.triangles[t].alive = false
+	}
+	for _, id := range(created) {
+		tri := &d.triangles[id]
+		a, b := tri.v[0], tri.v[1]
+		// edge (b, p) is shared with the triangle starting at b, edge (p, a) with the one ending at a
+		tri.n[0] = startingAt[b]
+		tri.n[1] = endingAt[a]
+	}
+	return created[0]
+}
+
+// Whether the triangle uses a vertex of the super triangle
+func (d *delaunay) isExternal (t int) bool {
+	for _, v := range(d.triangles[t].v) {
+		if v >= d.nPoints {
+			return true
+		}
+	}
+	return false
+}
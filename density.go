@@ -0,0 +1,84 @@
+package ConcaveHull
+
+import (
+	"math"
+	"sort"
+)
+
+// Kernels are truncated at this many bandwidths, beyond which their contribution is negligible
+const densityKernelCutoff = 3
+
+// Mark the points whose kernel density is at least threshold times the median density
+func densityKeep (points FlatPoints, bandwidth, threshold float64) []bool {
+	keep := make([]bool, points.Len())
+	if bandwidth <= 0 {
+		bandwidth = silvermanBandwidth(points)
+	}
+	if bandwidth <= 0 {
+		// all points are the same
+		for i := range(keep) {
+			keep[i] = true
+		}
+		return keep
+	}
+	density := kernelDensity(points, bandwidth)
+	sorted := append([]float64{}, density...)
+	sort.Float64s(sorted)
+	minDensity := threshold * sorted[len(sorted) / 2]
+	for i, d := range(density) {
+		keep[i] = d >= minDensity
+	}
+	return keep
+}
+
+// Unnormalized gaussian kernel density at each of the points. Points are binned in a grid of the size of the bandwidth
+// so that only neighbouring cells need to be visited
+func kernelDensity (points FlatPoints, bandwidth float64) []float64 {
+	n := points.Len()
+	cells := make(map[[2]int][]int)
+	cellOf := func (x, y float64) [2]int {
+		return [2]int{int(math.Floor(x / bandwidth)), int(math.Floor(y / bandwidth))}
+	}
+	for i := 0; i < n; i++ {
+		cell := cellOf(points.Take(i))
+		cells[cell] = append(cells[cell], i)
+	}
+	density := make([]float64, n)
+	cutoff := densityKernelCutoff * bandwidth * densityKernelCutoff * bandwidth
+	for i := 0; i < n; i++ {
+		x, y := points.Take(i)
+		cell := cellOf(x, y)
+		for dx := -densityKernelCutoff; dx <= densityKernelCutoff; dx++ {
+			for dy := -densityKernelCutoff; dy <= densityKernelCutoff; dy++ {
+				for _, j := range(cells[[2]int{cell[0] + dx, cell[1] + dy}]) {
+					xj, yj := points.Take(j)
+					d2 := (x - xj) * (x - xj) + (y - yj) * (y - yj)
+					if d2 <= cutoff {
+						density[i] += math.Exp(-d2 / (2 * bandwidth * bandwidth))
+					}
+				}
+			}
+		}
+	}
+	return density
+}
+
+// Silverman's rule of thumb using the average standard deviation of both axes
+func silvermanBandwidth (points FlatPoints) float64 {
+	n := points.Len()
+	if n < 2 {
+		return 0
+	}
+	var sumX, sumY, sumX2, sumY2 float64
+	for i := 0; i < n; i++ {
+		x, y := points.Take(i)
+		sumX, sumY = sumX + x, sumY + y
+		sumX2, sumY2 = sumX2 + x * x, sumY2 + y * y
+	}
+	fn := float64(n)
+	variance := (sumX2 / fn - (sumX / fn) * (sumX / fn) + sumY2 / fn - (sumY / fn) * (sumY / fn)) / 2
+	if variance <= 0 {
+		return 0
+	}
+	return 1.06 * math.Sqrt(variance) * math.Pow(fn, -0.2)
+}
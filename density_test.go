@@ -0,0 +1,29 @@
+package ConcaveHull
+
+import (
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestComputeWithOptions_densityThreshold (t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	points := hulltest.Random(r, 1000)
+	// isolated glitch far from the data
+	points = append(points, 3, 3)
+	hull := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.01})
+	assert.True(t, ringContainsVertex(hull, 3, 3))
+	hull = ComputeWithOptions(FlatPoints(points), &Options{Seglength: 0.01, DensityThreshold: 0.1})
+	assert.False(t, ringContainsVertex(hull, 3, 3))
+	assert.True(t, hull.Len() > 4)
+}
+
+func ringContainsVertex (ring FlatPoints, x, y float64) bool {
+	for i := 0; i < ring.Len(); i++ {
+		if vx, vy := ring.Take(i); vx == x && vy == y {
+			return true
+		}
+	}
+	return false
+}
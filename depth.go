@@ -0,0 +1,144 @@
+package ConcaveHull
+
+import (
+	"math"
+	"sort"
+	"github.com/furstenheim/go-convex-hull-2d"
+)
+
+// Number of projection directions used to approximate the halfspace depth
+const DEFAULT_DEPTH_DIRECTIONS = 64
+
+// Statistical regions of a bivariate point cloud, after Rousseeuw, Ruts and Tukey's bagplot
+type Bagplot struct {
+	// Deepest point, the average of the points of maximal depth
+	MedianX, MedianY float64
+	// Convex hull of the deepest half of the points, as a closed ring
+	Bag FlatPoints
+	// Bag inflated three times around the median
+	Fence FlatPoints
+	// Indices of the points outside of the fence
+	Outliers []int
+}
+
+// Convex hull, as a closed ring, of the deepest fraction of the points by halfspace (Tukey) depth. Depth regions are convex,
+// so this is the depth contour enclosing that fraction. Depth is approximated with DEFAULT_DEPTH_DIRECTIONS directions.
+// Points are not modified
+func DepthContour (points FlatPoints, fraction float64) FlatPoints {
+	depths := halfspaceDepths(points, DEFAULT_DEPTH_DIRECTIONS)
+	return convexRing(deepestPoints(points, depths, fraction))
+}
+
+// Bag, fence and outliers of the points. Points are not modified
+func ComputeBagplot (points FlatPoints) (b Bagplot) {
+	n := points.Len()
+	if n == 0 {
+		return b
+	}
+	depths := halfspaceDepths(points, DEFAULT_DEPTH_DIRECTIONS)
+	maxDepth, count := 0, 0.
+	for _, d := range(depths) {
+		if d > maxDepth {
+			maxDepth = d
+		}
+	}
+	for i, d := range(depths) {
+		if d == maxDepth {
+			x, y := points.Take(i)
+			b.MedianX, b.MedianY = b.MedianX + x, b.MedianY + y
+			count++
+		}
+	}
+	b.MedianX, b.MedianY = b.MedianX / count, b.MedianY / count
+	b.Bag = convexRing(deepestPoints(points, depths, 0.5))
+	b.Fence = make(FlatPoints, len(b.Bag))
+	for i := 0; i < b.Bag.Len(); i++ {
+		x, y := b.Bag.Take(i)
+		b.Fence[2 * i], b.Fence[2 * i + 1] = b.MedianX + 3 * (x - b.MedianX), b.MedianY + 3 * (y - b.MedianY)
+	}
+	for i := 0; i < n; i++ {
+		x, y := points.Take(i)
+		if b.Fence.Len() >= 4 && !ringContains(b.Fence, x, y) && !onRing(b.Fence, x, y) {
+			b.Outliers = append(b.Outliers, i)
+		}
+	}
+	return b
+}
+
+// Approximate halfspace depth of each point: the smallest number of points in a closed halfplane bounded by a line through the
+// point, minimized over evenly spaced directions instead of all of them. The approximation never underestimates the depth
+func halfspaceDepths (points FlatPoints, directions int) []int {
+	n := points.Len()
+	depths := make([]int, n)
+	for i := range(depths) {
+		depths[i] = n
+	}
+	projections := make([]float64, n)
+	sorted := make([]float64, n)
+	for k := 0; k < directions; k++ {
+		// opposite directions are covered by counting both sides
+		sin, cos := math.Sincos(math.Pi * float64(k) / float64(directions))
+		for i := 0; i < n; i++ {
+			x, y := points.Take(i)
+			projections[i] = x * cos + y * sin
+		}
+		copy(sorted, projections)
+		sort.Float64s(sorted)
+		for i, p := range(projections) {
+			below := sort.Search(n, func (j int) bool { return sorted[j] > p })
+			above := n - sort.Search(n, func (j int) bool { return sorted[j] >= p })
+			if below < depths[i] {
+				depths[i] = below
+			}
+			if above < depths[i] {
+				depths[i] = above
+			}
+		}
+	}
+	return depths
+}
+
+// Points of depth at least the largest threshold that keeps fraction of the points
+func deepestPoints (points FlatPoints, depths []int, fraction float64) FlatPoints {
+	n := len(depths)
+	order := append([]int{}, depths...)
+	sort.Sort(sort.Reverse(sort.IntSlice(order)))
+	kept := int(math.Ceil(fraction * float64(n)))
+	if kept < 1 {
+		kept = 1
+	}
+	if kept > n {
+		kept = n
+	}
+	threshold := order[kept - 1]
+	deepest := make(FlatPoints, 0, 2 * kept)
+	for i, d := range(depths) {
+		if d >= threshold {
+			deepest = append(deepest, points[2 * i], points[2 * i + 1])
+		}
+	}
+	return deepest
+}
+
+// Convex hull of the points as a closed ring, or the points themselves if there are fewer than three
+func convexRing (points FlatPoints) FlatPoints {
+	sorted := append(FlatPoints{}, points...)
+	sort.Sort(LexSorter(sorted))
+	hull := go_convex_hull_2d.NewFromSortedArray(sorted).(FlatPoints)
+	if hull.Len() < 3 {
+		return append(FlatPoints{}, hull...)
+	}
+	return closeRing(hull)
+}
+
+// Whether (x, y) lies on an edge of the closed ring
+func onRing (ring FlatPoints, x, y float64) bool {
+	for i := 0; i + 1 < ring.Len(); i++ {
+		x1, y1 := ring.Take(i)
+		x2, y2 := ring.Take(i + 1)
+		if orientation(x1, y1, x2, y2, x, y) == 0 && math.Min(x1, x2) <= x && x <= math.Max(x1, x2) && math.Min(y1, y2) <= y && y <= math.Max(y1, y2) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package ConcaveHull
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHalfspaceDepths (t *testing.T) {
+	// 3x3 grid, a line through the center in a generic direction leaves 4 points on each side, so it has depth 5. Corners have depth 1
+	points := FlatPoints{0, 0, 1, 0, 2, 0, 0, 1, 1, 1, 2, 1, 0, 2, 1, 2, 2, 2}
+	depths := halfspaceDepths(points, DEFAULT_DEPTH_DIRECTIONS)
+	assert.Equal(t, 5, depths[4])
+	assert.Equal(t, 1, depths[0])
+	assert.Equal(t, 1, depths[8])
+}
+
+func TestComputeBagplot (t *testing.T) {
+	r := rand.New(rand.NewSource(17))
+	var points FlatPoints
+	for i := 0; i < 1000; i++ {
+		points = append(points, r.NormFloat64(), r.NormFloat64())
+	}
+	points = append(points, 50, 50)
+	b := ComputeBagplot(points)
+	assert.InDelta(t, 0, b.MedianX, 0.2)
+	assert.InDelta(t, 0, b.MedianY, 0.2)
+	// half of a standard normal lies within radius 1.18
+	bagArea := math.Abs(SignedArea(b.Bag))
+	assert.InDelta(t, math.Pi * 1.18 * 1.18, bagArea, 1)
+	assert.InDelta(t, 9 * bagArea, math.Abs(SignedArea(b.Fence)), 1e-9)
+	assert.Contains(t, b.Outliers, 1000)
+	contour := DepthContour(points, 0.25)
+	inside := 0.
+	for i := 0; i < 1000; i++ {
+		if ringContains(contour, points[2 * i], points[2 * i + 1]) {
+			inside++
+		}
+	}
+	assert.InDelta(t, 250, inside, 20)
+}
@@ -0,0 +1,67 @@
+package ConcaveHull
+
+import "math"
+
+// Dimensionless shape measures of a polygon, all between 0 and 1
+type ShapeDescriptors struct {
+	Area float64
+	Perimeter float64
+	// Polsby-Popper score 4πA/P², 1 for a disc
+	Compactness float64
+	// Area over the area of the disc whose diameter is the diameter of the polygon, 1 for a disc
+	Circularity float64
+	// 1 - width / diameter, 0 for shapes as wide in every direction and close to 1 for thin ones
+	Elongation float64
+	// Area over the area of the convex hull, 1 for convex polygons
+	Solidity float64
+	// Area over the area of the minimum area enclosing rectangle, 1 for rectangles
+	Rectangularity float64
+}
+
+// Shape descriptors of a closed ring, such as a hull. Degenerate rings have zero descriptors
+func Descriptors (hull FlatPoints) (d ShapeDescriptors) {
+	ring := openRing(hull)
+	n := ring.Len()
+	if n < 3 {
+		return d
+	}
+	d.Area = math.Abs(SignedArea(ring))
+	for i := 0; i < n; i++ {
+		x1, y1 := ring.Take(i)
+		x2, y2 := ring.Take((i + 1) % n)
+		d.Perimeter += math.Hypot(x2 - x1, y2 - y1)
+	}
+	if d.Area == 0 {
+		return d
+	}
+	d.Compactness = 4 * math.Pi * d.Area / (d.Perimeter * d.Perimeter)
+	_, _, _, _, diameter := Diameter(ring)
+	width, _ := Width(ring)
+	d.Circularity = d.Area / (math.Pi * diameter * diameter / 4)
+	d.Elongation = 1 - width / diameter
+	convex := counterClockwiseHull(ring)
+	d.Solidity = d.Area / SignedArea(convex)
+	d.Rectangularity = d.Area / minimumRectangleArea(convex)
+	return d
+}
+
+// Area of the smallest rectangle enclosing an open convex ring, one of its sides is on an edge of the ring
+func minimumRectangleArea (convex FlatPoints) float64 {
+	n := convex.Len()
+	best := math.Inf(1)
+	for i := 0; i < n; i++ {
+		x1, y1 := convex.Take(i)
+		x2, y2 := convex.Take((i + 1) % n)
+		length := math.Hypot(x2 - x1, y2 - y1)
+		ux, uy := (x2 - x1) / length, (y2 - y1) / length
+		minU, maxU, maxV := 0., 0., 0.
+		for k := 0; k < n; k++ {
+			x, y := convex.Take(k)
+			u := (x - x1) * ux + (y - y1) * uy
+			v := math.Abs((x - x1) * uy - (y - y1) * ux)
+			minU, maxU, maxV = math.Min(minU, u), math.Max(maxU, u), math.Max(maxV, v)
+		}
+		best = math.Min(best, (maxU - minU) * maxV)
+	}
+	return best
+}
@@ -0,0 +1,26 @@
+package ConcaveHull
+
+import (
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescriptors (t *testing.T) {
+	square := Descriptors(FlatPoints{0, 0, 1, 0, 1, 1, 0, 1, 0, 0})
+	assert.InDelta(t, 1, square.Area, 1e-12)
+	assert.InDelta(t, 4, square.Perimeter, 1e-12)
+	assert.InDelta(t, math.Pi / 4, square.Compactness, 1e-12)
+	assert.InDelta(t, 2 / math.Pi, square.Circularity, 1e-12)
+	assert.InDelta(t, 1 - 1 / math.Sqrt2, square.Elongation, 1e-12)
+	assert.InDelta(t, 1, square.Solidity, 1e-12)
+	assert.InDelta(t, 1, square.Rectangularity, 1e-12)
+
+	// L shape, three quarters of a 2 by 2 square
+	l := Descriptors(FlatPoints{0, 0, 2, 0, 2, 1, 1, 1, 1, 2, 0, 2, 0, 0})
+	assert.InDelta(t, 3, l.Area, 1e-12)
+	assert.InDelta(t, 3 / 3.5, l.Solidity, 1e-12)
+	assert.InDelta(t, 0.75, l.Rectangularity, 1e-12)
+
+	assert.Equal(t, ShapeDescriptors{}, Descriptors(FlatPoints{0, 0, 1, 1, 0, 0}))
+}
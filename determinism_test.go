@@ -0,0 +1,70 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+// Bytes of every hull computed from the points with the options, through the single hull, cluster and series entry points
+func hullBytes (t *testing.T, points FlatPoints, o *Options) [][]byte {
+	var encoded [][]byte
+	add := func (h Hull) {
+		b, err := h.MarshalBinary()
+		assert.NoError(t, err)
+		encoded = append(encoded, b)
+	}
+	hull, err := ComputeContext(context.Background(), append(FlatPoints{}, points...), o)
+	assert.NoError(t, err)
+	add(hull)
+	for _, cluster := range(ComputeClusters(points, o)) {
+		add(cluster.Hull)
+	}
+	times := make([]time.Time, points.Len())
+	for i := range(times) {
+		times[i] = time.Unix(int64(i % 5) * 3600, 0)
+	}
+	series, err := ComputeSeries(points, times, time.Hour, o)
+	assert.NoError(t, err)
+	for _, s := range(series) {
+		add(s.Hull)
+	}
+	return encoded
+}
+
+// Outputs are byte for byte the same whatever GOMAXPROCS, Workers, SingleThreaded and the reuse of pooled buffers
+func TestDeterminism_parallelism (t *testing.T) {
+	r := rand.New(rand.NewSource(21))
+	var points FlatPoints
+	for k := 0; k < 6; k++ {
+		for _, v := range(hulltest.Random(r, 800)) {
+			points = append(points, v + float64(3 * k))
+		}
+	}
+	base := Options{Seglength: 0.03, ClusterDistance: 0.5, MinPoints: 3}
+	expected := hullBytes(t, points, &base)
+
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	// a pool warmed up by larger, unrelated computations hands out buffers with stale content
+	pool := &sync.Pool{}
+	warm := base
+	warm.ConcaveHullPool = pool
+	ComputeWithOptions(hulltest.Random(r, 20000), &warm)
+	for _, procs := range([]int{1, 2, 8}) {
+		runtime.GOMAXPROCS(procs)
+		for _, workers := range([]int{0, 1, 3, 16}) {
+			for _, singleThreaded := range([]bool{false, true}) {
+				for _, pooled := range([]*sync.Pool{nil, pool}) {
+					o := base
+					o.Workers, o.SingleThreaded, o.ConcaveHullPool = workers, singleThreaded, pooled
+					assert.Equal(t, expected, hullBytes(t, points, &o))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package ConcaveHull
+
+import (
+	"container/heap"
+	"math"
+	"github.com/USACE/concavehull/geomutil"
+	"github.com/furstenheim/go-convex-hull-2d"
+)
+
+// Algorithm used to compute the hull
+type Algorithm int
+
+const (
+	// Snap the edges of the convex hull to the closest points, then simplify with Douglas Peucker. Tuned with Seglength
+	AlgorithmSnapHull Algorithm = iota
+	// Port of the JTS ConcaveHull (2022): border triangles of the Delaunay triangulation are removed, longest border edge first,
+	// while their border edge is longer than EdgeLengthThreshold. The result is always a valid polygon containing all the points
+	AlgorithmEdgeLength
+)
+
+// Threshold of the edge length algorithm. The absolute threshold wins if set, the ratio defaults to 0, the most concave hull
+func edgeLengthThreshold (d *delaunay, o *Options) float64 {
+	if o.EdgeLengthThreshold > 0 {
+		return o.EdgeLengthThreshold
+	}
+	ratio := o.EdgeLengthRatio
+	if ratio <= 0 {
+		return 0
+	}
+	minLength, maxLength := math.Inf(1), 0.
+	for t := range(d.triangles) {
+		if !d.triangles[t].alive || d.isExternal(t) {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			l := d.edgeLength(t, k)
+			minLength, maxLength = math.Min(minLength, l), math.Max(maxLength, l)
+		}
+	}
+	if ratio >= 1 {
+		return 2 * maxLength
+	}
+	return minLength + ratio * (maxLength - minLength)
+}
+
+// Length of the edge of triangle t opposite to its vertex k
+func (d *delaunay) edgeLength (t, k int) float64 {
+	tri := &d.triangles[t]
+	x1, y1 := d.points.Take(tri.v[(k + 1) % 3])
+	x2, y2 := d.points.Take(tri.v[(k + 2) % 3])
+	return math.Hypot(x2 - x1, y2 - y1)
+}
+
+// Concave hull of sorted points with the edge length algorithm
+func edgeLengthHull (sorted FlatPoints, o *Options) FlatPoints {
+	points := uniqueSorted(sorted)
+	if points.Len() < 3 {
+		return points
+	}
+	d := newDelaunay(points)
+	threshold := edgeLengthThreshold(d, o)
+	// triangles that are part of the hull polygon
+	inside := make([]bool, len(d.triangles))
+	for t := range(d.triangles) {
+		inside[t] = d.triangles[t].alive && !d.isExternal(t)
+	}
+	isBorderEdge := func (t, k int) bool {
+		nb := d.triangles[t].n[k]
+		return nb == -1 || !inside[nb]
+	}
+	onBorder := make([]bool, d.nPoints)
+	for t := range(d.triangles) {
+		if !inside[t] {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			if isBorderEdge(t, k) {
+				onBorder[d.triangles[t].v[(k + 1) % 3]] = true
+				onBorder[d.triangles[t].v[(k + 2) % 3]] = true
+			}
+		}
+	}
+	// a triangle can be removed if it has a single border edge, longer than the threshold, and its opposite vertex is not
+	// on the border, otherwise the polygon would be split or a point left out
+	borderEdge := func (t int) (k int, length float64, removable bool) {
+		count := 0
+		for m := 0; m < 3; m++ {
+			if isBorderEdge(t, m) {
+				count++
+				k = m
+			}
+		}
+		if count != 1 || onBorder[d.triangles[t].v[k]] {
+			return k, 0, false
+		}
+		length = d.edgeLength(t, k)
+		return k, length, length > threshold
+	}
+	queue := &triangleQueue{}
+	for t := range(d.triangles) {
+		if !inside[t] {
+			continue
+		}
+		if _, length, removable := borderEdge(t); removable {
+			heap.Push(queue, triangleQueueItem{triangle: t, length: length})
+		}
+	}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(triangleQueueItem)
+		t := item.triangle
+		if !inside[t] {
+			continue
+		}
+		k, _, removable := borderEdge(t)
+		if !removable {
+			continue
+		}
+		inside[t] = false
+		onBorder[d.triangles[t].v[k]] = true
+		for m := 0; m < 3; m++ {
+			nb := d.triangles[t].n[m]
+			if nb == -1 || !inside[nb] {
+				continue
+			}
+			if _, length, removable := borderEdge(nb); removable {
+				heap.Push(queue, triangleQueueItem{triangle: nb, length: length})
+			}
+		}
+	}
+	var vertices [][2]float64
+	for i := 0; i < d.nPoints; i++ {
+		x, y := d.points.Take(i)
+		vertices = append(vertices, [2]float64{x, y})
+	}
+	var edges []geomutil.Edge
+	for t := range(d.triangles) {
+		if !inside[t] {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			if isBorderEdge(t, k) {
+				edges = append(edges, geomutil.Edge{From: d.triangles[t].v[(k + 1) % 3], To: d.triangles[t].v[(k + 2) % 3]})
+			}
+		}
+	}
+	rings := chainEdges(vertices, edges)
+	if len(rings) == 0 {
+		// collinear points have no triangles, their hull is the degenerate convex hull
+		return go_convex_hull_2d.NewFromSortedArray(append(FlatPoints{}, points...)).(FlatPoints)
+	}
+	var hull FlatPoints
+	for _, ring := range(rings) {
+		if SignedArea(ring) > SignedArea(hull) {
+			hull = ring
+		}
+	}
+	return hull
+}
+
+// Sorted points without repetitions
+func uniqueSorted (sorted FlatPoints) FlatPoints {
+	unique := make(FlatPoints, 0, len(sorted))
+	for i := 0; i < sorted.Len(); i++ {
+		x, y := sorted.Take(i)
+		if n := unique.Len(); n > 0 && unique[2 * n - 2] == x && unique[2 * n - 1] == y {
+			continue
+		}
+		unique = append(unique, x, y)
+	}
+	return unique
+}
+
+type triangleQueueItem struct {
+	triangle int
+	length float64
+}
+
+// Max heap of border triangles by the length of their border edge
+type triangleQueue []triangleQueueItem
+
+func (q triangleQueue) Len () int {
+	return len(q)
+}
+
+func (q triangleQueue) Less (i, j int) bool {
+	return q[i].length > q[j].length
+}
+
+func (q triangleQueue) Swap (i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *triangleQueue) Push (x interface{}) {
+	*q = append(*q, x.(triangleQueueItem))
+}
+
+func (q *triangleQueue) Pop () interface{} {
+	old := *q
+	item := old[len(old) - 1]
+	*q = old[:len(old) - 1]
+	return item
+}
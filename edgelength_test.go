@@ -0,0 +1,46 @@
+package ConcaveHull
+
+import (
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestDelaunay (t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := FlatPoints(hulltest.Random(r, 300))
+	d := newDelaunay(uniqueSorted(points))
+	// empty circumcircle property
+	for tr := range(d.triangles) {
+		if !d.triangles[tr].alive {
+			continue
+		}
+		assert.True(t, d.orient(d.triangles[tr].v[0], d.triangles[tr].v[1], d.triangles[tr].v[2]) > 0)
+		for p := 0; p < d.nPoints; p++ {
+			if d.inCircumcircle(tr, p) {
+				t.Fatalf("point %d is inside the circumcircle of triangle %d", p, tr)
+			}
+		}
+	}
+}
+
+func TestComputeWithOptions_edgeLength (t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	points := hulltest.Ring(r, 1000, 0.3, 0.5)
+	input := append([]float64{}, points...)
+	convex := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Algorithm: AlgorithmEdgeLength, EdgeLengthRatio: 1})
+	concave := ComputeWithOptions(FlatPoints(points), &Options{Algorithm: AlgorithmEdgeLength, EdgeLengthRatio: 0.1})
+	hulltest.AssertValid(t, input, convex)
+	hulltest.AssertValid(t, input, concave)
+	hulltest.AssertContains(t, input, concave, 1e-12)
+	assert.True(t, SignedArea(concave) < SignedArea(convex))
+}
+
+func TestComputeWithOptions_edgeLengthCollinear (t *testing.T) {
+	points := FlatPoints{2, 2, 0, 0, 3, 3, 1, 1}
+	snap := ComputeWithOptions(append(FlatPoints{}, points...), &Options{Seglength: 0.1})
+	edgeLength := ComputeWithOptions(points, &Options{Algorithm: AlgorithmEdgeLength})
+	assert.True(t, edgeLength.Len() > 0)
+	assert.Equal(t, snap, edgeLength)
+}
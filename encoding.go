@@ -0,0 +1,164 @@
+package ConcaveHull
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var ErrInvalidEncoding = errors.New("ConcaveHull: invalid binary encoding")
+
+// Version 2 added the stats and the warnings, version 1 hulls are still decoded
+var hullMagic = [4]byte{'C', 'H', 'H', '2'}
+var hullMagicV1 = [4]byte{'C', 'H', 'H', '1'}
+
+// Little endian float64 coordinates. Together with UnmarshalBinary it also provides gob encoding
+func (fp FlatPoints) MarshalBinary () ([]byte, error) {
+	return encodeFloats(fp), nil
+}
+
+func (fp *FlatPoints) UnmarshalBinary (data []byte) error {
+	if len(data) % 16 != 0 {
+		return ErrInvalidEncoding
+	}
+	*fp = decodeFloats(data)
+	return nil
+}
+
+// Binary encoding of the exported fields of the hull. Together with UnmarshalBinary it also provides gob encoding
+func (h Hull) MarshalBinary () ([]byte, error) {
+	data := append([]byte{}, hullMagic[:]...)
+	partial := byte(0)
+	if h.Partial {
+		partial = 1
+	}
+	data = append(data, partial)
+	data = appendFloats(data, h.Points)
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(h.Provenance)))
+	for _, k := range(h.Provenance) {
+		data = append(data, byte(k))
+	}
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(h.Dropped)))
+	for _, i := range(h.Dropped) {
+		data = binary.LittleEndian.AppendUint64(data, uint64(i))
+	}
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(h.Parts)))
+	for _, part := range(h.Parts) {
+		data = appendFloats(data, part)
+	}
+	data = binary.LittleEndian.AppendUint64(data, uint64(h.Stats.Probes))
+	data = binary.LittleEndian.AppendUint64(data, uint64(h.Stats.MaxStack))
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(h.Warnings)))
+	for _, w := range(h.Warnings) {
+		data = append(data, byte(w.Kind))
+		data = binary.LittleEndian.AppendUint64(data, uint64(len(w.Detail)))
+		data = append(data, w.Detail...)
+	}
+	return data, nil
+}
+
+func (h *Hull) UnmarshalBinary (data []byte) error {
+	if len(data) < 5 || [4]byte(data[:4]) != hullMagic && [4]byte(data[:4]) != hullMagicV1 {
+		return ErrInvalidEncoding
+	}
+	var decoded Hull
+	decoded.Partial = data[4] == 1
+	r := binaryReader{data: data[5:]}
+	decoded.Points = r.floats()
+	if n := r.length(1); n > 0 {
+		decoded.Provenance = make([]VertexKind, n)
+		for i := range(decoded.Provenance) {
+			decoded.Provenance[i] = VertexKind(r.data[i])
+		}
+		r.data = r.data[n:]
+	}
+	if n := r.length(8); n > 0 {
+		decoded.Dropped = make([]int, n)
+		for i := range(decoded.Dropped) {
+			decoded.Dropped[i] = int(r.uint64())
+		}
+	}
+	if n := r.length(8); n > 0 {
+		decoded.Parts = make([]FlatPoints, n)
+		for i := range(decoded.Parts) {
+			decoded.Parts[i] = r.floats()
+		}
+	}
+	if [4]byte(data[:4]) == hullMagic {
+		decoded.Stats.Probes, decoded.Stats.MaxStack = int(r.uint64()), int(r.uint64())
+		// a warning takes at least its kind and the length of its detail
+		if n := r.length(9); n > 0 {
+			decoded.Warnings = make([]Warning, n)
+			for i := range(decoded.Warnings) {
+				decoded.Warnings[i].Kind = WarningKind(r.byte())
+				detail := r.length(1)
+				if r.err == nil {
+					decoded.Warnings[i].Detail = string(r.data[:detail])
+					r.data = r.data[detail:]
+				}
+			}
+		}
+	}
+	if r.err != nil || len(r.data) != 0 {
+		return ErrInvalidEncoding
+	}
+	*h = decoded
+	return nil
+}
+
+// Number of values followed by the values
+func appendFloats (data []byte, values []float64) []byte {
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(values)))
+	for _, v := range(values) {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+	}
+	return data
+}
+
+// Reads length prefixed arrays, remembering the first error so that callers check only once
+type binaryReader struct {
+	data []byte
+	err error
+}
+
+func (r *binaryReader) uint64 () uint64 {
+	if r.err != nil || len(r.data) < 8 {
+		r.err = ErrInvalidEncoding
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(r.data)
+	r.data = r.data[8:]
+	return v
+}
+
+func (r *binaryReader) byte () byte {
+	if r.err != nil || len(r.data) < 1 {
+		r.err = ErrInvalidEncoding
+		return 0
+	}
+	v := r.data[0]
+	r.data = r.data[1:]
+	return v
+}
+
+// Length prefix of an array whose elements take size bytes, checked against the remaining data
+func (r *binaryReader) length (size int) int {
+	n := r.uint64()
+	if r.err == nil && n > uint64(len(r.data) / size) {
+		r.err = ErrInvalidEncoding
+	}
+	if r.err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+func (r *binaryReader) floats () FlatPoints {
+	n := r.length(8)
+	if r.err != nil || n == 0 {
+		return nil
+	}
+	values := decodeFloats(r.data[:8 * n])
+	r.data = r.data[8 * n:]
+	return values
+}
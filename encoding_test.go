@@ -0,0 +1,56 @@
+package ConcaveHull
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHull_binaryEncoding (t *testing.T) {
+	hull := Hull{
+		Points: FlatPoints{0, 0, 1, 0, 1, 1, 0, 0},
+		Partial: true,
+		Provenance: []VertexKind{VertexInput, VertexInterpolated, VertexInput, VertexInput},
+		Dropped: []int{3, 5},
+		Parts: []FlatPoints{{0, 0, 1, 0, 1, 1, 0, 0}},
+		Stats: SegmentizeStats{Probes: 120, MaxStack: 7},
+		Warnings: []Warning{{Kind: WarningProbeLimit, Detail: "100 probes reached"}, {Kind: WarningConvexHull}},
+	}
+	data, err := hull.MarshalBinary()
+	assert.NoError(t, err)
+	var decoded Hull
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, hull, decoded)
+	assert.Equal(t, ErrInvalidEncoding, decoded.UnmarshalBinary(data[:len(data) - 3]))
+}
+
+func TestHull_binaryEncodingV1 (t *testing.T) {
+	// encoded before the stats and the warnings were added
+	data := append([]byte("CHH1"), 1)
+	// points, then no provenance, dropped points or parts
+	for _, v := range([]uint64{2, math.Float64bits(1), math.Float64bits(2), 0, 0, 0}) {
+		data = binary.LittleEndian.AppendUint64(data, v)
+	}
+	var decoded Hull
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, Hull{Points: FlatPoints{1, 2}, Partial: true}, decoded)
+}
+
+func TestHull_gob (t *testing.T) {
+	hull := Hull{Points: FlatPoints{0, 0, 1, 0, 1, 1, 0, 0}, Provenance: make([]VertexKind, 4)}
+	var buffer bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buffer).Encode(hull))
+	var decoded Hull
+	assert.NoError(t, gob.NewDecoder(&buffer).Decode(&decoded))
+	assert.Equal(t, hull, decoded)
+
+	points := FlatPoints{1, 2, 3, 4}
+	buffer.Reset()
+	assert.NoError(t, gob.NewEncoder(&buffer).Encode(points))
+	var decodedPoints FlatPoints
+	assert.NoError(t, gob.NewDecoder(&buffer).Decode(&decodedPoints))
+	assert.Equal(t, points, decodedPoints)
+}
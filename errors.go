@@ -0,0 +1,180 @@
+package ConcaveHull
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime/debug"
+)
+
+// Returned by the functions that report errors when the points have an odd number of coordinates or non finite coordinates
+var ErrMalformedPoints = errors.New("ConcaveHull: malformed points")
+
+// Returned by the functions that report errors when a numeric option is not a finite number or is negative
+var ErrInvalidOptions = errors.New("ConcaveHull: invalid options")
+
+// Seglength or SeglengthRelative is invalid, or with Options.Strict, seglength was below the precision of the coordinates.
+// It is also an ErrInvalidOptions
+var ErrInvalidSeglength = fmt.Errorf("%w: seglength", ErrInvalidOptions)
+
+// Matched by *UnsortedError
+var ErrUnsortedInput = errors.New("ConcaveHull: points are not sorted")
+
+// With Options.Strict, the points don't span an area: there are fewer than three distinct points or they are collinear
+var ErrDegenerateInput = errors.New("ConcaveHull: degenerate input")
+
+// The deadline of the context passed, the error also matches context.DeadlineExceeded, or with Options.Strict, the
+// TimeBudget was spent
+var ErrTimeout = errors.New("ConcaveHull: timeout")
+
+// With Options.Strict, MaxProbes was reached
+var ErrBudgetExceeded = errors.New("ConcaveHull: budget exceeded")
+
+// Points given to ComputeFromSortedContext are not sorted lexicographically: point Index comes before point Index - 1
+type UnsortedError struct {
+	Index int
+}
+
+func (e *UnsortedError) Error () string {
+	return fmt.Sprintf("ConcaveHull: points are not sorted, point %d comes before point %d", e.Index, e.Index - 1)
+}
+
+func (e *UnsortedError) Is (target error) bool {
+	return target == ErrUnsortedInput
+}
+
+// Error of the context of a computation whose deadline passed, matching both ErrTimeout and the context error
+type timeoutError struct {
+	cause error
+}
+
+func (e *timeoutError) Error () string {
+	return ErrTimeout.Error() + ": " + e.cause.Error()
+}
+
+func (e *timeoutError) Is (target error) bool {
+	return target == ErrTimeout
+}
+
+func (e *timeoutError) Unwrap () error {
+	return e.cause
+}
+
+// Error to return for the error of a context, a timeoutError if the deadline passed
+func contextError (err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &timeoutError{cause: err}
+	}
+	return err
+}
+
+// A panic inside the computation, from this package or from a dependency, recovered so that it doesn't bring the process down
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error () string {
+	return fmt.Sprintf("ConcaveHull: internal failure: %v", e.Value)
+}
+
+// Deferred by the functions that report errors, turns a panic into a PanicError, or returns the InvariantError of Options.Debug
+func recoverPanic (err *error) {
+	if r := recover(); r != nil {
+		if invariant, ok := r.(*InvariantError); ok {
+			*err = invariant
+			return
+		}
+		*err = &PanicError{Value: r, Stack: debug.Stack()}
+	}
+}
+
+func validatePoints (points FlatPoints) error {
+	if len(points) % 2 != 0 {
+		return fmt.Errorf("%w: odd number of coordinates %d", ErrMalformedPoints, len(points))
+	}
+	for i, v := range(points) {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: coordinate %d of point %d is %v", ErrMalformedPoints, i % 2, i / 2, v)
+		}
+	}
+	return nil
+}
+
+// Nil if the points are sorted lexicographically by (x,y), as the ComputeFromSorted functions expect, an *UnsortedError otherwise
+func CheckSorted (points FlatPoints) error {
+	s := LexSorter(points)
+	for i := 1; i < s.Len(); i++ {
+		if s.Less(i, i - 1) {
+			return &UnsortedError{Index: i}
+		}
+	}
+	return nil
+}
+
+func validateOptions (o *Options) error {
+	if o == nil {
+		return nil
+	}
+	for _, option := range([]struct{ name string; value float64 }{
+		{"Seglength", o.Seglength},
+		{"SeglengthRelative", o.SeglengthRelative},
+	}) {
+		if math.IsNaN(option.value) || math.IsInf(option.value, 0) || option.value < 0 {
+			return fmt.Errorf("%w, %s is %v", ErrInvalidSeglength, option.name, option.value)
+		}
+	}
+	for _, option := range([]struct{ name string; value float64 }{
+		{"SearchEpsilon", o.SearchEpsilon},
+		{"SearchEpsilonRelative", o.SearchEpsilonRelative},
+		{"MaxEdgeLength", o.MaxEdgeLength},
+		{"SmoothWindow", o.SmoothWindow},
+		{"WeldTolerance", o.WeldTolerance},
+		{"MaxDepth", o.MaxDepth},
+		{"GridSize", o.GridSize},
+		{"ScaleX", o.ScaleX},
+		{"ScaleY", o.ScaleY},
+		{"DensityThreshold", o.DensityThreshold},
+		{"OutlierRejection", o.OutlierRejection},
+		{"TrimFraction", o.TrimFraction},
+		{"BridgeWidth", o.BridgeWidth},
+		{"EdgeLengthThreshold", o.EdgeLengthThreshold},
+	}) {
+		if math.IsNaN(option.value) || math.IsInf(option.value, 0) || option.value < 0 {
+			return fmt.Errorf("%w: %s is %v", ErrInvalidOptions, option.name, option.value)
+		}
+	}
+	if b := o.ClipInputBBox; b != nil {
+		for _, v := range([]float64{b.MinX, b.MinY, b.MaxX, b.MaxY}) {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return fmt.Errorf("%w: ClipInputBBox is %v", ErrInvalidOptions, *b)
+			}
+		}
+		if b.MinX > b.MaxX || b.MinY > b.MaxY {
+			return fmt.Errorf("%w: ClipInputBBox is %v", ErrInvalidOptions, *b)
+		}
+	}
+	if o.MaxVertices < 0 {
+		return fmt.Errorf("%w: MaxVertices is %d", ErrInvalidOptions, o.MaxVertices)
+	}
+	if o.MinVertices < 0 {
+		return fmt.Errorf("%w: MinVertices is %d", ErrInvalidOptions, o.MinVertices)
+	}
+	if o.MaxBisectionDepth < 0 || o.MaxBisectionDepth > 52 {
+		return fmt.Errorf("%w: MaxBisectionDepth is %d", ErrInvalidOptions, o.MaxBisectionDepth)
+	}
+	if o.CheckpointInterval < 0 {
+		return fmt.Errorf("%w: CheckpointInterval is %v", ErrInvalidOptions, o.CheckpointInterval)
+	}
+	if o.MaxProbes < 0 {
+		return fmt.Errorf("%w: MaxProbes is %d", ErrInvalidOptions, o.MaxProbes)
+	}
+	if o.CompatVersion < 0 || o.CompatVersion > CompatLatest {
+		return fmt.Errorf("%w: CompatVersion is %d", ErrInvalidOptions, o.CompatVersion)
+	}
+	if o.RTreeNodeSize < 0 || o.RTreeNodeSize == 1 {
+		return fmt.Errorf("%w: RTreeNodeSize is %d", ErrInvalidOptions, o.RTreeNodeSize)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package ConcaveHull
+
+import (
+	"math"
+	"math/big"
+)
+
+// Sign of the orientation of (a, b, c) computed exactly: positive if c is to the left of a->b, negative to the right, 0 if collinear
+func ExactOrientation (ax, ay, bx, by, cx, cy float64) int {
+	// (b - a) x (c - a)
+	left := new(big.Rat).Mul(exactSub(bx, ax), exactSub(cy, ay))
+	right := new(big.Rat).Mul(exactSub(by, ay), exactSub(cx, ax))
+	return left.Cmp(right)
+}
+
+func exactSub (a, b float64) *big.Rat {
+	r := new(big.Rat).SetFloat64(a)
+	return r.Sub(r, new(big.Rat).SetFloat64(b))
+}
+
+func exactSquaredDistance (x1, y1, x2, y2 float64) *big.Rat {
+	dx, dy := exactSub(x1, x2), exactSub(y1, y2)
+	dx.Mul(dx, dx)
+	return dx.Add(dx, dy.Mul(dy, dy))
+}
+
+// Open counter clockwise convex hull of sorted points with exact orientation tests, collinear points are not vertices
+func exactConvexHull (sorted FlatPoints) FlatPoints {
+	n := sorted.Len()
+	if n < 3 {
+		return append(FlatPoints{}, sorted...)
+	}
+	hull := make([]int, 0, 2 * n)
+	turnsLeft := func (i int) bool {
+		ax, ay := sorted.Take(hull[len(hull) - 2])
+		bx, by := sorted.Take(hull[len(hull) - 1])
+		cx, cy := sorted.Take(i)
+		return ExactOrientation(ax, ay, bx, by, cx, cy) > 0
+	}
+	// Andrew's monotone chain, lower then upper hull
+	for i := 0; i < n; i++ {
+		for len(hull) >= 2 && !turnsLeft(i) {
+			hull = hull[:len(hull) - 1]
+		}
+		hull = append(hull, i)
+	}
+	lower := len(hull) + 1
+	for i := n - 2; i >= 0; i-- {
+		for len(hull) >= lower && !turnsLeft(i) {
+			hull = hull[:len(hull) - 1]
+		}
+		hull = append(hull, i)
+	}
+	hull = hull[:len(hull) - 1]
+	result := make(FlatPoints, 0, 2 * len(hull))
+	for _, i := range(hull) {
+		result = append(result, sorted[2 * i], sorted[2 * i + 1])
+	}
+	return result
+}
+
+// Closest point to (x, y) that is not farther than either (lx, ly) or (rx, ry), compared exactly. Ties are broken
+// lexicographically so that the result doesn't depend on the layout of the grid
+func (g *gridIndex) nearestExact (x, y, lx, ly, rx, ry float64) (nearestX, nearestY float64, found bool) {
+	best := exactSquaredDistance(x, y, lx, ly)
+	if r := exactSquaredDistance(x, y, rx, ry); r.Cmp(best) < 0 {
+		best = r
+	}
+	// rounded up bound of the search radius, only used to stop visiting rings of cells. Measured with Hypot, the squared
+	// distance overflows float64 for coordinates of extreme magnitudes
+	bound := math.Min(math.Hypot(lx - x, ly - y), math.Hypot(rx - x, ry - y)) * (1 + 1e-9)
+	cx, cy := g.cell(x, y)
+	maxRing := intMax(intMax(cx, g.nx - 1 - cx), intMax(cy, g.ny - 1 - cy))
+	for ring := 0; ring <= maxRing; ring++ {
+		for i := cx - ring; i <= cx + ring; i++ {
+			if i < 0 || i >= g.nx {
+				continue
+			}
+			for j := cy - ring; j <= cy + ring; j++ {
+				if j < 0 || j >= g.ny || (i != cx - ring && i != cx + ring && j != cy - ring && j != cy + ring) {
+					continue
+				}
+				c := j * g.nx + i
+				for k := g.start[c]; k < g.start[c + 1]; k++ {
+					px, py := g.points.Take(k)
+					d := exactSquaredDistance(x, y, px, py)
+					cmp := d.Cmp(best)
+					if cmp < 0 || (cmp == 0 && (!found || px < nearestX || (px == nearestX && py < nearestY))) {
+						best, nearestX, nearestY, found = d, px, py, true
+					}
+				}
+			}
+		}
+		if float64(ring) * g.cellSize > bound {
+			break
+		}
+	}
+	return
+}
+
+// Douglas Peucker simplification comparing distances to the tolerance exactly. An infinite tolerance keeps only the end
+// points, a negative or NaN one keeps every vertex off the line through its neighbours
+func exactSimplify (points FlatPoints, tolerance float64) FlatPoints {
+	n := points.Len()
+	if n < 3 {
+		return append(FlatPoints{}, points...)
+	}
+	if math.IsInf(tolerance, 1) {
+		return FlatPoints{points[0], points[1], points[2 * n - 2], points[2 * n - 1]}
+	}
+	if math.IsNaN(tolerance) || tolerance < 0 {
+		tolerance = 0
+	}
+	keep := make([]bool, n)
+	keep[0], keep[n - 1] = true, true
+	tolerance2 := new(big.Rat).SetFloat64(tolerance)
+	tolerance2.Mul(tolerance2, tolerance2)
+	type span struct{ first, last int }
+	stack := []span{{0, n - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack) - 1]
+		stack = stack[:len(stack) - 1]
+		if s.last - s.first < 2 {
+			continue
+		}
+		ax, ay := points.Take(s.first)
+		bx, by := points.Take(s.last)
+		length2 := exactSquaredDistance(ax, ay, bx, by)
+		farthest, best := -1, new(big.Rat)
+		for i := s.first + 1; i < s.last; i++ {
+			px, py := points.Take(i)
+			var d *big.Rat
+			if length2.Sign() == 0 {
+				d = exactSquaredDistance(ax, ay, px, py)
+			} else {
+				// squared distance to the line, cross² / length²
+				cross := new(big.Rat).Sub(new(big.Rat).Mul(exactSub(bx, ax), exactSub(py, ay)), new(big.Rat).Mul(exactSub(by, ay), exactSub(px, ax)))
+				d = cross.Mul(cross, cross)
+				d.Quo(d, length2)
+			}
+			if d.Cmp(best) > 0 {
+				farthest, best = i, d
+			}
+		}
+		if farthest >= 0 && best.Cmp(tolerance2) > 0 {
+			keep[farthest] = true
+			stack = append(stack, span{s.first, farthest}, span{farthest, s.last})
+		}
+	}
+	simplified := make(FlatPoints, 0, len(points))
+	for i, k := range(keep) {
+		if k {
+			simplified = append(simplified, points[2 * i], points[2 * i + 1])
+		}
+	}
+	return simplified
+}
@@ -0,0 +1,60 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestExactOrientation (t *testing.T) {
+	// products overflow float64
+	assert.True(t, math.IsNaN(orientation(0, 0, 1e200, 1e200, 2e200, 2e200)))
+	assert.Equal(t, 0, ExactOrientation(0, 0, 1e200, 1e200, 2e200, 2e200))
+	assert.Equal(t, 1, ExactOrientation(0, 0, 1e200, 1e200, 2e200, 3e200))
+	assert.Equal(t, -1, ExactOrientation(0, 0, 1e200, 1e200, 3e200, 2e200))
+	// one ulp off the line
+	assert.Equal(t, 1, ExactOrientation(0.5, 0.5, 12, 12, 24, math.Nextafter(24, 25)))
+}
+
+func TestComputeWithOptions_exactArithmetic (t *testing.T) {
+	r := rand.New(rand.NewSource(19))
+	points := hulltest.Ring(r, 1000, 0.3, 0.5)
+	expected := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.02})
+	input := append([]float64{}, points...)
+	result := ComputeWithOptions(FlatPoints(points), &Options{Seglength: 0.02, ExactArithmetic: true})
+	hulltest.AssertValid(t, input, result)
+	assert.Equal(t, expected, result)
+}
+
+func TestComputeContext_exactArithmeticExtremeMagnitudes (t *testing.T) {
+	// squared differences overflow float64: a square with a notch in its bottom edge
+	for _, scale := range([]float64{1e160, 1e300}) {
+		square := FlatPoints{0, 0, 1, 0, 1, 1, 0, 1, 0.5, 0.1}
+		for i := range(square) {
+			square[i] *= scale
+		}
+		notched := FlatPoints{0, 0, 0.5 * scale, 0.1 * scale, scale, 0, scale, scale, 0, scale, 0, 0}
+		for _, o := range([]*Options{
+			{Seglength: scale / 100, ExactArithmetic: true},
+			{SeglengthRelative: 0.01, ExactArithmetic: true},
+		}) {
+			hull, err := ComputeContext(context.Background(), append(FlatPoints{}, square...), o)
+			assert.NoError(t, err)
+			assert.Len(t, hull.Warnings, 0)
+			assert.Equal(t, notched, hull.Points)
+		}
+		hull, err := ComputeContext(context.Background(), append(FlatPoints{}, square...), &Options{Seglength: scale / 100, MaxVertices: 4, ExactArithmetic: true})
+		assert.NoError(t, err)
+		assert.Equal(t, FlatPoints{0, 0, scale, 0, scale, scale, 0, scale, 0, 0}, hull.Points)
+	}
+}
+
+func TestExactSimplify_nonFiniteTolerance (t *testing.T) {
+	ring := FlatPoints{0, 0, 1e200, 0, 1e200, 1e200, 0, 1e200, 0, 0}
+	assert.Equal(t, FlatPoints{0, 0, 0, 0}, exactSimplify(ring, math.Inf(1)))
+	assert.Equal(t, ring, exactSimplify(ring, math.NaN()))
+	assert.Equal(t, ring, exactSimplify(ring, -1))
+}
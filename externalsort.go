@@ -0,0 +1,250 @@
+package ConcaveHull
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// Memory an ExternalSorter holds points in before spilling them to a temporary file, 256 MiB
+const DEFAULT_SORT_MEMORY_BUDGET = 256 << 20
+
+// Runs merged at once, more are merged in several passes so that the number of open files stays bounded
+const externalSortFanIn = 64
+
+// Lexicographic sort of more points than fit in memory. Points are added one at a time, for example from ScanCSV or ScanLAS,
+// sorted in runs filling the memory budget that are spilled to temporary files, and merged when read back.
+// WriteSorted writes them in the StreamBinary format, which is the memory layout of FlatPoints on little endian machines, so
+// the file can be memory mapped and passed to ComputeFromSortedWithOptions or StreamFromSorted without being loaded.
+// Not safe for concurrent use, Close removes the temporary files
+type ExternalSorter struct {
+	dir string
+	// points sorted in memory before they are spilled
+	runLength int
+	buffer FlatPoints
+	runs []*os.File
+	count int
+}
+
+// Sorter keeping at most about memoryBudget bytes of points in memory, DEFAULT_SORT_MEMORY_BUDGET if 0, and its temporary
+// files in dir, os.TempDir() if empty
+func NewExternalSorter (memoryBudget int, dir string) *ExternalSorter {
+	if memoryBudget <= 0 {
+		memoryBudget = DEFAULT_SORT_MEMORY_BUDGET
+	}
+	runLength := memoryBudget / 16
+	if runLength < 1 {
+		runLength = 1
+	}
+	return &ExternalSorter{dir: dir, runLength: runLength}
+}
+
+// Add a point, spilling the points in memory to a temporary file when the budget is reached. Has the signature of the
+// callbacks of ScanCSV and ScanLAS
+func (s *ExternalSorter) Add (x, y float64) error {
+	if len(s.buffer) == cap(s.buffer) {
+		// grow within the budget instead of doubling past it
+		size := 2 * cap(s.buffer)
+		if size < 1024 {
+			size = 1024
+		}
+		if size > 2 * s.runLength {
+			size = 2 * s.runLength
+		}
+		s.buffer = append(make(FlatPoints, 0, size), s.buffer...)
+	}
+	s.buffer = append(s.buffer, x, y)
+	s.count++
+	if s.buffer.Len() >= s.runLength {
+		return s.spill()
+	}
+	return nil
+}
+
+// Number of points added
+func (s *ExternalSorter) Len () int {
+	return s.count
+}
+
+// Call f with the points in lexicographic order, stopping at the first error. Points added afterwards are sorted with the
+// others by the next call
+func (s *ExternalSorter) Scan (f func (x, y float64) error) error {
+	if len(s.runs) == 0 {
+		sort.Sort(LexSorter(s.buffer))
+		for i := 0; i < s.buffer.Len(); i++ {
+			if err := f(s.buffer.Take(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if s.buffer.Len() > 0 {
+		if err := s.spill(); err != nil {
+			return err
+		}
+	}
+	for len(s.runs) > externalSortFanIn {
+		merged, err := s.writeRun(func (f func (x, y float64) error) error {
+			return mergeRuns(s.runs[:externalSortFanIn], f)
+		})
+		if err != nil {
+			return err
+		}
+		removeRuns(s.runs[:externalSortFanIn])
+		s.runs = append(s.runs[externalSortFanIn:], merged)
+	}
+	return mergeRuns(s.runs, f)
+}
+
+// Write the points in lexicographic order to w, see Scan
+func (s *ExternalSorter) WriteSorted (w io.Writer, format StreamFormat) error {
+	pw := newPointWriter(w, format)
+	err := s.Scan(func (x, y float64) error {
+		pw.write(x, y)
+		return pw.err
+	})
+	if err != nil {
+		return err
+	}
+	return pw.flush()
+}
+
+// Remove the temporary files and forget the points
+func (s *ExternalSorter) Close () error {
+	err := removeRuns(s.runs)
+	s.runs, s.buffer, s.count = nil, nil, 0
+	return err
+}
+
+// Sort the points in memory and write them to a new run
+func (s *ExternalSorter) spill () error {
+	sort.Sort(LexSorter(s.buffer))
+	run, err := s.writeRun(func (f func (x, y float64) error) error {
+		for i := 0; i < s.buffer.Len(); i++ {
+			if err := f(s.buffer.Take(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, run)
+	s.buffer = s.buffer[:0]
+	return nil
+}
+
+// Temporary file of the points passed by scan, in StreamBinary
+func (s *ExternalSorter) writeRun (scan func (f func (x, y float64) error) error) (*os.File, error) {
+	file, err := os.CreateTemp(s.dir, "concavehull-sort-*")
+	if err != nil {
+		return nil, err
+	}
+	pw := newPointWriter(file, StreamBinary)
+	err = scan(func (x, y float64) error {
+		pw.write(x, y)
+		return pw.err
+	})
+	if err == nil {
+		err = pw.flush()
+	}
+	if err != nil {
+		removeRuns([]*os.File{file})
+		return nil, err
+	}
+	return file, nil
+}
+
+// Close and remove the files of the runs, returning the first error
+func removeRuns (runs []*os.File) error {
+	var first error
+	for _, run := range(runs) {
+		if err := run.Close(); err != nil && first == nil {
+			first = err
+		}
+		if err := os.Remove(run.Name()); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Call f with the points of the sorted runs in lexicographic order
+func mergeRuns (runs []*os.File, f func (x, y float64) error) error {
+	cursors := make(runCursors, 0, len(runs))
+	for _, run := range(runs) {
+		if _, err := run.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		cursor := &runCursor{r: bufio.NewReaderSize(run, 1 << 16)}
+		if ok, err := cursor.next(); err != nil {
+			return err
+		} else if ok {
+			cursors = append(cursors, cursor)
+		}
+	}
+	heap.Init(&cursors)
+	for cursors.Len() > 0 {
+		cursor := cursors[0]
+		if err := f(cursor.x, cursor.y); err != nil {
+			return err
+		}
+		if ok, err := cursor.next(); err != nil {
+			return err
+		} else if ok {
+			heap.Fix(&cursors, 0)
+		} else {
+			heap.Pop(&cursors)
+		}
+	}
+	return nil
+}
+
+// Next point of a run being merged
+type runCursor struct {
+	r *bufio.Reader
+	x, y float64
+}
+
+// Read the next point, false at the end of the run
+func (c *runCursor) next () (bool, error) {
+	var buffer [16]byte
+	if _, err := io.ReadFull(c.r, buffer[:]); err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	c.x = math.Float64frombits(binary.LittleEndian.Uint64(buffer[:8]))
+	c.y = math.Float64frombits(binary.LittleEndian.Uint64(buffer[8:]))
+	return true, nil
+}
+
+type runCursors []*runCursor
+
+func (c runCursors) Less (i, j int) bool {
+	return c[i].x < c[j].x || c[i].x == c[j].x && c[i].y < c[j].y
+}
+
+func (c runCursors) Len () int {
+	return len(c)
+}
+
+func (c runCursors) Swap (i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
+
+func (c *runCursors) Push (x interface{}) {
+	*c = append(*c, x.(*runCursor))
+}
+
+func (c *runCursors) Pop () interface{} {
+	old := *c
+	cursor := old[len(old) - 1]
+	*c = old[:len(old) - 1]
+	return cursor
+}
@@ -0,0 +1,59 @@
+package ConcaveHull
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalSorter (t *testing.T) {
+	r := rand.New(rand.NewSource(29))
+	var points FlatPoints
+	for i := 0; i < 1500; i++ {
+		// few distinct x so that y breaks ties across runs
+		points = append(points, float64(r.Intn(50)), r.Float64())
+	}
+	dir := t.TempDir()
+	// 10 points per run, 150 runs merged in two passes
+	sorter := NewExternalSorter(160, dir)
+	for i := 0; i < points.Len(); i++ {
+		assert.NoError(t, sorter.Add(points.Take(i)))
+	}
+	assert.Equal(t, points.Len(), sorter.Len())
+	expected := append(FlatPoints{}, points...)
+	sort.Sort(LexSorter(expected))
+	var buffer bytes.Buffer
+	assert.NoError(t, sorter.WriteSorted(&buffer, StreamBinary))
+	assert.Equal(t, expected, decodeFloats(buffer.Bytes()))
+
+	// added points are merged with the others
+	assert.NoError(t, sorter.Add(-1, 0))
+	var scanned FlatPoints
+	assert.NoError(t, sorter.Scan(func (x, y float64) error {
+		scanned = append(scanned, x, y)
+		return nil
+	}))
+	assert.Equal(t, append(FlatPoints{-1, 0}, expected...), scanned)
+
+	assert.NoError(t, sorter.Close())
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 0)
+}
+
+func TestExternalSorter_inMemory (t *testing.T) {
+	dir := t.TempDir()
+	sorter := NewExternalSorter(0, dir)
+	for _, p := range([][2]float64{{2, 1}, {1, 3}, {1, 2}}) {
+		assert.NoError(t, sorter.Add(p[0], p[1]))
+	}
+	var buffer bytes.Buffer
+	assert.NoError(t, sorter.WriteSorted(&buffer, StreamText))
+	assert.Equal(t, "1 2\n1 3\n2 1\n", buffer.String())
+	files, _ := os.ReadDir(dir)
+	assert.Len(t, files, 0)
+	assert.NoError(t, sorter.Close())
+}
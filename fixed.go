@@ -0,0 +1,83 @@
+package ConcaveHull
+
+import (
+	"fmt"
+	"math"
+)
+
+// Largest integer coordinate that float64 represents exactly, along with all the integers below it
+const maxExactInteger = 1 << 53
+
+// Interleaved integer coordinates quantized to a resolution, such as millimetres with Scale 0.001: point i is
+// Data[2 * i] * Scale, Data[2 * i + 1] * Scale. As a PointSource it gives the scaled coordinates
+type FixedPoints struct {
+	Data []int64
+	Scale float64
+}
+
+func (f FixedPoints) Len () int {
+	return len(f.Data) / 2
+}
+
+func (f FixedPoints) Take (i int) (x, y float64) {
+	return float64(f.Data[2 * i]) * f.Scale, float64(f.Data[2 * i + 1]) * f.Scale
+}
+
+// Same as FixedPoints with 32 bit integers, which halves the memory of large datasets
+type FixedPoints32 struct {
+	Data []int32
+	Scale float64
+}
+
+func (f FixedPoints32) Len () int {
+	return len(f.Data) / 2
+}
+
+func (f FixedPoints32) Take (i int) (x, y float64) {
+	return float64(f.Data[2 * i]) * f.Scale, float64(f.Data[2 * i + 1]) * f.Scale
+}
+
+// Concave hull of fixed point coordinates, in the same integer units and scale. This is a conversion convenience: the integers
+// are copied to float64, which holds them exactly, and the usual computation runs on that copy, so the lengths of the options
+// are in integer units and the scale plays no part in it. Vertices added by options such as MaxEdgeLength are rounded.
+// Coordinates beyond 2^53 in absolute value are reported as ErrMalformedPoints
+func ComputeFixed (points FixedPoints, o *Options) (FixedPoints, error) {
+	hull, err := computeIntegers(len(points.Data), func (i int) int64 { return points.Data[i] }, o)
+	if err != nil {
+		return FixedPoints{}, err
+	}
+	ring := FixedPoints{Data: make([]int64, len(hull)), Scale: points.Scale}
+	for i, v := range(hull) {
+		ring.Data[i] = int64(math.Round(v))
+	}
+	return ring, nil
+}
+
+// Same as ComputeFixed for 32 bit coordinates
+func ComputeFixed32 (points FixedPoints32, o *Options) (FixedPoints32, error) {
+	hull, err := computeIntegers(len(points.Data), func (i int) int64 { return int64(points.Data[i]) }, o)
+	if err != nil {
+		return FixedPoints32{}, err
+	}
+	ring := FixedPoints32{Data: make([]int32, len(hull)), Scale: points.Scale}
+	for i, v := range(hull) {
+		ring.Data[i] = int32(math.Round(v))
+	}
+	return ring, nil
+}
+
+// Hull of the n interleaved integer coordinates given by at, computed on a float64 copy of them
+func computeIntegers (n int, at func (i int) int64, o *Options) (FlatPoints, error) {
+	if n % 2 != 0 {
+		return nil, fmt.Errorf("%w: odd number of coordinates %d", ErrMalformedPoints, n)
+	}
+	points := make(FlatPoints, n)
+	for i := range(points) {
+		v := at(i)
+		if v > maxExactInteger || v < -maxExactInteger {
+			return nil, fmt.Errorf("%w: coordinate %d is %d", ErrMalformedPoints, i, v)
+		}
+		points[i] = float64(v)
+	}
+	return ComputeWithOptions(points, o), nil
+}
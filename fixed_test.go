@@ -0,0 +1,50 @@
+package ConcaveHull
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestComputeFixed (t *testing.T) {
+	// millimetres of a survey in UTM, where the scaled values are not exact in float64
+	r := rand.New(rand.NewSource(4))
+	random := hulltest.Random(r, 1000)
+	data := make([]int64, len(random))
+	for i, v := range(random) {
+		data[i] = 500000000 + int64(v * 100000)
+	}
+	points := FixedPoints{Data: data, Scale: 0.001}
+	hull, err := ComputeFixed(points, &Options{Seglength: 2000})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.001, hull.Scale)
+	assert.True(t, hull.Len() > 4)
+	inputs := map[[2]int64]bool{}
+	for i := 0; i < len(data); i += 2 {
+		inputs[[2]int64{data[i], data[i + 1]}] = true
+	}
+	for i := 0; i < len(hull.Data); i += 2 {
+		assert.True(t, inputs[[2]int64{hull.Data[i], hull.Data[i + 1]}])
+	}
+	x, y := hull.Take(0)
+	assert.InDelta(t, float64(hull.Data[0]) / 1000, x, 1e-6)
+	assert.InDelta(t, float64(hull.Data[1]) / 1000, y, 1e-6)
+
+	data32 := make([]int32, len(data))
+	for i, v := range(data) {
+		data32[i] = int32(v - 500000000)
+	}
+	hull32, err := ComputeFixed32(FixedPoints32{Data: data32, Scale: 0.001}, &Options{Seglength: 2000})
+	assert.NoError(t, err)
+	assert.Equal(t, len(hull.Data), len(hull32.Data))
+	for i := range(hull32.Data) {
+		assert.Equal(t, hull.Data[i] - 500000000, int64(hull32.Data[i]))
+	}
+
+	_, err = ComputeFixed(FixedPoints{Data: []int64{1 << 60, 0}}, nil)
+	assert.True(t, errors.Is(err, ErrMalformedPoints))
+	_, err = ComputeFixed32(FixedPoints32{Data: []int32{1, 2, 3}}, nil)
+	assert.True(t, errors.Is(err, ErrMalformedPoints))
+}
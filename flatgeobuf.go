@@ -0,0 +1,475 @@
+package ConcaveHull
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+var ErrInvalidFlatGeobuf = errors.New("ConcaveHull: invalid FlatGeobuf")
+
+var flatGeobufMagic = [8]byte{'f', 'g', 'b', 3, 'f', 'g', 'b', 0}
+
+// Geometry types of FlatGeobuf
+const (
+	fgbUnknown = 0
+	fgbPoint = 1
+	fgbPolygon = 3
+	fgbMultiPoint = 4
+)
+
+// Fields of the FlatGeobuf tables, in schema order
+const (
+	fgbHeaderName = 0
+	fgbHeaderEnvelope = 1
+	fgbHeaderGeometryType = 2
+	fgbHeaderFeaturesCount = 8
+	fgbHeaderIndexNodeSize = 9
+	fgbHeaderCrs = 10
+	fgbFeatureGeometry = 0
+	fgbGeometryXY = 1
+	fgbGeometryType = 6
+	fgbCrsCode = 1
+)
+
+// Default of the header field index_node_size
+const fgbDefaultNodeSize = 16
+// Bytes of a node of the packed Hilbert R-tree: bounding box and offset
+const fgbNodeItemSize = 40
+
+type FlatGeobufOptions struct {
+	Name string // name of the dataset, written in the header
+	CRSCode int // EPSG code of the coordinates, for example 4326, 0 if unknown
+}
+
+// Read the coordinates of a FlatGeobuf file of Point or MultiPoint features. If bbox, as minX, minY, maxX, maxY, is given,
+// only points inside it are returned and the spatial index of the file, if any, is used to read only the features that
+// may intersect it. The file is read sequentially
+func ReadFlatGeobuf (r io.Reader, bbox *[4]float64) (FlatPoints, error) {
+	var magic [8]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil || [7]byte(magic[:7]) != [7]byte(flatGeobufMagic[:7]) {
+		return nil, ErrInvalidFlatGeobuf
+	}
+	headerData, err := readSizePrefixed(r)
+	if err != nil {
+		return nil, err
+	}
+	header := fbRoot(headerData)
+	geometryType := header.uint8(fgbHeaderGeometryType, fgbUnknown)
+	featuresCount := header.uint64(fgbHeaderFeaturesCount, 0)
+	nodeSize := uint64(header.uint16(fgbHeaderIndexNodeSize, fgbDefaultNodeSize))
+	if header.buffer.err != nil {
+		return nil, header.buffer.err
+	}
+	var offsets []uint64
+	filtered := false
+	if nodeSize > 0 && featuresCount > 0 {
+		if nodeSize < 2 || featuresCount > math.MaxUint32 {
+			return nil, ErrInvalidFlatGeobuf
+		}
+		levels, numNodes := packedRTreeLevels(featuresCount, nodeSize)
+		if bbox == nil {
+			if _, err := io.CopyN(io.Discard, r, int64(numNodes * fgbNodeItemSize)); err != nil {
+				return nil, ErrInvalidFlatGeobuf
+			}
+		} else {
+			index := make([]byte, numNodes * fgbNodeItemSize)
+			if _, err := io.ReadFull(r, index); err != nil {
+				return nil, ErrInvalidFlatGeobuf
+			}
+			if offsets, err = searchPackedRTree(index, levels, nodeSize, *bbox); err != nil {
+				return nil, err
+			}
+			filtered = true
+		}
+	}
+	var points FlatPoints
+	var offset uint64
+	for next := 0; !filtered || next < len(offsets); {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, ErrInvalidFlatGeobuf
+		}
+		featureSize := uint64(binary.LittleEndian.Uint32(size[:]))
+		if filtered && offsets[next] != offset {
+			if offsets[next] < offset {
+				return nil, ErrInvalidFlatGeobuf
+			}
+			if _, err := io.CopyN(io.Discard, r, int64(featureSize)); err != nil {
+				return nil, ErrInvalidFlatGeobuf
+			}
+			offset += 4 + featureSize
+			continue
+		}
+		data := make([]byte, featureSize)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, ErrInvalidFlatGeobuf
+		}
+		offset += 4 + featureSize
+		next++
+		if points, err = appendFeaturePoints(points, data, geometryType, bbox); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func readSizePrefixed (r io.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, ErrInvalidFlatGeobuf
+	}
+	data := make([]byte, binary.LittleEndian.Uint32(size[:]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, ErrInvalidFlatGeobuf
+	}
+	return data, nil
+}
+
+func appendFeaturePoints (points FlatPoints, data []byte, geometryType uint8, bbox *[4]float64) (FlatPoints, error) {
+	geometry := fbRoot(data).table(fgbFeatureGeometry)
+	if t := geometry.uint8(fgbGeometryType, fgbUnknown); t != fgbUnknown {
+		geometryType = t
+	}
+	xy := geometry.float64s(fgbGeometryXY)
+	if geometry.buffer.err != nil || len(xy) % 2 != 0 {
+		return nil, ErrInvalidFlatGeobuf
+	}
+	if geometryType != fgbPoint && geometryType != fgbMultiPoint {
+		return nil, fmt.Errorf("%w: geometry type %d is not a point", ErrInvalidFlatGeobuf, geometryType)
+	}
+	for i := 0; i + 1 < len(xy); i += 2 {
+		x, y := xy[i], xy[i + 1]
+		if bbox == nil || x >= bbox[0] && y >= bbox[1] && x <= bbox[2] && y <= bbox[3] {
+			points = append(points, x, y)
+		}
+	}
+	return points, nil
+}
+
+// Ranges of node indices of each level of a packed R-tree, leaves first, and the number of nodes. The root comes first in the file
+func packedRTreeLevels (numItems, nodeSize uint64) (levels [][2]uint64, numNodes uint64) {
+	n := numItems
+	numNodes = n
+	counts := []uint64{n}
+	for n > 1 {
+		n = (n + nodeSize - 1) / nodeSize
+		numNodes += n
+		counts = append(counts, n)
+	}
+	end := numNodes
+	for _, count := range(counts) {
+		levels = append(levels, [2]uint64{end - count, end})
+		end -= count
+	}
+	return levels, numNodes
+}
+
+// Byte offsets, relative to the first feature, of the features whose bounding box intersects bbox, in increasing order
+func searchPackedRTree (index []byte, levels [][2]uint64, nodeSize uint64, bbox [4]float64) ([]uint64, error) {
+	type entry struct {
+		node uint64
+		level int
+	}
+	var offsets []uint64
+	queue := []entry{{node: levels[len(levels) - 1][0], level: len(levels) - 1}}
+	for len(queue) > 0 {
+		e := queue[len(queue) - 1]
+		queue = queue[:len(queue) - 1]
+		end := e.node + nodeSize
+		if end > levels[e.level][1] {
+			end = levels[e.level][1]
+		}
+		for i := e.node; i < end; i++ {
+			item := index[i * fgbNodeItemSize:]
+			minX := math.Float64frombits(binary.LittleEndian.Uint64(item))
+			minY := math.Float64frombits(binary.LittleEndian.Uint64(item[8:]))
+			maxX := math.Float64frombits(binary.LittleEndian.Uint64(item[16:]))
+			maxY := math.Float64frombits(binary.LittleEndian.Uint64(item[24:]))
+			offset := binary.LittleEndian.Uint64(item[32:])
+			if maxX < bbox[0] || maxY < bbox[1] || minX > bbox[2] || minY > bbox[3] {
+				continue
+			}
+			if e.level == 0 {
+				offsets = append(offsets, offset)
+				continue
+			}
+			if offset < levels[e.level - 1][0] || offset >= levels[e.level - 1][1] {
+				return nil, ErrInvalidFlatGeobuf
+			}
+			queue = append(queue, entry{node: offset, level: e.level - 1})
+		}
+	}
+	sort.Slice(offsets, func (i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets, nil
+}
+
+// Write the hulls as Polygon features of a FlatGeobuf file, without spatial index
+func WriteFlatGeobuf (w io.Writer, hulls []FlatPoints, o FlatGeobufOptions) error {
+	envelope := []float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, hull := range(hulls) {
+		if hull.Len() == 0 {
+			continue
+		}
+		minX, minY, maxX, maxY := bbox(hull)
+		envelope = []float64{math.Min(envelope[0], minX), math.Min(envelope[1], minY), math.Max(envelope[2], maxX), math.Max(envelope[3], maxY)}
+	}
+	fields := []fbField{
+		{index: fgbHeaderGeometryType, scalar: []byte{fgbPolygon}},
+		fbUint64Field(fgbHeaderFeaturesCount, uint64(len(hulls))),
+		{index: fgbHeaderIndexNodeSize, scalar: []byte{0, 0}},
+	}
+	if o.Name != "" {
+		fields = append(fields, fbField{index: fgbHeaderName, ref: func (b *fbBuilder) int { return b.string(o.Name) }})
+	}
+	if !math.IsInf(envelope[0], 0) {
+		fields = append(fields, fbField{index: fgbHeaderEnvelope, ref: func (b *fbBuilder) int { return b.float64s(envelope) }})
+	}
+	if o.CRSCode != 0 {
+		fields = append(fields, fbField{index: fgbHeaderCrs, ref: func (b *fbBuilder) int {
+			return b.table([]fbField{fbUint32Field(fgbCrsCode, uint32(o.CRSCode))})
+		}})
+	}
+	if err := writeSizePrefixed(w, flatGeobufMagic[:], fbFinish(fields)); err != nil {
+		return err
+	}
+	for _, hull := range(hulls) {
+		ring := closeRing(hull)
+		geometry := []fbField{
+			{index: fgbGeometryType, scalar: []byte{fgbPolygon}},
+			{index: fgbGeometryXY, ref: func (b *fbBuilder) int { return b.float64s(ring) }},
+		}
+		feature := []fbField{{index: fgbFeatureGeometry, ref: func (b *fbBuilder) int { return b.table(geometry) }}}
+		if err := writeSizePrefixed(w, nil, fbFinish(feature)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSizePrefixed (w io.Writer, prefix, data []byte) error {
+	buffer := binary.LittleEndian.AppendUint32(append([]byte{}, prefix...), uint32(len(data)))
+	_, err := w.Write(append(buffer, data...))
+	return err
+}
+
+// Minimal FlatBuffers reader: tables, scalars and vectors of float64, remembering the first out of bounds access
+type fbBuffer struct {
+	data []byte
+	err error
+}
+
+type fbTable struct {
+	buffer *fbBuffer
+	pos int
+	vtable int
+	vtableSize int
+}
+
+func (b *fbBuffer) check (pos, size int) bool {
+	if b.err == nil && (pos < 0 || size < 0 || pos + size > len(b.data) || pos + size < pos) {
+		b.err = ErrInvalidFlatGeobuf
+	}
+	return b.err == nil
+}
+
+func (b *fbBuffer) fail () {
+	if b.err == nil {
+		b.err = ErrInvalidFlatGeobuf
+	}
+}
+
+func (b *fbBuffer) tableAt (pos int) fbTable {
+	t := fbTable{buffer: b, pos: pos}
+	if !b.check(pos, 4) {
+		return t
+	}
+	t.vtable = pos - int(int32(binary.LittleEndian.Uint32(b.data[pos:])))
+	if !b.check(t.vtable, 4) {
+		return t
+	}
+	t.vtableSize = int(binary.LittleEndian.Uint16(b.data[t.vtable:]))
+	b.check(t.vtable, t.vtableSize)
+	return t
+}
+
+func fbRoot (data []byte) fbTable {
+	b := &fbBuffer{data: data}
+	if !b.check(0, 4) {
+		return fbTable{buffer: b}
+	}
+	return b.tableAt(int(binary.LittleEndian.Uint32(data)))
+}
+
+// Position of field i, 0 if absent
+func (t fbTable) field (i int) int {
+	if t.buffer.err != nil || 4 + 2 * i + 2 > t.vtableSize {
+		return 0
+	}
+	offset := int(binary.LittleEndian.Uint16(t.buffer.data[t.vtable + 4 + 2 * i:]))
+	if offset == 0 {
+		return 0
+	}
+	return t.pos + offset
+}
+
+func (t fbTable) uint8 (i int, byDefault uint8) uint8 {
+	if pos := t.field(i); pos != 0 && t.buffer.check(pos, 1) {
+		return t.buffer.data[pos]
+	}
+	return byDefault
+}
+
+func (t fbTable) uint16 (i int, byDefault uint16) uint16 {
+	if pos := t.field(i); pos != 0 && t.buffer.check(pos, 2) {
+		return binary.LittleEndian.Uint16(t.buffer.data[pos:])
+	}
+	return byDefault
+}
+
+func (t fbTable) uint64 (i int, byDefault uint64) uint64 {
+	if pos := t.field(i); pos != 0 && t.buffer.check(pos, 8) {
+		return binary.LittleEndian.Uint64(t.buffer.data[pos:])
+	}
+	return byDefault
+}
+
+// Target of the offset stored in field i, 0 if absent
+func (t fbTable) reference (i int) int {
+	pos := t.field(i)
+	if pos == 0 || !t.buffer.check(pos, 4) {
+		return 0
+	}
+	return pos + int(binary.LittleEndian.Uint32(t.buffer.data[pos:]))
+}
+
+func (t fbTable) table (i int) fbTable {
+	pos := t.reference(i)
+	if pos == 0 {
+		t.buffer.fail()
+		return fbTable{buffer: t.buffer}
+	}
+	return t.buffer.tableAt(pos)
+}
+
+func (t fbTable) float64s (i int) []float64 {
+	pos := t.reference(i)
+	if pos == 0 || !t.buffer.check(pos, 4) {
+		return nil
+	}
+	n := int(binary.LittleEndian.Uint32(t.buffer.data[pos:]))
+	if n > len(t.buffer.data) / 8 {
+		t.buffer.fail()
+	}
+	if !t.buffer.check(pos + 4, 8 * n) {
+		return nil
+	}
+	return decodeFloats(t.buffer.data[pos + 4:pos + 4 + 8 * n])
+}
+
+// Minimal FlatBuffers writer. Tables are written before the objects they reference, so that every offset points forward
+type fbBuilder struct {
+	data []byte
+}
+
+// Field of a table, either a little endian scalar or a reference to an object written by ref, which returns its position
+type fbField struct {
+	index int
+	scalar []byte
+	ref func (b *fbBuilder) int
+}
+
+func fbUint32Field (index int, v uint32) fbField {
+	return fbField{index: index, scalar: binary.LittleEndian.AppendUint32(nil, v)}
+}
+
+func fbUint64Field (index int, v uint64) fbField {
+	return fbField{index: index, scalar: binary.LittleEndian.AppendUint64(nil, v)}
+}
+
+// Buffer whose root table has the fields
+func fbFinish (fields []fbField) []byte {
+	b := &fbBuilder{data: make([]byte, 4)}
+	root := b.table(fields)
+	binary.LittleEndian.PutUint32(b.data, uint32(root))
+	return b.data
+}
+
+func (b *fbBuilder) pad (alignment, extra int) {
+	for (len(b.data) + extra) % alignment != 0 {
+		b.data = append(b.data, 0)
+	}
+}
+
+// Write the vtable, the table and then the referenced objects, returns the position of the table
+func (b *fbBuilder) table (fields []fbField) int {
+	// larger scalars first, so that a table aligned to 8 bytes aligns all its fields
+	fields = append([]fbField{}, fields...)
+	size := func (f fbField) int {
+		if f.ref != nil {
+			return 4
+		}
+		return len(f.scalar)
+	}
+	sort.SliceStable(fields, func (i, j int) bool { return size(fields[i]) > size(fields[j]) })
+	offsets := make([]int, len(fields))
+	tableSize, maxIndex := 4, -1
+	for k, f := range(fields) {
+		for tableSize % size(f) != 0 {
+			tableSize++
+		}
+		offsets[k] = tableSize
+		tableSize += size(f)
+		if f.index > maxIndex {
+			maxIndex = f.index
+		}
+	}
+	vtable := make([]byte, 4 + 2 * (maxIndex + 1))
+	binary.LittleEndian.PutUint16(vtable, uint16(len(vtable)))
+	binary.LittleEndian.PutUint16(vtable[2:], uint16(tableSize))
+	for k, f := range(fields) {
+		binary.LittleEndian.PutUint16(vtable[4 + 2 * f.index:], uint16(offsets[k]))
+	}
+	b.pad(2, 0)
+	vtablePos := len(b.data)
+	b.data = append(b.data, vtable...)
+	b.pad(8, 0)
+	tablePos := len(b.data)
+	b.data = append(b.data, make([]byte, tableSize)...)
+	binary.LittleEndian.PutUint32(b.data[tablePos:], uint32(tablePos - vtablePos))
+	for k, f := range(fields) {
+		copy(b.data[tablePos + offsets[k]:], f.scalar)
+	}
+	for k, f := range(fields) {
+		if f.ref != nil {
+			// the object may grow the buffer, write it before taking the slice
+			fieldPos := tablePos + offsets[k]
+			target := f.ref(b)
+			binary.LittleEndian.PutUint32(b.data[fieldPos:], uint32(target - fieldPos))
+		}
+	}
+	return tablePos
+}
+
+func (b *fbBuilder) float64s (values []float64) int {
+	b.pad(8, 4)
+	pos := len(b.data)
+	b.data = binary.LittleEndian.AppendUint32(b.data, uint32(len(values)))
+	for _, v := range(values) {
+		b.data = binary.LittleEndian.AppendUint64(b.data, math.Float64bits(v))
+	}
+	return pos
+}
+
+func (b *fbBuilder) string (s string) int {
+	b.pad(4, 0)
+	pos := len(b.data)
+	b.data = binary.LittleEndian.AppendUint32(b.data, uint32(len(s)))
+	b.data = append(append(b.data, s...), 0)
+	return pos
+}
@@ -0,0 +1,115 @@
+package ConcaveHull
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+// FlatGeobuf file with one Point feature per point and, if nodeSize > 0, a packed R-tree over them in input order
+func pointFlatGeobuf (points FlatPoints, nodeSize uint64) []byte {
+	var features [][]byte
+	for i := 0; i < points.Len(); i++ {
+		x, y := points.Take(i)
+		geometry := []fbField{{index: fgbGeometryXY, ref: func (b *fbBuilder) int { return b.float64s([]float64{x, y}) }}}
+		features = append(features, fbFinish([]fbField{{index: fgbFeatureGeometry, ref: func (b *fbBuilder) int { return b.table(geometry) }}}))
+	}
+	header := fbFinish([]fbField{
+		{index: fgbHeaderGeometryType, scalar: []byte{fgbPoint}},
+		fbUint64Field(fgbHeaderFeaturesCount, uint64(points.Len())),
+		{index: fgbHeaderIndexNodeSize, scalar: binary.LittleEndian.AppendUint16(nil, uint16(nodeSize))},
+	})
+	var file bytes.Buffer
+	writeSizePrefixed(&file, flatGeobufMagic[:], header)
+	if nodeSize > 0 {
+		levels, numNodes := packedRTreeLevels(uint64(points.Len()), nodeSize)
+		boxes := make([][4]float64, numNodes)
+		offsets := make([]uint64, numNodes)
+		offset := uint64(0)
+		for i, feature := range(features) {
+			x, y := points.Take(i)
+			node := levels[0][0] + uint64(i)
+			boxes[node], offsets[node] = [4]float64{x, y, x, y}, offset
+			offset += 4 + uint64(len(feature))
+		}
+		for l := 1; l < len(levels); l++ {
+			for node := levels[l][0]; node < levels[l][1]; node++ {
+				first := levels[l - 1][0] + (node - levels[l][0]) * nodeSize
+				box := [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+				for child := first; child < first + nodeSize && child < levels[l - 1][1]; child++ {
+					box = [4]float64{math.Min(box[0], boxes[child][0]), math.Min(box[1], boxes[child][1]), math.Max(box[2], boxes[child][2]), math.Max(box[3], boxes[child][3])}
+				}
+				boxes[node], offsets[node] = box, first
+			}
+		}
+		for node := range(boxes) {
+			var item []byte
+			for _, v := range(boxes[node]) {
+				item = binary.LittleEndian.AppendUint64(item, math.Float64bits(v))
+			}
+			file.Write(binary.LittleEndian.AppendUint64(item, offsets[node]))
+		}
+	}
+	for _, feature := range(features) {
+		writeSizePrefixed(&file, nil, feature)
+	}
+	return file.Bytes()
+}
+
+func TestReadFlatGeobuf (t *testing.T) {
+	var points FlatPoints
+	for i := 0; i < 50; i++ {
+		points = append(points, float64(i % 10), float64(i / 10))
+	}
+	for _, nodeSize := range([]uint64{0, 2, 16}) {
+		file := pointFlatGeobuf(points, nodeSize)
+		read, err := ReadFlatGeobuf(bytes.NewReader(file), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, points, read)
+		read, err = ReadFlatGeobuf(bytes.NewReader(file), &[4]float64{2, 1, 3.5, 2})
+		assert.Nil(t, err)
+		assert.Equal(t, FlatPoints{2, 1, 3, 1, 2, 2, 3, 2}, read)
+	}
+}
+
+func TestReadFlatGeobuf_invalid (t *testing.T) {
+	file := pointFlatGeobuf(FlatPoints{0, 0, 1, 1, 2, 2}, 2)
+	for _, data := range([][]byte{nil, []byte("fgb\x03fgb\x00"), file[:len(file) - 3], append([]byte("xyz"), file[3:]...)}) {
+		_, err := ReadFlatGeobuf(bytes.NewReader(data), nil)
+		assert.True(t, errors.Is(err, ErrInvalidFlatGeobuf))
+	}
+	var polygons bytes.Buffer
+	assert.Nil(t, WriteFlatGeobuf(&polygons, []FlatPoints{{0, 0, 1, 0, 1, 1}}, FlatGeobufOptions{}))
+	_, err := ReadFlatGeobuf(bytes.NewReader(polygons.Bytes()), nil)
+	assert.True(t, errors.Is(err, ErrInvalidFlatGeobuf))
+}
+
+func TestWriteFlatGeobuf (t *testing.T) {
+	var file bytes.Buffer
+	hulls := []FlatPoints{{0, 0, 2, 0, 2, 2, 0, 0}, {5, 5, 6, 5, 6, 7}}
+	assert.Nil(t, WriteFlatGeobuf(&file, hulls, FlatGeobufOptions{Name: "hulls", CRSCode: 4326}))
+	data := file.Bytes()
+	assert.Equal(t, flatGeobufMagic[:], data[:8])
+	r := bytes.NewReader(data[8:])
+	headerData, err := readSizePrefixed(r)
+	assert.Nil(t, err)
+	header := fbRoot(headerData)
+	assert.Equal(t, uint8(fgbPolygon), header.uint8(fgbHeaderGeometryType, 0))
+	assert.Equal(t, uint64(2), header.uint64(fgbHeaderFeaturesCount, 0))
+	assert.Equal(t, uint16(0), header.uint16(fgbHeaderIndexNodeSize, fgbDefaultNodeSize))
+	assert.Equal(t, []float64{0, 0, 6, 7}, header.float64s(fgbHeaderEnvelope))
+	crs := header.table(fgbHeaderCrs)
+	assert.Equal(t, uint64(4326), uint64(binary.LittleEndian.Uint32(headerData[crs.field(fgbCrsCode):])))
+	for _, expected := range([]FlatPoints{hulls[0], {5, 5, 6, 5, 6, 7, 5, 5}}) {
+		featureData, err := readSizePrefixed(r)
+		assert.Nil(t, err)
+		geometry := fbRoot(featureData).table(fgbFeatureGeometry)
+		assert.Equal(t, uint8(fgbPolygon), geometry.uint8(fgbGeometryType, 0))
+		assert.Equal(t, []float64(expected), geometry.float64s(fgbGeometryXY))
+		assert.Nil(t, geometry.buffer.err)
+	}
+	assert.Equal(t, 0, r.Len())
+}
@@ -0,0 +1,40 @@
+package ConcaveHull
+
+import "sort"
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Geohash cells of the given precision, in characters, that intersect or are contained in the hull, see CoverMode.
+// Points are longitude, latitude in degrees. Cells are sorted
+func (h Hull) GeohashCover (precision int, mode CoverMode) []string {
+	lonBits, latBits := (5 * precision + 1) / 2, 5 * precision / 2
+	columns, rows := 1 << uint(lonBits), 1 << uint(latBits)
+	var hashes []string
+	for _, cell := range(gridCover(h.Points, -180, -90, 360 / float64(columns), 180 / float64(rows), columns, rows, mode)) {
+		hashes = append(hashes, geohash(cell[0], cell[1], precision))
+	}
+	sort.Strings(hashes)
+	return hashes
+}
+
+// Geohash of the cell at column and row of the grid of the given precision. Bits alternate starting with longitude
+func geohash (column, row, precision int) string {
+	lonBits, latBits := (5 * precision + 1) / 2, 5 * precision / 2
+	hash := make([]byte, precision)
+	for c := 0; c < precision; c++ {
+		value := 0
+		for b := 5 * c; b < 5 * c + 5; b++ {
+			var bit int
+			if b % 2 == 0 {
+				lonBits--
+				bit = (column >> uint(lonBits)) & 1
+			} else {
+				latBits--
+				bit = (row >> uint(latBits)) & 1
+			}
+			value = value << 1 | bit
+		}
+		hash[c] = geohashAlphabet[value]
+	}
+	return string(hash)
+}
@@ -0,0 +1,33 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeohash (t *testing.T) {
+	// ezs42 is the cell of (-5.6, 42.6), 25 bits, 13 of longitude and 12 of latitude
+	lon, lat := -5.6, 42.6
+	column := int((lon + 180) / 360 * (1 << 13))
+	row := int((lat + 90) / 180 * (1 << 12))
+	assert.Equal(t, "ezs42", geohash(column, row, 5))
+}
+
+func TestHull_GeohashCover (t *testing.T) {
+	// square spanning exactly the 32 cells of precision 2 below "u"
+	h := Hull{Points: FlatPoints{0, 45, 45, 45, 45, 90, 0, 90, 0, 45}}
+	cover := h.GeohashCover(2, CoverIntersecting)
+	assert.Equal(t, 32, len(cover))
+	assert.Equal(t, "u0", cover[0])
+	assert.Equal(t, cover, h.GeohashCover(2, CoverContained))
+	// triangle: the cells along the diagonal intersect it but aren't contained in it
+	h = Hull{Points: FlatPoints{0, 45, 45, 45, 0, 90, 0, 45}}
+	intersecting := h.GeohashCover(2, CoverIntersecting)
+	contained := h.GeohashCover(2, CoverContained)
+	assert.True(t, len(contained) < len(intersecting))
+	assert.True(t, len(intersecting) < 32)
+	// small hull inside a single cell
+	h = Hull{Points: FlatPoints{-5.61, 42.59, -5.59, 42.59, -5.6, 42.61, -5.61, 42.59}}
+	assert.Equal(t, []string{"ezs42"}, h.GeohashCover(5, CoverIntersecting))
+	assert.Equal(t, 0, len(h.GeohashCover(5, CoverContained)))
+}
@@ -0,0 +1,148 @@
+package ConcaveHull
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+var ErrInvalidGeoJSON = errors.New("ConcaveHull: file is not a GeoJSON FeatureCollection")
+
+// Layout of a GeoJSON file
+type GeoJSONFormat int
+
+const (
+	GeoJSONFeatureCollection GeoJSONFormat = iota // a single FeatureCollection object
+	GeoJSONSeq // newline delimited features, one per line
+)
+
+// A polygon feature of a GeoJSON file
+type GeoJSONFeature struct {
+	ID interface{} // string or number, omitted if nil
+	// Exterior ring followed by its holes. Rings may be closed or not, orientation is fixed on encoding
+	Rings []FlatPoints
+	Properties map[string]interface{}
+}
+
+type geoJSONGeometry struct {
+	Type string `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+type geoJSONFeature struct {
+	Type string `json:"type"`
+	ID interface{} `json:"id,omitempty"`
+	Geometry geoJSONGeometry `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// GeoJSON Feature object with a Polygon geometry. Rings are closed, the exterior counter clockwise and holes clockwise, as RFC 7946 asks
+func (f GeoJSONFeature) MarshalJSON () ([]byte, error) {
+	rings := f.Rings
+	if len(rings) > 0 {
+		rings = HullPolygon{Exterior: rings[0], Holes: rings[1:]}.rings()
+	}
+	feature := geoJSONFeature{Type: "Feature", ID: f.ID, Geometry: geoJSONGeometry{Type: "Polygon", Coordinates: geoJSONRings(rings)}, Properties: f.Properties}
+	return json.Marshal(feature)
+}
+
+// Append the features to the GeoJSON file at path, which is created if it doesn't exist. The new content is written to a
+// temporary file in the same directory that then replaces the original, so readers and crashes never see a partial file.
+// Members of an existing FeatureCollection other than features are kept.
+// Concurrent appends to the same file must be serialized by the caller
+func AppendGeoJSON (path string, format GeoJSONFormat, features ...GeoJSONFeature) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	var content []byte
+	if format == GeoJSONSeq {
+		content, err = appendGeoJSONSeq(existing, features)
+	} else {
+		content, err = appendFeatureCollection(existing, features)
+	}
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, content, mode)
+}
+
+func appendGeoJSONSeq (existing []byte, features []GeoJSONFeature) ([]byte, error) {
+	content := existing
+	if len(content) > 0 && content[len(content) - 1] != '\n' {
+		content = append(content, '\n')
+	}
+	for _, feature := range(features) {
+		encoded, err := json.Marshal(feature)
+		if err != nil {
+			return nil, err
+		}
+		content = append(append(content, encoded...), '\n')
+	}
+	return content, nil
+}
+
+func appendFeatureCollection (existing []byte, features []GeoJSONFeature) ([]byte, error) {
+	collection := map[string]json.RawMessage{}
+	var rawFeatures []json.RawMessage
+	if len(bytes.TrimSpace(existing)) > 0 {
+		if err := json.Unmarshal(existing, &collection); err != nil {
+			return nil, ErrInvalidGeoJSON
+		}
+		if string(collection["type"]) != `"FeatureCollection"` {
+			return nil, ErrInvalidGeoJSON
+		}
+		if raw, ok := collection["features"]; ok {
+			if err := json.Unmarshal(raw, &rawFeatures); err != nil {
+				return nil, ErrInvalidGeoJSON
+			}
+		}
+	}
+	collection["type"] = json.RawMessage(`"FeatureCollection"`)
+	for _, feature := range(features) {
+		encoded, err := json.Marshal(feature)
+		if err != nil {
+			return nil, err
+		}
+		rawFeatures = append(rawFeatures, encoded)
+	}
+	if rawFeatures == nil {
+		rawFeatures = []json.RawMessage{}
+	}
+	encoded, err := json.Marshal(rawFeatures)
+	if err != nil {
+		return nil, err
+	}
+	collection["features"] = encoded
+	return json.Marshal(collection)
+}
+
+// Write to a temporary file in the directory of path, sync it and rename it over path
+func writeFileAtomic (path string, content []byte, mode os.FileMode) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "." + filepath.Base(path) + ".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,63 @@
+package ConcaveHull
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoJSONFeature_MarshalJSON (t *testing.T) {
+	// clockwise exterior and counter clockwise hole are reversed, rings are closed
+	feature := GeoJSONFeature{ID: 3, Rings: []FlatPoints{{0, 0, 0, 4, 4, 4, 4, 0}, {1, 1, 2, 1, 2, 2}}, Properties: map[string]interface{}{"name": "a"}}
+	encoded, err := json.Marshal(feature)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"Feature","id":3,"geometry":{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]],[[1,1],[2,2],[2,1],[1,1]]]},"properties":{"name":"a"}}`, string(encoded))
+}
+
+func TestAppendGeoJSON_featureCollection (t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hulls.geojson")
+	square := FlatPoints{0, 0, 1, 0, 1, 1, 0, 1}
+	assert.Nil(t, AppendGeoJSON(path, GeoJSONFeatureCollection, GeoJSONFeature{ID: "a", Rings: []FlatPoints{square}}))
+	assert.Nil(t, os.WriteFile(path, []byte(strings.Replace(string(mustRead(t, path)), "{", `{"name":"batch",`, 1)), 0600))
+	assert.Nil(t, os.Chmod(path, 0600))
+	assert.Nil(t, AppendGeoJSON(path, GeoJSONFeatureCollection, GeoJSONFeature{ID: "b", Rings: []FlatPoints{square}}, GeoJSONFeature{ID: "c", Rings: []FlatPoints{square}}))
+	var collection struct {
+		Type string
+		Name string
+		Features []struct{ ID string }
+	}
+	assert.Nil(t, json.Unmarshal(mustRead(t, path), &collection))
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	assert.Equal(t, "batch", collection.Name)
+	assert.Equal(t, 3, len(collection.Features))
+	assert.Equal(t, "c", collection.Features[2].ID)
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	assert.Equal(t, 1, len(entries))
+
+	assert.Nil(t, os.WriteFile(path, []byte(`{"type":"Feature"}`), 0600))
+	assert.Equal(t, ErrInvalidGeoJSON, AppendGeoJSON(path, GeoJSONFeatureCollection, GeoJSONFeature{Rings: []FlatPoints{square}}))
+}
+
+func TestAppendGeoJSON_seq (t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hulls.geojsonl")
+	square := FlatPoints{0, 0, 1, 0, 1, 1, 0, 1}
+	assert.Nil(t, AppendGeoJSON(path, GeoJSONSeq, GeoJSONFeature{ID: 1, Rings: []FlatPoints{square}}))
+	assert.Nil(t, AppendGeoJSON(path, GeoJSONSeq, GeoJSONFeature{ID: 2, Rings: []FlatPoints{square}}))
+	lines := strings.Split(strings.TrimSpace(string(mustRead(t, path))), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.True(t, strings.HasPrefix(lines[1], `{"type":"Feature","id":2,`))
+}
+
+func mustRead (t *testing.T, path string) []byte {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
@@ -0,0 +1,50 @@
+package ConcaveHull
+
+import "github.com/USACE/concavehull/geomutil"
+
+// Twice the signed area of the triangle (a, b, c), positive if the triangle is counter clockwise
+func orientation (ax, ay, bx, by, cx, cy float64) float64 {
+	return geomutil.Orientation(ax, ay, bx, by, cx, cy)
+}
+
+// Squared euclidean distance, small enough for the compiler to inline in the loops of segmentize
+func squaredDistance (x1, y1, x2, y2 float64) float64 {
+	return geomutil.SquaredDistance(x1, y1, x2, y2)
+}
+
+// Ring without the closing point, so that consecutive vertices, including the last and the first, form the edges
+func openRing (ring FlatPoints) FlatPoints {
+	return geomutil.OpenRing(ring)
+}
+
+// Ring with the closing point
+func closeRing (ring FlatPoints) FlatPoints {
+	return geomutil.CloseRing(ring)
+}
+
+// Signed area of a ring by the shoelace formula, positive for counter clockwise rings. The ring may be closed or not.
+// Coordinates are taken relative to the first vertex, so rings far from the origin, such as projected ones, keep their precision
+func SignedArea (ring FlatPoints) float64 {
+	return geomutil.SignedArea(ring)
+}
+
+// Whether the ring is counter clockwise, which is the orientation of the exterior rings of hulls and of GeoJSON. Rings with
+// no area are neither counter clockwise nor clockwise
+func IsCCW (ring FlatPoints) bool {
+	return geomutil.IsCCW(ring)
+}
+
+// Copy of the ring in reverse order
+func reverseRing (ring FlatPoints) FlatPoints {
+	return geomutil.ReverseRing(ring)
+}
+
+// Even odd rule. The ring may be closed or not. Points on the boundary may be reported either way
+func ringContains (ring FlatPoints, x, y float64) bool {
+	return geomutil.RingContains(ring, x, y)
+}
+
+// Closest point to (x, y) on the segment (x1, y1) - (x2, y2) and the parameter t in [0, 1] along the segment
+func projectOnSegment (x, y, x1, y1, x2, y2 float64) (px, py, t float64) {
+	return geomutil.ProjectOnSegment(x, y, x1, y1, x2, y2)
+}
@@ -0,0 +1,21 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedArea (t *testing.T) {
+	square := FlatPoints{0, 0, 2, 0, 2, 2, 0, 2}
+	assert.Equal(t, 4., SignedArea(square))
+	assert.Equal(t, 4., SignedArea(closeRing(square)))
+	assert.Equal(t, -4., SignedArea(reverseRing(square)))
+	assert.True(t, IsCCW(square))
+	assert.False(t, IsCCW(reverseRing(square)))
+	assert.False(t, IsCCW(FlatPoints{0, 0, 1, 1, 2, 2}))
+	assert.Equal(t, 0., SignedArea(FlatPoints{0, 0, 1, 1}))
+
+	// a unit square in UTM northings keeps its exact area
+	far := FlatPoints{500000, 4649776, 500001, 4649776, 500001, 4649777, 500000, 4649777}
+	assert.Equal(t, 1., SignedArea(far))
+}
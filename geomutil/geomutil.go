@@ -0,0 +1,219 @@
+// Package geomutil provides the planar geometry primitives of ConcaveHull, ring operations, area, containment, simplification,
+// clipping and polygon overlay, without external dependencies.
+//
+// Points and rings are flat arrays of coordinates []float64{x0, y0, x1, y1, ...}, the same layout as ConcaveHull.FlatPoints,
+// so results can be converted in both directions without copying. Rings may be closed, the last point repeating the first,
+// or open, unless stated otherwise.
+package geomutil
+
+import "math"
+
+// Twice the signed area of the triangle (a, b, c), positive if the triangle is counter clockwise
+func Orientation (ax, ay, bx, by, cx, cy float64) float64 {
+	return (bx - ax) * (cy - ay) - (by - ay) * (cx - ax)
+}
+
+// Squared euclidean distance
+func SquaredDistance (x1, y1, x2, y2 float64) float64 {
+	dx, dy := x2 - x1, y2 - y1
+	return dx * dx + dy * dy
+}
+
+// Ring without the closing point, so that consecutive vertices, including the last and the first, form the edges. The
+// result shares the array of the ring
+func OpenRing (ring []float64) []float64 {
+	n := len(ring) / 2
+	if n > 1 && ring[0] == ring[2 * n - 2] && ring[1] == ring[2 * n - 1] {
+		return ring[:2 * n - 2]
+	}
+	return ring
+}
+
+// Copy of the ring with the closing point, the ring itself if it is empty
+func CloseRing (ring []float64) []float64 {
+	ring = OpenRing(ring)
+	if len(ring) == 0 {
+		return ring
+	}
+	closed := make([]float64, 0, len(ring) + 2)
+	closed = append(closed, ring...)
+	return append(closed, ring[0], ring[1])
+}
+
+// Copy of the ring in reverse order
+func ReverseRing (ring []float64) []float64 {
+	reversed := make([]float64, 0, len(ring))
+	for i := len(ring) / 2 - 1; i >= 0; i-- {
+		reversed = append(reversed, ring[2 * i], ring[2 * i + 1])
+	}
+	return reversed
+}
+
+// Signed area of a ring by the shoelace formula, positive for counter clockwise rings. Coordinates are taken relative to the
+// first vertex, so rings far from the origin, such as projected ones, keep their precision
+func SignedArea (ring []float64) float64 {
+	ring = OpenRing(ring)
+	n := len(ring) / 2
+	if n < 3 {
+		return 0
+	}
+	x0, y0 := ring[0], ring[1]
+	area := 0.
+	for i := 1; i + 1 < n; i++ {
+		area += (ring[2 * i] - x0) * (ring[2 * i + 3] - y0) - (ring[2 * i + 2] - x0) * (ring[2 * i + 1] - y0)
+	}
+	return area / 2
+}
+
+// Whether the ring is counter clockwise. Rings with no area are neither counter clockwise nor clockwise
+func IsCCW (ring []float64) bool {
+	return SignedArea(ring) > 0
+}
+
+// Even odd rule. Points on the boundary may be reported either way
+func RingContains (ring []float64, x, y float64) bool {
+	ring = OpenRing(ring)
+	n := len(ring) / 2
+	inside := false
+	for i := 0; i < n; i++ {
+		x1, y1 := ring[2 * i], ring[2 * i + 1]
+		j := (i + 1) % n
+		x2, y2 := ring[2 * j], ring[2 * j + 1]
+		if (y1 > y) != (y2 > y) && x < (x2 - x1) * (y - y1) / (y2 - y1) + x1 {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Bounding box of the points, all zero if there are none
+func BBox (points []float64) (minX, minY, maxX, maxY float64) {
+	if len(points) < 2 {
+		return
+	}
+	minX, minY = points[0], points[1]
+	maxX, maxY = minX, minY
+	for i := 2; i + 1 < len(points); i += 2 {
+		x, y := points[i], points[i + 1]
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+	return
+}
+
+// Closest point to (x, y) on the segment (x1, y1) - (x2, y2) and the parameter t in [0, 1] along the segment
+func ProjectOnSegment (x, y, x1, y1, x2, y2 float64) (px, py, t float64) {
+	vx, vy := x2 - x1, y2 - y1
+	if l := vx * vx + vy * vy; l > 0 {
+		t = math.Max(0, math.Min(1, ((x - x1) * vx + (y - y1) * vy) / l))
+	}
+	return x1 + t * vx, y1 + t * vy, t
+}
+
+// Closest point to (x, y) on the edges of a ring, the index of its edge, from vertex edge to the next one, and the squared
+// distance. The distance is infinite for an empty ring
+func ClosestOnRing (ring []float64, x, y float64) (px, py float64, edge int, squared float64) {
+	n := len(ring) / 2
+	squared = math.Inf(1)
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		cx, cy, _ := ProjectOnSegment(x, y, ring[2 * i], ring[2 * i + 1], ring[2 * j], ring[2 * j + 1])
+		if d := SquaredDistance(x, y, cx, cy); d < squared {
+			px, py, edge, squared = cx, cy, i, d
+		}
+	}
+	return px, py, edge, squared
+}
+
+// Whether two closed segments have at least one point in common
+func SegmentsTouch (ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	d1 := Orientation(cx, cy, dx, dy, ax, ay)
+	d2 := Orientation(cx, cy, dx, dy, bx, by)
+	d3 := Orientation(ax, ay, bx, by, cx, cy)
+	d4 := Orientation(ax, ay, bx, by, dx, dy)
+	// signs rather than products, which underflow to 0 for tiny orientations
+	if (d1 > 0 && d2 < 0 || d1 < 0 && d2 > 0) && (d3 > 0 && d4 < 0 || d3 < 0 && d4 > 0) {
+		return true
+	}
+	inBox := func (x1, y1, x2, y2, px, py float64) bool {
+		return math.Min(x1, x2) <= px && px <= math.Max(x1, x2) && math.Min(y1, y2) <= py && py <= math.Max(y1, y2)
+	}
+	return d1 == 0 && inBox(cx, cy, dx, dy, ax, ay) ||
+		d2 == 0 && inBox(cx, cy, dx, dy, bx, by) ||
+		d3 == 0 && inBox(ax, ay, bx, by, cx, cy) ||
+		d4 == 0 && inBox(ax, ay, bx, by, dx, dy)
+}
+
+// Part of the segment inside the open rectangle by Liang-Barsky clipping, as parameters t0 < t1 along the segment, ok is
+// false if the segment doesn't pass through the interior of the rectangle
+func ClipSegment (x1, y1, x2, y2, minX, minY, maxX, maxY float64) (t0, t1 float64, ok bool) {
+	t0, t1 = 0., 1.
+	dx, dy := x2 - x1, y2 - y1
+	for _, edge := range([4][2]float64{{-dx, x1 - minX}, {dx, maxX - x1}, {-dy, y1 - minY}, {dy, maxY - y1}}) {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			if q <= 0 {
+				return 0, 0, false
+			}
+			continue
+		}
+		r := q / p
+		if p < 0 {
+			t0 = math.Max(t0, r)
+		} else {
+			t1 = math.Min(t1, r)
+		}
+	}
+	return t0, t1, t0 < t1
+}
+
+// Douglas Peucker simplification of a polyline into a new array: the end points are kept, and so is, recursively, the
+// vertex farthest from the segment between two kept ones while it is farther than tolerance. Of equally far vertices the
+// first is kept. Pass a closed ring to simplify it with its first vertex fixed. The arithmetic is that of go.geo's reducers,
+// which ConcaveHull used before, so hulls are unchanged
+func DouglasPeucker (points []float64, tolerance float64) []float64 {
+	n := len(points) / 2
+	if n <= 2 {
+		return append([]float64{}, points[:2 * n]...)
+	}
+	keep := make([]bool, n)
+	keep[0], keep[n - 1] = true, true
+	spans := [][2]int{{0, n - 1}}
+	for len(spans) > 0 {
+		from, to := spans[len(spans) - 1][0], spans[len(spans) - 1][1]
+		spans = spans[:len(spans) - 1]
+		farthest, distance := -1, 0.
+		for k := from + 1; k < to; k++ {
+			if d := squaredDistanceToSegment(points[2 * k], points[2 * k + 1], points[2 * from], points[2 * from + 1], points[2 * to], points[2 * to + 1]); d > distance {
+				farthest, distance = k, d
+			}
+		}
+		if farthest >= 0 && distance > tolerance * tolerance {
+			keep[farthest] = true
+			spans = append(spans, [2]int{farthest, to}, [2]int{from, farthest})
+		}
+	}
+	simplified := make([]float64, 0, 2 * n)
+	for i, k := range(keep) {
+		if k {
+			simplified = append(simplified, points[2 * i], points[2 * i + 1])
+		}
+	}
+	return simplified
+}
+
+// Squared distance from (x, y) to the segment (x1, y1) - (x2, y2). Beyond the end of the segment the distance is taken to
+// the end point itself rather than to its projection
+func squaredDistanceToSegment (x, y, x1, y1, x2, y2 float64) float64 {
+	px, py := x1, y1
+	dx, dy := x2 - x1, y2 - y1
+	if dx != 0 || dy != 0 {
+		t := ((x - x1) * dx + (y - y1) * dy) / (dx * dx + dy * dy)
+		if t > 1 {
+			px, py = x2, y2
+		} else if t > 0 {
+			px, py = x1 + dx * t, y1 + dy * t
+		}
+	}
+	return SquaredDistance(x, y, px, py)
+}
@@ -0,0 +1,71 @@
+package geomutil
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRings (t *testing.T) {
+	square := []float64{0, 0, 2, 0, 2, 2, 0, 2}
+	closed := CloseRing(square)
+	if !reflect.DeepEqual(closed, []float64{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}) || !reflect.DeepEqual(OpenRing(closed), square) {
+		t.Errorf("closing and opening gave %v", closed)
+	}
+	if SignedArea(closed) != 4 || SignedArea(ReverseRing(square)) != -4 || !IsCCW(square) || IsCCW(ReverseRing(square)) {
+		t.Error("wrong area or orientation of the square")
+	}
+	if SignedArea([]float64{0, 0, 1, 1, 2, 2}) != 0 || SignedArea([]float64{0, 0, 1, 1}) != 0 {
+		t.Error("rings without area have an area")
+	}
+	// a unit square in UTM northings keeps its exact area
+	if a := SignedArea([]float64{500000, 4649776, 500001, 4649776, 500001, 4649777, 500000, 4649777}); a != 1 {
+		t.Errorf("far square has area %v", a)
+	}
+	if !RingContains(square, 1, 1) || RingContains(closed, 3, 1) {
+		t.Error("wrong containment")
+	}
+	if minX, minY, maxX, maxY := BBox([]float64{1, 5, -2, 3, 4, 4}); minX != -2 || minY != 3 || maxX != 4 || maxY != 5 {
+		t.Errorf("wrong bounding box %v %v %v %v", minX, minY, maxX, maxY)
+	}
+}
+
+func TestSegments (t *testing.T) {
+	if px, py, tt := ProjectOnSegment(1, 1, 0, 0, 2, 0); px != 1 || py != 0 || tt != 0.5 {
+		t.Errorf("projection is %v %v %v", px, py, tt)
+	}
+	if !SegmentsTouch(0, 0, 2, 2, 0, 2, 2, 0) || !SegmentsTouch(0, 0, 2, 0, 1, 0, 1, 1) || SegmentsTouch(0, 0, 1, 0, 0, 1, 1, 1) {
+		t.Error("wrong touch")
+	}
+	// orientations whose products underflow
+	if !SegmentsTouch(0, 0, 2e-160, 2e-160, 0, 2e-160, 2e-160, 0) {
+		t.Error("tiny crossing segments don't touch")
+	}
+	if px, py, edge, squared := ClosestOnRing([]float64{0, 0, 2, 0, 2, 2, 0, 2}, 1, 3); px != 1 || py != 2 || edge != 2 || squared != 1 {
+		t.Errorf("closest point %v %v on edge %v at %v", px, py, edge, squared)
+	}
+	if t0, t1, ok := ClipSegment(-1, 0.5, 3, 0.5, 0, 0, 2, 1); !ok || t0 != 0.25 || t1 != 0.75 {
+		t.Errorf("clipped to %v %v %v", t0, t1, ok)
+	}
+	// along the boundary of the rectangle, not through its interior
+	if _, _, ok := ClipSegment(-1, 0, 3, 0, 0, 0, 2, 1); ok {
+		t.Error("segment on the boundary clipped")
+	}
+}
+
+func TestDouglasPeucker (t *testing.T) {
+	line := []float64{0, 0, 1, 0.1, 2, 0, 3, 1, 4, 0}
+	if s := DouglasPeucker(line, 0.5); !reflect.DeepEqual(s, []float64{0, 0, 2, 0, 3, 1, 4, 0}) {
+		t.Errorf("simplified to %v", s)
+	}
+	if s := DouglasPeucker(line, math.Inf(1)); !reflect.DeepEqual(s, []float64{0, 0, 4, 0}) {
+		t.Errorf("simplified to %v", s)
+	}
+	// of equally far vertices the first is kept
+	if s := DouglasPeucker([]float64{0, 0, 1, 1, 2, 0, 3, 1, 4, 0}, 0.9); !reflect.DeepEqual(s, []float64{0, 0, 1, 1, 4, 0}) {
+		t.Errorf("simplified to %v", s)
+	}
+	if s := DouglasPeucker([]float64{0, 0, 1, 1, 2, 0, 3, 1, 4, 0}, 1); !reflect.DeepEqual(s, []float64{0, 0, 4, 0}) {
+		t.Errorf("simplified to %v", s)
+	}
+}
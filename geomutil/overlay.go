@@ -0,0 +1,284 @@
+package geomutil
+
+import (
+	"math"
+	"sort"
+)
+
+// Union of two simple polygons. The result is a list of closed rings, exterior rings are counter clockwise and holes clockwise
+func Union (a, b []float64) [][]float64 {
+	return overlay(a, b, overlayUnion)
+}
+
+// Intersection of two simple polygons. The result is a list of closed rings, exterior rings are counter clockwise and holes clockwise
+func Intersection (a, b []float64) [][]float64 {
+	return overlay(a, b, overlayIntersection)
+}
+
+// Part of a that is not covered by b. The result is a list of closed rings, exterior rings are counter clockwise and holes clockwise
+func Difference (a, b []float64) [][]float64 {
+	return overlay(a, b, overlayDifference)
+}
+
+type overlayOperation int
+
+const (
+	overlayUnion overlayOperation = iota
+	overlayIntersection
+	overlayDifference
+)
+
+// Directed edge between two vertices, by index, see ChainEdges
+type Edge struct {
+	From, To int
+}
+
+// Overlay of two simple polygons: every edge is split at the points where it meets the other polygon, and each piece
+// is kept or discarded depending on whether it lies inside the other polygon. Kept pieces are then chained into rings.
+// Hulls computed from overlapping data share many vertices and edges, which this approach handles without perturbation
+func overlay (a, b []float64, op overlayOperation) [][]float64 {
+	a, b = CounterClockwise(a), CounterClockwise(b)
+	if len(a) / 2 < 3 || len(b) / 2 < 3 {
+		return degenerateOverlay(a, b, op)
+	}
+	piecesA, piecesB := splitRings(a, b)
+	var vertices [][2]float64
+	vertexIds := make(map[[2]float64]int)
+	vertexId := func (p [2]float64) int {
+		if id, ok := vertexIds[p]; ok {
+			return id
+		}
+		vertexIds[p] = len(vertices)
+		vertices = append(vertices, p)
+		return len(vertices) - 1
+	}
+	// direction of every piece of b, to detect shared boundaries
+	directionsB := make(map[[2]int]bool)
+	for _, piece := range(piecesB) {
+		directionsB[[2]int{vertexId(piece[0]), vertexId(piece[1])}] = true
+	}
+	sharedA := make(map[[2]int]bool)
+	var edges []Edge
+	for _, piece := range(piecesA) {
+		from, to := vertexId(piece[0]), vertexId(piece[1])
+		sameDirection, oppositeDirection := directionsB[[2]int{from, to}], directionsB[[2]int{to, from}]
+		if sameDirection || oppositeDirection {
+			sharedA[[2]int{from, to}] = true
+		}
+		keep := false
+		switch {
+		case sameDirection:
+			keep = op != overlayDifference
+		case oppositeDirection:
+			keep = op == overlayDifference
+		default:
+			inside := RingContains(b, (piece[0][0] + piece[1][0]) / 2, (piece[0][1] + piece[1][1]) / 2)
+			keep = inside == (op == overlayIntersection)
+		}
+		if keep {
+			edges = append(edges, Edge{From: from, To: to})
+		}
+	}
+	for _, piece := range(piecesB) {
+		from, to := vertexId(piece[0]), vertexId(piece[1])
+		if sharedA[[2]int{from, to}] || sharedA[[2]int{to, from}] {
+			continue
+		}
+		inside := RingContains(a, (piece[0][0] + piece[1][0]) / 2, (piece[0][1] + piece[1][1]) / 2)
+		switch op {
+		case overlayUnion:
+			if !inside {
+				edges = append(edges, Edge{From: from, To: to})
+			}
+		case overlayIntersection:
+			if inside {
+				edges = append(edges, Edge{From: from, To: to})
+			}
+		case overlayDifference:
+			if inside {
+				edges = append(edges, Edge{From: to, To: from})
+			}
+		}
+	}
+	return ChainEdges(vertices, edges)
+}
+
+// Chain directed edges into closed rings. When several edges leave the same vertex the sharpest left turn is taken,
+// so that polygons touching at a vertex come out as separate rings. Collinear vertices are removed and each ring starts at
+// its lexicographically lowest vertex, so the rings don't depend on the order of the edges
+func ChainEdges (vertices [][2]float64, edges []Edge) [][]float64 {
+	outgoing := make(map[int][]int)
+	for i, e := range(edges) {
+		outgoing[e.From] = append(outgoing[e.From], i)
+	}
+	used := make([]bool, len(edges))
+	var rings [][]float64
+	for start := range(edges) {
+		if used[start] {
+			continue
+		}
+		var ring []float64
+		current := start
+		for {
+			e := edges[current]
+			used[current] = true
+			ring = append(ring, vertices[e.From][0], vertices[e.From][1])
+			if e.To == edges[start].From {
+				break
+			}
+			next := -1
+			bestTurn := math.Inf(-1)
+			inX, inY := vertices[e.To][0] - vertices[e.From][0], vertices[e.To][1] - vertices[e.From][1]
+			for _, candidate := range(outgoing[e.To]) {
+				if used[candidate] {
+					continue
+				}
+				c := edges[candidate]
+				outX, outY := vertices[c.To][0] - vertices[c.From][0], vertices[c.To][1] - vertices[c.From][1]
+				turn := math.Atan2(inX * outY - inY * outX, inX * outX + inY * outY)
+				if turn > bestTurn {
+					bestTurn, next = turn, candidate
+				}
+			}
+			if next == -1 {
+				// open chain, only possible with invalid input
+				ring = nil
+				break
+			}
+			current = next
+		}
+		ring = removeCollinear(ring)
+		if len(ring) / 2 >= 3 {
+			rings = append(rings, CloseRing(rotateToLowest(ring)))
+		}
+	}
+	return rings
+}
+
+// Split the edges of both rings at every point where they meet the other ring. Returns the pieces as (from, to) pairs
+func splitRings (a, b []float64) (piecesA, piecesB [][2][2]float64) {
+	na, nb := len(a) / 2, len(b) / 2
+	splitsA := make([][][2]float64, na)
+	splitsB := make([][][2]float64, nb)
+	for i := 0; i < na; i++ {
+		ax1, ay1 := a[2 * i], a[2 * i + 1]
+		ax2, ay2 := a[2 * ((i + 1) % na)], a[2 * ((i + 1) % na) + 1]
+		for j := 0; j < nb; j++ {
+			bx1, by1 := b[2 * j], b[2 * j + 1]
+			bx2, by2 := b[2 * ((j + 1) % nb)], b[2 * ((j + 1) % nb) + 1]
+			SplitEdgePair(ax1, ay1, ax2, ay2, bx1, by1, bx2, by2, &splitsA[i], &splitsB[j])
+		}
+	}
+	return RingPieces(a, splitsA), RingPieces(b, splitsB)
+}
+
+// Add to splitsA and splitsB the points where edges a and b meet, in the interior of a and b respectively
+func SplitEdgePair (ax1, ay1, ax2, ay2, bx1, by1, bx2, by2 float64, splitsA, splitsB *[][2]float64) {
+	o1 := Orientation(ax1, ay1, ax2, ay2, bx1, by1)
+	o2 := Orientation(ax1, ay1, ax2, ay2, bx2, by2)
+	o3 := Orientation(bx1, by1, bx2, by2, ax1, ay1)
+	o4 := Orientation(bx1, by1, bx2, by2, ax2, ay2)
+	if o1 * o2 < 0 && o3 * o4 < 0 {
+		t := o3 / (o3 - o4)
+		p := [2]float64{ax1 + t * (ax2 - ax1), ay1 + t * (ay2 - ay1)}
+		*splitsA = append(*splitsA, p)
+		*splitsB = append(*splitsB, p)
+		return
+	}
+	// touching configurations: an endpoint of one edge lies on the other
+	if o1 == 0 && strictlyInsideSegment(ax1, ay1, ax2, ay2, bx1, by1) {
+		*splitsA = append(*splitsA, [2]float64{bx1, by1})
+	}
+	if o2 == 0 && strictlyInsideSegment(ax1, ay1, ax2, ay2, bx2, by2) {
+		*splitsA = append(*splitsA, [2]float64{bx2, by2})
+	}
+	if o3 == 0 && strictlyInsideSegment(bx1, by1, bx2, by2, ax1, ay1) {
+		*splitsB = append(*splitsB, [2]float64{ax1, ay1})
+	}
+	if o4 == 0 && strictlyInsideSegment(bx1, by1, bx2, by2, ax2, ay2) {
+		*splitsB = append(*splitsB, [2]float64{ax2, ay2})
+	}
+}
+
+// Edges of an open ring split at the points of splits, one list per edge, as (from, to) pairs in the order of the ring.
+// The points of each list are sorted along their edge
+func RingPieces (ring []float64, splits [][][2]float64) (pieces [][2][2]float64) {
+	n := len(ring) / 2
+	for i := 0; i < n; i++ {
+		x1, y1 := ring[2 * i], ring[2 * i + 1]
+		x2, y2 := ring[2 * ((i + 1) % n)], ring[2 * ((i + 1) % n) + 1]
+		points := splits[i]
+		sort.Slice(points, func (k, l int) bool {
+			return (points[k][0] - x1) * (x2 - x1) + (points[k][1] - y1) * (y2 - y1) < (points[l][0] - x1) * (x2 - x1) + (points[l][1] - y1) * (y2 - y1)
+		})
+		previous := [2]float64{x1, y1}
+		for _, p := range(append(points, [2]float64{x2, y2})) {
+			if p != previous {
+				pieces = append(pieces, [2][2]float64{previous, p})
+				previous = p
+			}
+		}
+	}
+	return pieces
+}
+
+// Whether (px, py), known to be collinear with the segment, lies strictly between its endpoints
+func strictlyInsideSegment (x1, y1, x2, y2, px, py float64) bool {
+	if px == x1 && py == y1 || px == x2 && py == y2 {
+		return false
+	}
+	return math.Min(x1, x2) <= px && px <= math.Max(x1, x2) && math.Min(y1, y2) <= py && py <= math.Max(y1, y2)
+}
+
+// Remove vertices of an open ring lying on the line between their neighbours, typically left by edge splitting
+func removeCollinear (ring []float64) []float64 {
+	for changed := true; changed && len(ring) / 2 >= 3; {
+		changed = false
+		n := len(ring) / 2
+		kept := make([]float64, 0, len(ring))
+		for i := 0; i < n; i++ {
+			p, next := (i + n - 1) % n, (i + 1) % n
+			if Orientation(ring[2 * p], ring[2 * p + 1], ring[2 * i], ring[2 * i + 1], ring[2 * next], ring[2 * next + 1]) == 0 {
+				changed = true
+				// skip only one vertex per pass so that neighbours stay valid
+				kept = append(kept, ring[2 * (i + 1):]...)
+				break
+			}
+			kept = append(kept, ring[2 * i], ring[2 * i + 1])
+		}
+		ring = kept
+	}
+	return ring
+}
+
+// Rotate an open ring so that it starts at its lexicographically lowest vertex
+func rotateToLowest (ring []float64) []float64 {
+	lowest := 0
+	for i := 1; i < len(ring) / 2; i++ {
+		if ring[2 * i] < ring[2 * lowest] || ring[2 * i] == ring[2 * lowest] && ring[2 * i + 1] < ring[2 * lowest + 1] {
+			lowest = i
+		}
+	}
+	return append(append([]float64{}, ring[2 * lowest:]...), ring[:2 * lowest]...)
+}
+
+// Open, counter clockwise copy of the ring
+func CounterClockwise (ring []float64) []float64 {
+	ring = OpenRing(ring)
+	if SignedArea(ring) < 0 {
+		return ReverseRing(ring)
+	}
+	return append([]float64{}, ring...)
+}
+
+// Overlay when at least one of the operands has no area
+func degenerateOverlay (a, b []float64, op overlayOperation) [][]float64 {
+	var result [][]float64
+	if len(a) / 2 >= 3 && op != overlayIntersection {
+		result = append(result, CloseRing(a))
+	}
+	if len(b) / 2 >= 3 && op == overlayUnion {
+		result = append(result, CloseRing(b))
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package geomutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOverlay (t *testing.T) {
+	a := []float64{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}
+	b := []float64{1, 1, 3, 1, 3, 3, 1, 3, 1, 1}
+	if rings := Intersection(a, b); !reflect.DeepEqual(rings, [][]float64{{1, 1, 2, 1, 2, 2, 1, 2, 1, 1}}) {
+		t.Errorf("intersection is %v", rings)
+	}
+	if rings := Union(a, b); len(rings) != 1 || SignedArea(rings[0]) != 7 {
+		t.Errorf("union is %v", rings)
+	}
+	if rings := Difference(a, b); len(rings) != 1 || SignedArea(rings[0]) != 3 {
+		t.Errorf("difference is %v", rings)
+	}
+	// clockwise, sharing the edge x = 2
+	if rings := Union(a, []float64{2, 0, 2, 2, 4, 2, 4, 0}); !reflect.DeepEqual(rings, [][]float64{{0, 0, 4, 0, 4, 2, 0, 2, 0, 0}}) {
+		t.Errorf("union is %v", rings)
+	}
+}
+
+func TestChainEdges (t *testing.T) {
+	vertices := [][2]float64{{1, 1}, {0, 1}, {0, 0}, {1, 0}, {0.5, 0}}
+	// starts at the lowest vertex, without the collinear one
+	edges := []Edge{{From: 0, To: 1}, {From: 1, To: 2}, {From: 2, To: 4}, {From: 4, To: 3}, {From: 3, To: 0}}
+	if rings := ChainEdges(vertices, edges); !reflect.DeepEqual(rings, [][]float64{{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}}) {
+		t.Errorf("rings are %v", rings)
+	}
+	// an open chain gives no ring
+	if rings := ChainEdges(vertices, edges[:4]); len(rings) != 0 {
+		t.Errorf("rings are %v", rings)
+	}
+}
@@ -0,0 +1,38 @@
+// Package gonumpoints converts between gonum matrices of n rows of x and y and ConcaveHull points:
+//
+//	hull := ConcaveHull.Compute(gonumpoints.FromMatrix(m))
+package gonumpoints
+
+import (
+	"fmt"
+	"github.com/USACE/concavehull"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Points of an n×2 matrix, x in the first column and y in the second. The data of a dense row major matrix whose rows are
+// contiguous, such as mat.NewDense(n, 2, data), is used in place: computations that sort the points also reorder the rows of
+// the matrix. Other matrices, including transposes and column slices of wider matrices, are copied
+func FromMatrix (m mat.Matrix) (ConcaveHull.FlatPoints, error) {
+	rows, cols := m.Dims()
+	if cols != 2 {
+		return nil, fmt.Errorf("%w: matrix has %d columns instead of 2", ConcaveHull.ErrMalformedPoints, cols)
+	}
+	if raw, ok := m.(mat.RawMatrixer); ok {
+		if general := raw.RawMatrix(); general.Stride == 2 && len(general.Data) >= 2 * rows {
+			return ConcaveHull.FlatPoints(general.Data[:2 * rows]), nil
+		}
+	}
+	points := make(ConcaveHull.FlatPoints, 0, 2 * rows)
+	for i := 0; i < rows; i++ {
+		points = append(points, m.At(i, 0), m.At(i, 1))
+	}
+	return points, nil
+}
+
+// n×2 matrix of the points, such as a hull, sharing their data
+func ToMatrix (points ConcaveHull.FlatPoints) *mat.Dense {
+	if points.Len() == 0 {
+		return &mat.Dense{}
+	}
+	return mat.NewDense(points.Len(), 2, points[:2 * points.Len()])
+}
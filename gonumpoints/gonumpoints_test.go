@@ -0,0 +1,39 @@
+package gonumpoints
+
+import (
+	"errors"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestFromMatrix (t *testing.T) {
+	data := []float64{1, 1, 0, 0, 1, 0, 0, 1, 0.5, 0.4}
+	m := mat.NewDense(5, 2, data)
+	points, err := FromMatrix(m)
+	assert.NoError(t, err)
+	assert.Equal(t, ConcaveHull.FlatPoints(data), points)
+	// shared, sorting the points sorts the rows of the matrix
+	points[0] = 2
+	assert.Equal(t, 2., m.At(0, 0))
+
+	wide := mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	points, err = FromMatrix(wide.Slice(0, 2, 1, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, ConcaveHull.FlatPoints{2, 3, 5, 6}, points)
+	points, err = FromMatrix(mat.NewDense(2, 2, []float64{1, 2, 3, 4}).T())
+	assert.NoError(t, err)
+	assert.Equal(t, ConcaveHull.FlatPoints{1, 3, 2, 4}, points)
+
+	_, err = FromMatrix(wide)
+	assert.True(t, errors.Is(err, ConcaveHull.ErrMalformedPoints))
+}
+
+func TestToMatrix (t *testing.T) {
+	hull := ConcaveHull.Compute(ConcaveHull.FlatPoints{0, 0, 1, 0, 1, 1, 0, 1, 0.5, 0.5})
+	m := ToMatrix(hull)
+	rows, cols := m.Dims()
+	assert.Equal(t, []int{hull.Len(), 2}, []int{rows, cols})
+	assert.Equal(t, hull[2], m.At(1, 0))
+}
@@ -0,0 +1,119 @@
+package ConcaveHull
+
+import "math"
+
+// Uniform grid of points with about one point per cell, for nearest neighbour searches with any Metric
+type gridIndex struct {
+	minX, minY, cellSize float64
+	nx, ny int
+	// points of cell i are points[2 * start[i]: 2 * start[i + 1]]
+	start []int
+	points FlatPoints
+}
+
+func newGridIndex (points FlatPoints) *gridIndex {
+	n := points.Len()
+	minX, minY, maxX, maxY := bbox(points)
+	g := &gridIndex{minX: minX, minY: minY, nx: 1, ny: 1}
+	g.cellSize = math.Max(maxX - minX, maxY - minY) / math.Max(math.Sqrt(float64(n)), 1)
+	if g.cellSize > 0 {
+		g.nx = int((maxX - minX) / g.cellSize) + 1
+		g.ny = int((maxY - minY) / g.cellSize) + 1
+	} else {
+		g.cellSize = 1
+	}
+	// counting sort of the points by cell
+	g.start = make([]int, g.nx * g.ny + 1)
+	cells := make([]int, n)
+	for i := 0; i < n; i++ {
+		cx, cy := g.cell(points.Take(i))
+		cells[i] = cy * g.nx + cx
+		g.start[cells[i] + 1]++
+	}
+	for i := 1; i < len(g.start); i++ {
+		g.start[i] += g.start[i - 1]
+	}
+	next := append([]int{}, g.start[:len(g.start) - 1]...)
+	g.points = make(FlatPoints, 2 * n)
+	for i, c := range(cells) {
+		g.points[2 * next[c]], g.points[2 * next[c] + 1] = points[2 * i], points[2 * i + 1]
+		next[c]++
+	}
+	return g
+}
+
+func (g *gridIndex) cell (x, y float64) (int, int) {
+	return int(math.Floor((x - g.minX) / g.cellSize)), int(math.Floor((y - g.minY) / g.cellSize))
+}
+
+// Closest point to (x, y) at a distance of at most radius. Rings of cells are visited until the metric guarantees that
+// no point further away can be closer
+func (g *gridIndex) nearestWithin (x, y, radius float64, metric Metric) (nearestX, nearestY float64, found bool) {
+	cx, cy := g.cell(x, y)
+	best := radius
+	factor := metric.EuclideanFactor()
+	maxRing := intMax(intMax(cx, g.nx - 1 - cx), intMax(cy, g.ny - 1 - cy))
+	for ring := 0; ring <= maxRing; ring++ {
+		for i := cx - ring; i <= cx + ring; i++ {
+			if i < 0 || i >= g.nx {
+				continue
+			}
+			for j := cy - ring; j <= cy + ring; j++ {
+				if j < 0 || j >= g.ny || (i != cx - ring && i != cx + ring && j != cy - ring && j != cy + ring) {
+					continue
+				}
+				c := j * g.nx + i
+				for k := g.start[c]; k < g.start[c + 1]; k++ {
+					px, py := g.points.Take(k)
+					if d := metric.Distance(x, y, px, py); d <= best {
+						best, nearestX, nearestY, found = d, px, py, true
+					}
+				}
+			}
+		}
+		// points in further rings are at least ring cells away
+		if factor * float64(ring) * g.cellSize > best {
+			break
+		}
+	}
+	return
+}
+
+// Uniform grid of square cells covering a bounding box
+type cellGrid struct {
+	minX, minY, cellSize float64
+	nx, ny int
+}
+
+// Grid with about cells cells along the longer side of the box, at least one
+func newCellGrid (minX, minY, maxX, maxY, cells float64) cellGrid {
+	g := cellGrid{minX: minX, minY: minY, nx: 1, ny: 1}
+	g.cellSize = math.Max(maxX - minX, maxY - minY) / math.Max(cells, 1)
+	if g.cellSize > 0 {
+		g.nx = int((maxX - minX) / g.cellSize) + 1
+		g.ny = int((maxY - minY) / g.cellSize) + 1
+	} else {
+		g.cellSize = 1
+	}
+	return g
+}
+
+// Cell of (x, y), clamped to the grid
+func (g cellGrid) cell (x, y float64) (int, int) {
+	cx, cy := int(math.Floor((x - g.minX) / g.cellSize)), int(math.Floor((y - g.minY) / g.cellSize))
+	return intMin(intMax(cx, 0), g.nx - 1), intMin(intMax(cy, 0), g.ny - 1)
+}
+
+func intMax (a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func intMin (a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,83 @@
+package ConcaveHull
+
+import (
+	"math"
+	"sort"
+)
+
+// The part of an H3 library needed to cover hulls, so that this package doesn't depend on cgo. With github.com/uber/h3-go/v4
+// an adapter is a few lines:
+//
+//	type h3Adapter struct{}
+//	func (h3Adapter) LatLngToCell (lat, lng float64, res int) uint64 { c, _ := h3.LatLngToCell(h3.NewLatLng(lat, lng), res); return uint64(c) }
+//	func (h3Adapter) CellToLatLng (cell uint64) (float64, float64) { ll, _ := h3.Cell(cell).LatLng(); return ll.Lat, ll.Lng }
+//	func (h3Adapter) GridDisk (cell uint64, k int) []uint64 { ... convert h3.GridDisk(h3.Cell(cell), k) ... }
+type H3Indexer interface {
+	LatLngToCell (lat, lng float64, resolution int) uint64
+	CellToLatLng (cell uint64) (lat, lng float64)
+	// Cells within k steps of cell, including it
+	GridDisk (cell uint64, k int) []uint64
+}
+
+// H3 cells of the given resolution whose center lies inside the hull, the polyfill semantics of H3. Points are longitude,
+// latitude in degrees. Cells along the boundary are found by sampling the edges, then the interior is flood filled from them.
+// Cells are sorted
+func (h Hull) H3Cover (resolution int, indexer H3Indexer) []uint64 {
+	ring := closeRing(h.Points)
+	if ring.Len() < 4 {
+		return nil
+	}
+	lng0, lat0 := ring.Take(0)
+	spacing := h3Spacing(indexer, indexer.LatLngToCell(lat0, lng0, resolution))
+	visited := map[uint64]bool{}
+	var queue []uint64
+	visit := func (cell uint64) {
+		if !visited[cell] {
+			visited[cell] = true
+			queue = append(queue, cell)
+		}
+	}
+	for i := 0; i + 1 < ring.Len(); i++ {
+		x1, y1 := ring.Take(i)
+		x2, y2 := ring.Take(i + 1)
+		n := math.Max(1, math.Ceil(math.Hypot(x2 - x1, y2 - y1) / spacing))
+		for k := 0.; k < n; k++ {
+			visit(indexer.LatLngToCell(y1 + (y2 - y1) * k / n, x1 + (x2 - x1) * k / n, resolution))
+		}
+	}
+	// boundary cells are always expanded, interior cells only while their center is inside
+	boundary := len(queue)
+	var cells []uint64
+	for i := 0; i < len(queue); i++ {
+		cell := queue[i]
+		lat, lng := indexer.CellToLatLng(cell)
+		inside := ringContains(ring, lng, lat)
+		if inside {
+			cells = append(cells, cell)
+		}
+		if inside || i < boundary {
+			for _, neighbour := range(indexer.GridDisk(cell, 1)) {
+				visit(neighbour)
+			}
+		}
+	}
+	sort.Slice(cells, func (i, j int) bool { return cells[i] < cells[j] })
+	return cells
+}
+
+// Sampling step along edges, in degrees, half of the distance between the centers of neighbouring cells
+func h3Spacing (indexer H3Indexer, cell uint64) float64 {
+	lat, lng := indexer.CellToLatLng(cell)
+	spacing := math.Inf(1)
+	for _, neighbour := range(indexer.GridDisk(cell, 1)) {
+		if neighbour == cell {
+			continue
+		}
+		nLat, nLng := indexer.CellToLatLng(neighbour)
+		spacing = math.Min(spacing, math.Hypot(nLat - lat, nLng - lng) / 2)
+	}
+	if math.IsInf(spacing, 1) {
+		return 1
+	}
+	return spacing
+}
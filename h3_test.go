@@ -0,0 +1,46 @@
+package ConcaveHull
+
+import (
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+// Square cells of size 1 / 2^resolution degrees, standing in for H3 in tests
+type squareIndexer struct{}
+
+func (squareIndexer) LatLngToCell (lat, lng float64, resolution int) uint64 {
+	size := math.Ldexp(1, -resolution)
+	return uint64(int64(math.Floor(lng / size)) + 1 << 20) << 32 | uint64(int64(math.Floor(lat / size)) + 1 << 20) << 8 | uint64(resolution)
+}
+
+func (squareIndexer) CellToLatLng (cell uint64) (float64, float64) {
+	resolution := int(cell & 0xff)
+	size := math.Ldexp(1, -resolution)
+	column, row := int64(cell >> 32) - 1 << 20, int64((cell >> 8) & 0xffffff) - 1 << 20
+	return (float64(row) + 0.5) * size, (float64(column) + 0.5) * size
+}
+
+func (s squareIndexer) GridDisk (cell uint64, k int) []uint64 {
+	lat, lng := s.CellToLatLng(cell)
+	resolution := int(cell & 0xff)
+	size := math.Ldexp(1, -resolution)
+	var disk []uint64
+	for i := -k; i <= k; i++ {
+		for j := -k; j <= k; j++ {
+			disk = append(disk, s.LatLngToCell(lat + float64(i) * size, lng + float64(j) * size, resolution))
+		}
+	}
+	return disk
+}
+
+func TestHull_H3Cover (t *testing.T) {
+	// 10 by 10 cells of size 1/4 with a notch: centers inside are those of the square minus the notch
+	h := Hull{Points: FlatPoints{0, 0, 2.5, 0, 2.5, 2.5, 1.5, 2.5, 1.5, 1, 1, 1, 1, 2.5, 0, 2.5, 0, 0}}
+	cells := h.H3Cover(2, squareIndexer{})
+	assert.Equal(t, 100 - 12, len(cells))
+	for _, cell := range(cells) {
+		lat, lng := squareIndexer{}.CellToLatLng(cell)
+		assert.True(t, ringContains(h.Points, lng, lat))
+	}
+}
@@ -0,0 +1,204 @@
+package ConcaveHull
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Detailed result of a concave hull computation
+type Hull struct {
+	Points FlatPoints
+	// Some edges of the convex hull were left straight because the time budget expired, the context was cancelled or
+	// Options.MaxProbes was reached
+	Partial bool
+	// Origin of each vertex of Points
+	Provenance []VertexKind
+	// Indices of the points discarded before computing the hull, by outlier rejection, trimming or density filtering.
+	// Indices refer to the points after sorting, which is the order the input slice is left in
+	Dropped []int
+	// Pieces of the hull after splitting it at narrow bridges, only set if Options.BridgeWidth is set
+	Parts []FlatPoints
+	Stats SegmentizeStats
+	// Deviations from the options, for callers to log or alert on
+	Warnings []Warning
+	// Index of the edges of Points built by Project
+	boundary *edgeIndex
+}
+
+// Why a computation deviated from its options
+type WarningKind uint8
+
+const (
+	WarningSeglengthClamped WarningKind = iota // seglength was below the precision of the coordinates and was raised
+	WarningConvexHull // seglength is at least as long as every edge of the convex hull, so the hull is the convex hull
+	WarningProbeLimit // Options.MaxProbes was reached and some edges were left straight
+	WarningTimeBudget // Options.TimeBudget was spent and some edges were left straight
+	WarningCheckpoint // Options.CheckpointPath could not be read, written or removed
+)
+
+func (k WarningKind) String () string {
+	switch k {
+	case WarningSeglengthClamped:
+		return "seglength clamped"
+	case WarningConvexHull:
+		return "convex hull"
+	case WarningProbeLimit:
+		return "probe limit"
+	case WarningTimeBudget:
+		return "time budget"
+	case WarningCheckpoint:
+		return "checkpoint"
+	}
+	return "unknown"
+}
+
+type Warning struct {
+	Kind WarningKind
+	Detail string
+}
+
+func (w Warning) String () string {
+	return w.Kind.String() + ": " + w.Detail
+}
+
+// Work done by the bisection of the edges of the convex hull, to size servers and tune seglength
+type SegmentizeStats struct {
+	Probes int // nearest neighbour searches
+	MaxStack int // high water mark of the bisection stack, which is bounded whatever the parameters
+}
+
+// Where a vertex of the hull comes from. Simplification only removes vertices, so it creates no kind of its own
+type VertexKind uint8
+
+const (
+	VertexInput VertexKind = iota // one of the input points, that is, a measured position
+	VertexInterpolated // synthetic point placed along the boundary, for example when subdividing or smoothing edges
+)
+
+func (k VertexKind) String () string {
+	switch k {
+	case VertexInput:
+		return "input"
+	case VertexInterpolated:
+		return "interpolated"
+	}
+	return "unknown"
+}
+
+// Same as ComputeWithOptions but stops refining the hull when ctx is cancelled.
+// Edges are refined from longest to shortest, so a partial hull is the best approximation available at that moment
+// Malformed points or options are reported as errors, and so is any panic during the computation, as a PanicError
+func ComputeContext (ctx context.Context, points FlatPoints, o *Options) (hull Hull, err error) {
+	o = o.snapshot()
+	start, inputPoints := time.Now(), points.Len()
+	defer func () { observe(o, start, inputPoints, hull.Points, err) }()
+	defer recoverPanic(&err)
+	if err := ctx.Err(); err != nil {
+		return Hull{}, contextError(err)
+	}
+	if err := validatePoints(points); err != nil {
+		return Hull{}, err
+	}
+	points = clipInput(points, o)
+	span := startSpan(ctx, o, SPAN_SORT)
+	sort.Sort(LexSorter(points))
+	span.End()
+	return computeFromSortedContext(ctx, points, o)
+}
+
+// Same as ComputeFromSortedWithOptions but stops refining the hull when ctx is cancelled.
+// On cancellation ctx.Err() is returned, also matching ErrTimeout if the deadline passed, unless Options.AllowPartial is set, in which
+// case the partially refined hull is returned
+// Unsorted points are reported as an *UnsortedError, unless Options.SkipSortCheck is set
+func ComputeFromSortedContext (ctx context.Context, points FlatPoints, o *Options) (hull Hull, err error) {
+	o = o.snapshot()
+	start, inputPoints := time.Now(), points.Len()
+	defer func () { observe(o, start, inputPoints, hull.Points, err) }()
+	defer recoverPanic(&err)
+	if err := ctx.Err(); err != nil {
+		return Hull{}, contextError(err)
+	}
+	if err := validatePoints(points); err != nil {
+		return Hull{}, err
+	}
+	if o == nil || !o.SkipSortCheck {
+		if err := CheckSorted(points); err != nil {
+			return Hull{}, err
+		}
+	}
+	return computeFromSortedContext(ctx, points, o)
+}
+
+func computeFromSortedContext (ctx context.Context, points FlatPoints, o *Options) (Hull, error) {
+	if err := validateOptions(o); err != nil {
+		return Hull{}, err
+	}
+	points = clipInput(points, o)
+	if err := checkStrictInput(points, o); err != nil {
+		return Hull{}, err
+	}
+	return finishHull(ctx, computeFromSortedWithContext(ctx, points, o), o)
+}
+
+// With Options.Strict, ErrDegenerateInput if the points don't span an area
+func checkStrictInput (points FlatPoints, o *Options) error {
+	if o == nil || !o.Strict || spansArea(points) {
+		return nil
+	}
+	return fmt.Errorf("%w: %d points without area", ErrDegenerateInput, points.Len())
+}
+
+// Whether some three of the points are not collinear
+func spansArea (points FlatPoints) bool {
+	n := points.Len()
+	if n < 3 {
+		return false
+	}
+	x1, y1 := points.Take(0)
+	far := -1
+	for i := 1; i < n; i++ {
+		if x, y := points.Take(i); x != x1 || y != y1 {
+			far = i
+			break
+		}
+	}
+	if far < 0 {
+		return false
+	}
+	x2, y2 := points.Take(far)
+	for i := far + 1; i < n; i++ {
+		if x, y := points.Take(i); orientation(x1, y1, x2, y2, x, y) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Post processing shared by the context aware entry points
+func finishHull (ctx context.Context, hull Hull, o *Options) (Hull, error) {
+	if err := ctx.Err(); hull.Partial && err != nil && (o == nil || !o.AllowPartial) {
+		return Hull{}, contextError(err)
+	}
+	if o != nil && o.Strict {
+		for _, w := range(hull.Warnings) {
+			switch w.Kind {
+			case WarningSeglengthClamped:
+				return Hull{}, fmt.Errorf("%w, %s", ErrInvalidSeglength, w.Detail)
+			case WarningProbeLimit:
+				return Hull{}, fmt.Errorf("%w: %s", ErrBudgetExceeded, w.Detail)
+			case WarningTimeBudget:
+				return Hull{}, fmt.Errorf("%w: %s", ErrTimeout, w.Detail)
+			}
+		}
+	}
+	// Snapping and Douglas Peucker only keep input points, only subdivision reports its vertices
+	if hull.Provenance == nil {
+		hull.Provenance = make([]VertexKind, hull.Points.Len())
+	}
+	if o != nil && o.BridgeWidth > 0 {
+		hull.Parts = SplitNarrow(hull.Points, o.BridgeWidth)
+	}
+	return hull, nil
+}
@@ -0,0 +1,322 @@
+package ConcaveHull
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestComputeContext (t *testing.T) {
+	points := FlatPoints{1./3., 0.5, 0.0, 0.0, 1.0, 0.0, 0.0, 1.0, 1.0, 1.0}
+	hull, err := ComputeContext(context.Background(), append(FlatPoints{}, points...), nil)
+	assert.NoError(t, err)
+	assert.False(t, hull.Partial)
+	compareConcaveHulls(t, hull.Points, FlatPoints{0.0, 0.0, 1.0, 0., 1., 1., 0., 1., 1./3., 0.5, 0.0, 0.0})
+	assert.Equal(t, []VertexKind{VertexInput, VertexInput, VertexInput, VertexInput, VertexInput, VertexInput}, hull.Provenance)
+}
+
+func TestComputeContext_cancelled (t *testing.T) {
+	points := FlatPoints{1./3., 0.5, 0.0, 0.0, 1.0, 0.0, 0.0, 1.0, 1.0, 1.0}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ComputeContext(ctx, append(FlatPoints{}, points...), nil)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestComputeFromSortedContext_partial (t *testing.T) {
+	points := FlatPoints{0.0, 0.0, 0.0, 1.0, 1./3., 0.5, 1.0, 0.0, 1.0, 1.0}
+	// cancelled once the index is built, as soon as edges are refined
+	ctx := &cancelOnSecondCheck{Context: context.Background()}
+	hull, err := ComputeFromSortedContext(ctx, points, &Options{AllowPartial: true})
+	assert.NoError(t, err)
+	assert.True(t, hull.Partial)
+	compareConcaveHulls(t, hull.Points, FlatPoints{0.0, 0.0, 1.0, 0.0, 1.0, 1.0, 0.0, 1.0, 0., 0.})
+}
+
+type cancelOnSecondCheck struct {
+	context.Context
+	checks int
+}
+
+func (c *cancelOnSecondCheck) Done () <-chan struct{} {
+	return make(chan struct{})
+}
+
+func (c *cancelOnSecondCheck) Err () error {
+	c.checks++
+	if c.checks > 1 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestComputeContext_maxEdgeLength (t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+	for _, algorithm := range([]Algorithm{AlgorithmSnapHull, AlgorithmEdgeLength}) {
+		points := hulltest.Ring(r, 300, 0.3, 0.5)
+		input := append([]float64{}, points...)
+		hull, err := ComputeContext(context.Background(), FlatPoints(points), &Options{Seglength: 0.2, MaxEdgeLength: 0.05, Algorithm: algorithm})
+		assert.NoError(t, err)
+		assert.Nil(t, hulltest.CheckClosed(hull.Points))
+		interpolated := 0
+		for i := 0; i + 1 < hull.Points.Len(); i++ {
+			x1, y1 := hull.Points.Take(i)
+			x2, y2 := hull.Points.Take(i + 1)
+			assert.True(t, math.Hypot(x2 - x1, y2 - y1) <= 0.05 + 1e-12)
+			if hull.Provenance[i] == VertexInterpolated {
+				interpolated++
+			} else {
+				assert.Nil(t, hulltest.CheckVerticesFromInput(input, []float64{x1, y1}))
+			}
+		}
+		assert.True(t, interpolated > 0)
+	}
+}
+
+func TestComputeContext_errors (t *testing.T) {
+	_, err := ComputeContext(context.Background(), FlatPoints{0, 0, 1}, nil)
+	assert.True(t, errors.Is(err, ErrMalformedPoints))
+	_, err = ComputeContext(context.Background(), FlatPoints{0, 0, 1, math.NaN(), 1, 1}, nil)
+	assert.True(t, errors.Is(err, ErrMalformedPoints))
+	_, err = ComputeContext(context.Background(), FlatPoints{0, 0, 1, 0, 1, 1}, &Options{Seglength: math.Inf(1)})
+	assert.True(t, errors.Is(err, ErrInvalidOptions))
+	// panics in user supplied code are recovered
+	_, err = ComputeContext(context.Background(), FlatPoints{0, 0, 1, 0, 1, 1, 0.5, 0.1}, &Options{Seglength: 0.1, Metric: panickingMetric{}})
+	var panicError *PanicError
+	assert.True(t, errors.As(err, &panicError))
+	assert.Equal(t, "boom", panicError.Value)
+}
+
+type panickingMetric struct{}
+
+func (panickingMetric) Distance (x1, y1, x2, y2 float64) float64 {
+	panic("boom")
+}
+
+func (panickingMetric) EuclideanFactor () float64 {
+	return 1
+}
+
+func FuzzComputeContext (f *testing.F) {
+	f.Add([]byte{}, uint8(0))
+	f.Add(make([]byte, 48), uint8(1))
+	seed := make([]byte, 0, 160)
+	for _, v := range([]float64{0, 0, 1, 0, 1, 1, 0, 1, 0.5, 0.5, 0.5, 0.5, 1e300, -1e300, 5e-324, 0, 0, 0, 2, 2}) {
+		seed = binary.LittleEndian.AppendUint64(seed, math.Float64bits(v))
+	}
+	f.Add(seed, uint8(2))
+	f.Fuzz(func (t *testing.T, data []byte, mode uint8) {
+		_, err := ComputeContext(context.Background(), fuzzPoints(data), fuzzOptions(mode))
+		var panicError *PanicError
+		if errors.As(err, &panicError) {
+			t.Fatalf("%v\n%s", panicError.Value, panicError.Stack)
+		}
+	})
+}
+
+// The entry points without an error report malformed points by panicking, so only valid points are fuzzed, and any panic
+// fails the test
+func FuzzComputeWithOptions (f *testing.F) {
+	f.Add([]byte{}, uint8(0))
+	f.Add(make([]byte, 48), uint8(1))
+	seed := make([]byte, 0, 160)
+	for _, v := range([]float64{0, 0, 1, 0, 1, 1, 0, 1, 0.5, 0.5, 0.5, 0.5, 1e300, -1e300, 5e-324, 0, 0, 0, 2, 2}) {
+		seed = binary.LittleEndian.AppendUint64(seed, math.Float64bits(v))
+	}
+	f.Add(seed, uint8(3))
+	f.Fuzz(func (t *testing.T, data []byte, mode uint8) {
+		points := fuzzPoints(data)
+		if validatePoints(points) != nil {
+			return
+		}
+		o := fuzzOptions(mode)
+		sorted := ComputeWithOptions(append(FlatPoints{}, points...), o)
+		sort.Sort(LexSorter(points))
+		assert.Equal(t, sorted, ComputeFromSortedWithOptions(points, o))
+	})
+}
+
+func fuzzPoints (data []byte) FlatPoints {
+	points := make(FlatPoints, len(data) / 8)
+	for i := range(points) {
+		points[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8 * i:]))
+	}
+	return points
+}
+
+func fuzzOptions (mode uint8) *Options {
+	o := &Options{SeglengthRelative: 0.05}
+	switch mode % 4 {
+	case 1:
+		o.Algorithm = AlgorithmEdgeLength
+	case 2:
+		o.OutlierRejection, o.TrimFraction = 3, 0.1
+	case 3:
+		o.ExactArithmetic = true
+	}
+	return o
+}
+
+func TestComputeFromSortedContext_unsorted (t *testing.T) {
+	points := FlatPoints{0, 0, 1, 1, 1, 0, 0, 1}
+	_, err := ComputeFromSortedContext(context.Background(), points, nil)
+	var unsorted *UnsortedError
+	assert.True(t, errors.As(err, &unsorted))
+	assert.Equal(t, 2, unsorted.Index)
+	// without the check the result is undefined, but it is not reported as unsorted
+	_, err = ComputeFromSortedContext(context.Background(), points, &Options{SkipSortCheck: true})
+	assert.False(t, errors.As(err, &unsorted))
+	assert.True(t, errors.As(CheckSorted(points), &unsorted))
+	assert.NoError(t, CheckSorted(FlatPoints{0, 0, 0, 1, 1, 0, 1, 1}))
+}
+
+func TestComputeContext_subPrecisionSeglength (t *testing.T) {
+	r := rand.New(rand.NewSource(27))
+	points := hulltest.Random(r, 500)
+	for i := range(points) {
+		points[i] = 1e6 + points[i] * 1000
+	}
+	hull, err := ComputeContext(context.Background(), FlatPoints(append([]float64{}, points...)), &Options{Seglength: 1e-15})
+	assert.Nil(t, err)
+	assert.Len(t, hull.Warnings, 1)
+	assert.Equal(t, WarningSeglengthClamped, hull.Warnings[0].Kind)
+	hulltest.AssertValid(t, points, hull.Points)
+
+	hull, err = ComputeContext(context.Background(), FlatPoints(points), &Options{Seglength: 1})
+	assert.Nil(t, err)
+	assert.Len(t, hull.Warnings, 0)
+}
+
+func TestGuardSeglength_largeMagnitude (t *testing.T) {
+	square := FlatPoints{0, 0, 1e160, 0, 1e160, 1e160, 0, 1e160}
+	for _, o := range([]*Options{{Seglength: 1e158}, {Seglength: 1e158, CompatVersion: CompatV1}}) {
+		var c concaver
+		c.configure(nil, square, o, time.Now())
+		assert.Equal(t, 1e158, c.seglength)
+		assert.Len(t, c.warnings, 0)
+	}
+	var c concaver
+	c.configure(nil, square, &Options{SeglengthRelative: 0.05}, time.Now())
+	assert.InDelta(t, 0.05 * math.Sqrt2 * 1e160, c.seglength, 1e146)
+	assert.Len(t, c.warnings, 0)
+	// below the precision of the coordinates, raised to a finite seglength
+	c = concaver{}
+	c.configure(nil, square, &Options{Seglength: 1}, time.Now())
+	assert.Len(t, c.warnings, 1)
+	assert.Equal(t, WarningSeglengthClamped, c.warnings[0].Kind)
+	assert.False(t, math.IsInf(c.seglength, 0))
+	assert.True(t, c.seglength > 1 && c.seglength < 1e150)
+}
+
+func TestComputeContext_fallbackWarnings (t *testing.T) {
+	r := rand.New(rand.NewSource(28))
+	points := hulltest.Random(r, 2000)
+	hull, err := ComputeContext(context.Background(), FlatPoints(append([]float64{}, points...)), &Options{Seglength: 10})
+	assert.Nil(t, err)
+	assert.Len(t, hull.Warnings, 1)
+	assert.Equal(t, WarningConvexHull, hull.Warnings[0].Kind)
+	assert.False(t, hull.Partial)
+
+	full, err := ComputeContext(context.Background(), FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.001})
+	assert.Nil(t, err)
+	assert.Len(t, full.Warnings, 0)
+	assert.True(t, full.Stats.Probes > 100)
+	limited, err := ComputeContext(context.Background(), FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.001, MaxProbes: 100})
+	assert.Nil(t, err)
+	assert.True(t, limited.Partial)
+	assert.Len(t, limited.Warnings, 1)
+	assert.Equal(t, WarningProbeLimit, limited.Warnings[0].Kind)
+	assert.True(t, limited.Stats.Probes < full.Stats.Probes)
+	hulltest.AssertValid(t, points, limited.Points)
+}
+
+func TestComputeContext_errorTaxonomy (t *testing.T) {
+	_, err := ComputeContext(context.Background(), FlatPoints{0, 0, 1, 1}, &Options{Seglength: -1})
+	assert.True(t, errors.Is(err, ErrInvalidSeglength))
+	assert.True(t, errors.Is(err, ErrInvalidOptions))
+	_, err = ComputeContext(context.Background(), FlatPoints{0, 0, 1, 1}, &Options{MaxDepth: -1})
+	assert.False(t, errors.Is(err, ErrInvalidSeglength))
+	for _, v := range([]float64{math.NaN(), math.Inf(1), -1}) {
+		for _, o := range([]Options{
+			{ScaleX: v},
+			{ScaleY: v},
+			{DensityThreshold: v},
+			{OutlierRejection: v},
+			{TrimFraction: v},
+			{BridgeWidth: v},
+			{EdgeLengthThreshold: v},
+		}) {
+			o := o
+			_, err = ComputeContext(context.Background(), FlatPoints{0, 0, 1, 1}, &o)
+			assert.True(t, errors.Is(err, ErrInvalidOptions), "%+v", o)
+		}
+	}
+
+	_, err = ComputeFromSortedContext(context.Background(), FlatPoints{1, 0, 0, 0}, nil)
+	assert.True(t, errors.Is(err, ErrUnsortedInput))
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = ComputeContext(ctx, FlatPoints{0, 0, 1, 0, 0, 1}, nil)
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = ComputeContext(ctx, FlatPoints{0, 0, 1, 0, 0, 1}, nil)
+	assert.False(t, errors.Is(err, ErrTimeout))
+
+	// degenerate input is only an error with Strict
+	for _, points := range([]FlatPoints{{}, {1, 1, 1, 1, 1, 1}, {0, 0, 1, 1, 2, 2, 3, 3}}) {
+		_, err = ComputeContext(context.Background(), append(FlatPoints{}, points...), nil)
+		assert.NoError(t, err)
+		_, err = ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Strict: true})
+		assert.True(t, errors.Is(err, ErrDegenerateInput))
+	}
+	concaver := Prepare(FlatPoints{0, 0, 1, 1, 2, 2})
+	defer concaver.Close()
+	_, err = concaver.ComputeContext(context.Background(), &Options{Strict: true})
+	assert.True(t, errors.Is(err, ErrDegenerateInput))
+
+	r := rand.New(rand.NewSource(3))
+	points := FlatPoints(hulltest.Random(r, 2000))
+	hull, err := ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 0.001, MaxProbes: 100})
+	assert.NoError(t, err)
+	assert.True(t, hull.Partial)
+	_, err = ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 0.001, MaxProbes: 100, Strict: true})
+	assert.True(t, errors.Is(err, ErrBudgetExceeded))
+	hull, err = ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 0.001, TimeBudget: time.Nanosecond})
+	assert.NoError(t, err)
+	assert.Equal(t, WarningTimeBudget, hull.Warnings[len(hull.Warnings) - 1].Kind)
+	_, err = ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 0.001, TimeBudget: time.Nanosecond, Strict: true})
+	assert.True(t, errors.Is(err, ErrTimeout))
+	_, err = ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{Seglength: 1e-300, Strict: true})
+	assert.True(t, errors.Is(err, ErrInvalidSeglength))
+}
+
+func TestComputeContext_compatVersion (t *testing.T) {
+	r := rand.New(rand.NewSource(29))
+	points := hulltest.Random(r, 300)
+	for i := range(points) {
+		points[i] = 1e6 + points[i] * 1000
+	}
+	for _, version := range([]CompatVersion{0, CompatV2}) {
+		hull, err := ComputeContext(context.Background(), FlatPoints(append([]float64{}, points...)), &Options{Seglength: 1e-11, CompatVersion: version})
+		assert.Nil(t, err)
+		assert.Len(t, hull.Warnings, 1)
+	}
+	// version 1 used the seglength as given
+	hull, err := ComputeContext(context.Background(), FlatPoints(append([]float64{}, points...)), &Options{Seglength: 1e-11, CompatVersion: CompatV1})
+	assert.Nil(t, err)
+	assert.Len(t, hull.Warnings, 0)
+	hulltest.AssertValid(t, points, hull.Points)
+
+	_, err = ComputeContext(context.Background(), FlatPoints(points), &Options{CompatVersion: CompatLatest + 1})
+	assert.True(t, errors.Is(err, ErrInvalidOptions))
+}
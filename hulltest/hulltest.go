@@ -0,0 +1,168 @@
+// Package hulltest provides point generators and hull validity checks to property-test code built on top of ConcaveHull.
+//
+// Points and hulls are flat arrays of coordinates []float64{x0, y0, x1, y1, ...}, the same layout as ConcaveHull.FlatPoints,
+// so results can be converted in both directions without copying.
+package hulltest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/USACE/concavehull/geomutil"
+)
+
+// Uniformly distributed points in the unit square
+func Random (r *rand.Rand, n int) []float64 {
+	points := make([]float64, 0, 2 * n)
+	for i := 0; i < n; i++ {
+		points = append(points, r.Float64(), r.Float64())
+	}
+	return points
+}
+
+// n points split among the given number of clusters. Cluster centers are uniform in the unit square
+// and points are normally distributed around them with the given standard deviation
+func Clustered (r *rand.Rand, n, clusters int, spread float64) []float64 {
+	centers := Random(r, clusters)
+	points := make([]float64, 0, 2 * n)
+	for i := 0; i < n; i++ {
+		c := i % clusters
+		points = append(points, centers[2 * c] + r.NormFloat64() * spread, centers[2 * c + 1] + r.NormFloat64() * spread)
+	}
+	return points
+}
+
+// Points uniformly distributed in the annulus centered at (0.5, 0.5) with the given radii. Concave hulls of a ring
+// should hug the outer circle, while the convex hull is a polygon approximating it
+func Ring (r *rand.Rand, n int, inner, outer float64) []float64 {
+	points := make([]float64, 0, 2 * n)
+	for i := 0; i < n; i++ {
+		angle := r.Float64() * 2 * math.Pi
+		radius := math.Sqrt(inner * inner + r.Float64() * (outer * outer - inner * inner))
+		points = append(points, 0.5 + radius * math.Cos(angle), 0.5 + radius * math.Sin(angle))
+	}
+	return points
+}
+
+// Points on the segment from (0, 0) to (1, 1)
+func Collinear (r *rand.Rand, n int) []float64 {
+	points := make([]float64, 0, 2 * n)
+	for i := 0; i < n; i++ {
+		t := r.Float64()
+		points = append(points, t, t)
+	}
+	return points
+}
+
+// n copies of the same point
+func Duplicated (n int, x, y float64) []float64 {
+	points := make([]float64, 0, 2 * n)
+	for i := 0; i < n; i++ {
+		points = append(points, x, y)
+	}
+	return points
+}
+
+// Random points snapped to a grid of the given number of cells per side, so that many points share coordinates and lie on common lines
+func Grid (r *rand.Rand, n, cells int) []float64 {
+	points := make([]float64, 0, 2 * n)
+	for i := 0; i < n; i++ {
+		points = append(points, float64(r.Intn(cells + 1)) / float64(cells), float64(r.Intn(cells + 1)) / float64(cells))
+	}
+	return points
+}
+
+// Check that the hull is a closed ring of at least three distinct vertices
+func CheckClosed (hull []float64) error {
+	if len(hull) % 2 != 0 {
+		return fmt.Errorf("hull has an odd number of coordinates: %d", len(hull))
+	}
+	n := len(hull) / 2
+	if n < 4 {
+		return fmt.Errorf("hull has %d vertices, a closed ring needs at least 4", n)
+	}
+	if hull[0] != hull[2 * n - 2] || hull[1] != hull[2 * n - 1] {
+		return fmt.Errorf("hull is not closed: first vertex (%v, %v), last vertex (%v, %v)", hull[0], hull[1], hull[2 * n - 2], hull[2 * n - 1])
+	}
+	return nil
+}
+
+// Check that no two non adjacent edges of the closed ring intersect
+func CheckSimple (hull []float64) error {
+	if err := CheckClosed(hull); err != nil {
+		return err
+	}
+	n := len(hull) / 2 - 1
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if j == i + 1 || (i == 0 && j == n - 1) {
+				continue
+			}
+			if geomutil.SegmentsTouch(hull[2 * i], hull[2 * i + 1], hull[2 * i + 2], hull[2 * i + 3], hull[2 * j], hull[2 * j + 1], hull[2 * j + 2], hull[2 * j + 3]) {
+				return fmt.Errorf("edges %d and %d of the hull intersect", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// Check that every vertex of the hull is one of the input points
+func CheckVerticesFromInput (points, hull []float64) error {
+	input := make(map[[2]float64]bool, len(points) / 2)
+	for i := 0; i + 1 < len(points); i += 2 {
+		input[[2]float64{points[i], points[i + 1]}] = true
+	}
+	for i := 0; i + 1 < len(hull); i += 2 {
+		if !input[[2]float64{hull[i], hull[i + 1]}] {
+			return fmt.Errorf("vertex %d (%v, %v) of the hull is not an input point", i / 2, hull[i], hull[i + 1])
+		}
+	}
+	return nil
+}
+
+// Check that every point lies inside the closed ring or within tolerance of its boundary.
+// Snapping based concave hulls may legitimately leave some points outside, so this check is not part of CheckValid
+func CheckContains (points, hull []float64, tolerance float64) error {
+	if err := CheckClosed(hull); err != nil {
+		return err
+	}
+	for i := 0; i + 1 < len(points); i += 2 {
+		x, y := points[i], points[i + 1]
+		if geomutil.RingContains(hull, x, y) {
+			continue
+		}
+		if _, _, _, squared := geomutil.ClosestOnRing(hull, x, y); squared > tolerance * tolerance {
+			return fmt.Errorf("point %d (%v, %v) is outside of the hull", i / 2, x, y)
+		}
+	}
+	return nil
+}
+
+// Check that the hull is a closed simple ring whose vertices are input points
+func CheckValid (points, hull []float64) error {
+	if err := CheckSimple(hull); err != nil {
+		return err
+	}
+	return CheckVerticesFromInput(points, hull)
+}
+
+// Fail the test if CheckValid fails
+func AssertValid (t testing.TB, points, hull []float64) bool {
+	t.Helper()
+	if err := CheckValid(points, hull); err != nil {
+		t.Errorf("invalid hull: %v", err)
+		return false
+	}
+	return true
+}
+
+// Fail the test if CheckContains fails
+func AssertContains (t testing.TB, points, hull []float64, tolerance float64) bool {
+	t.Helper()
+	if err := CheckContains(points, hull, tolerance); err != nil {
+		t.Errorf("hull does not contain the points: %v", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package hulltest
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCheckValid (t *testing.T) {
+	square := []float64{0, 0, 1, 0, 1, 1, 0, 1}
+	if err := CheckValid(square, []float64{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}); err != nil {
+		t.Error(err)
+	}
+	if err := CheckValid(square, []float64{0, 0, 1, 0, 1, 1, 0, 1}); err == nil {
+		t.Error("open ring should be invalid")
+	}
+	if err := CheckValid(square, []float64{0, 0, 1, 1, 1, 0, 0, 1, 0, 0}); err == nil {
+		t.Error("bow tie should be invalid")
+	}
+	if err := CheckValid(square, []float64{0, 0, 2, 0, 1, 1, 0, 1, 0, 0}); err == nil {
+		t.Error("vertex not in input should be invalid")
+	}
+}
+
+func TestCheckContains (t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := Random(r, 100)
+	if err := CheckContains(points, []float64{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}, 0); err != nil {
+		t.Error(err)
+	}
+	if err := CheckContains(append(points, 2, 2), []float64{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}, 0.5); err == nil {
+		t.Error("point outside should be detected")
+	}
+}
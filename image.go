@@ -0,0 +1,23 @@
+package ConcaveHull
+
+import (
+	"image"
+	"math"
+)
+
+// Concave hull of pixel coordinates, such as those of a blob found by a detector, as a closed ring of pixels. Vertices are
+// input pixels, except those added by options such as MaxEdgeLength, which are rounded to the nearest pixel. Seglength and the
+// other lengths of the options are in pixels
+func ComputeImagePoints (pixels []image.Point, o *Options) []image.Point {
+	points := make(FlatPoints, 0, 2 * len(pixels))
+	for _, p := range(pixels) {
+		points = append(points, float64(p.X), float64(p.Y))
+	}
+	hull := ComputeWithOptions(points, o)
+	ring := make([]image.Point, hull.Len())
+	for i := range(ring) {
+		x, y := hull.Take(i)
+		ring[i] = image.Point{X: int(math.Round(x)), Y: int(math.Round(y))}
+	}
+	return ring
+}
@@ -0,0 +1,36 @@
+package ConcaveHull
+
+import (
+	"image"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeImagePoints (t *testing.T) {
+	// filled L shaped blob
+	var pixels []image.Point
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			if x < 5 || y >= 15 {
+				pixels = append(pixels, image.Point{X: x, Y: y})
+			}
+		}
+	}
+	hull := ComputeImagePoints(pixels, &Options{Seglength: 2})
+	assert.True(t, len(hull) > 4)
+	assert.Equal(t, hull[0], hull[len(hull) - 1])
+	inBlob := map[image.Point]bool{}
+	for _, p := range(pixels) {
+		inBlob[p] = true
+	}
+	for _, p := range(hull) {
+		assert.True(t, inBlob[p])
+	}
+	// the notch of the L is not covered
+	ring := make(FlatPoints, 0, 2 * len(hull))
+	for _, p := range(hull) {
+		ring = append(ring, float64(p.X), float64(p.Y))
+	}
+	assert.False(t, ringContains(ring, 15, 5))
+	assert.Len(t, ComputeImagePoints(nil, nil), 0)
+}
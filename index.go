@@ -0,0 +1,12 @@
+package ConcaveHull
+
+// Nearest neighbour search over the points of a computation, which finds the closest point to each probe of the bisection.
+// SimpleRTree is used unless Options.SpatialIndex is set
+type SpatialIndex interface {
+	// Closest point to (x, y) whose squared distance to it is at most maxSquaredDistance, found is false if there is none
+	FindNearestPointWithin (x, y, maxSquaredDistance float64) (px, py, squaredDistance float64, found bool)
+}
+
+// Builds the index of the points, sorted lexicographically. The points are only valid during the computation and must not
+// be modified
+type SpatialIndexBuilder func (points FlatPoints) SpatialIndex
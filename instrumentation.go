@@ -0,0 +1,102 @@
+package ConcaveHull
+
+import (
+	"expvar"
+	"time"
+)
+
+// Receives one observation per computation made through ComputeFromSortedWithOptions, ComputeContext, ComputeFromSortedContext
+// or Concaver.ComputeContext, see Options.Instrumentation. It is called on the goroutine of the computation, so it must be
+// safe for concurrent use if computations run concurrently
+type Instrumentation interface {
+	ObserveComputation (stats ComputationStats)
+}
+
+// Measurements of one computation
+type ComputationStats struct {
+	Duration time.Duration
+	InputPoints int
+	OutputPoints int
+	// Error returned by the computation, nil for the functions that don't report errors
+	Err error
+}
+
+// Report the computation to Options.Instrumentation, if set
+func observe (o *Options, start time.Time, inputPoints int, hull FlatPoints, err error) {
+	if o == nil || o.Instrumentation == nil {
+		return
+	}
+	o.Instrumentation.ObserveComputation(ComputationStats{
+		Duration: time.Since(start),
+		InputPoints: inputPoints,
+		OutputPoints: hull.Len(),
+		Err: err,
+	})
+}
+
+// Instrumentation publishing totals in an expvar.Map: computations, errors, duration_seconds, input_points and output_points.
+// Averages are the totals divided by computations
+type ExpvarInstrumentation struct {
+	Map *expvar.Map
+}
+
+// Publish the totals under name in expvar. Like expvar.NewMap it panics if name is already published, so create it once
+func NewExpvarInstrumentation (name string) ExpvarInstrumentation {
+	return ExpvarInstrumentation{Map: expvar.NewMap(name)}
+}
+
+func (e ExpvarInstrumentation) ObserveComputation (stats ComputationStats) {
+	e.Map.Add("computations", 1)
+	if stats.Err != nil {
+		e.Map.Add("errors", 1)
+	}
+	e.Map.AddFloat("duration_seconds", stats.Duration.Seconds())
+	e.Map.Add("input_points", int64(stats.InputPoints))
+	e.Map.Add("output_points", int64(stats.OutputPoints))
+}
+
+// Monotonic counter, satisfied by prometheus.Counter
+type Counter interface {
+	Inc ()
+}
+
+// Distribution of values, satisfied by prometheus.Histogram and prometheus.Summary
+type Observer interface {
+	Observe (float64)
+}
+
+// Instrumentation reporting to Prometheus collectors, which the caller creates and registers, for example:
+//
+//	ConcaveHull.PrometheusInstrumentation{
+//		Computations: promauto.NewCounter(prometheus.CounterOpts{Name: "concavehull_computations_total"}),
+//		Errors: promauto.NewCounter(prometheus.CounterOpts{Name: "concavehull_errors_total"}),
+//		Duration: promauto.NewHistogram(prometheus.HistogramOpts{Name: "concavehull_duration_seconds"}),
+//		InputPoints: promauto.NewHistogram(prometheus.HistogramOpts{Name: "concavehull_input_points", Buckets: prometheus.ExponentialBuckets(10, 10, 7)}),
+//	}
+//
+// Nil collectors are skipped
+type PrometheusInstrumentation struct {
+	Computations Counter
+	Errors Counter
+	Duration Observer // in seconds
+	InputPoints Observer
+	OutputPoints Observer
+}
+
+func (p PrometheusInstrumentation) ObserveComputation (stats ComputationStats) {
+	if p.Computations != nil {
+		p.Computations.Inc()
+	}
+	if p.Errors != nil && stats.Err != nil {
+		p.Errors.Inc()
+	}
+	if p.Duration != nil {
+		p.Duration.Observe(stats.Duration.Seconds())
+	}
+	if p.InputPoints != nil {
+		p.InputPoints.Observe(float64(stats.InputPoints))
+	}
+	if p.OutputPoints != nil {
+		p.OutputPoints.Observe(float64(stats.OutputPoints))
+	}
+}
@@ -0,0 +1,71 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+type recordingInstrumentation struct {
+	stats []ComputationStats
+}
+
+func (r *recordingInstrumentation) ObserveComputation (stats ComputationStats) {
+	r.stats = append(r.stats, stats)
+}
+
+type fakeCounter struct {
+	count int
+}
+
+func (c *fakeCounter) Inc () {
+	c.count++
+}
+
+type fakeObserver struct {
+	values []float64
+}
+
+func (o *fakeObserver) Observe (v float64) {
+	o.values = append(o.values, v)
+}
+
+func TestInstrumentation_observesComputations (t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	points := hulltest.Random(r, 300)
+	recording := &recordingInstrumentation{}
+	o := &Options{Seglength: 0.05, Instrumentation: recording}
+	hull := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), o)
+	_, err := ComputeContext(context.Background(), FlatPoints{0, 0, math.NaN(), 1}, o)
+	assert.Error(t, err)
+	assert.Equal(t, 2, len(recording.stats))
+	assert.Equal(t, 300, recording.stats[0].InputPoints)
+	assert.Equal(t, hull.Len(), recording.stats[0].OutputPoints)
+	assert.Nil(t, recording.stats[0].Err)
+	assert.Equal(t, 2, recording.stats[1].InputPoints)
+	assert.Equal(t, 0, recording.stats[1].OutputPoints)
+	assert.Equal(t, err, recording.stats[1].Err)
+}
+
+func TestPrometheusInstrumentation (t *testing.T) {
+	computations, errors, inputs := &fakeCounter{}, &fakeCounter{}, &fakeObserver{}
+	o := &Options{Instrumentation: PrometheusInstrumentation{Computations: computations, Errors: errors, InputPoints: inputs}}
+	ComputeContext(context.Background(), FlatPoints{0, 0, 1, 0, 0, 1, 1, 1}, o)
+	ComputeContext(context.Background(), FlatPoints{0, 0, 1}, o)
+	assert.Equal(t, 2, computations.count)
+	assert.Equal(t, 1, errors.count)
+	assert.Equal(t, []float64{4, 1}, inputs.values)
+}
+
+func TestExpvarInstrumentation (t *testing.T) {
+	instrumentation := NewExpvarInstrumentation("concavehull_test")
+	o := &Options{Instrumentation: instrumentation}
+	ComputeWithOptions(FlatPoints{0, 0, 1, 0, 0, 1, 1, 1}, o)
+	ComputeWithOptions(FlatPoints{0, 0, 2, 0, 0, 2, 2, 2}, o)
+	assert.Equal(t, "2", instrumentation.Map.Get("computations").String())
+	assert.Equal(t, "8", instrumentation.Map.Get("input_points").String())
+	assert.Nil(t, instrumentation.Map.Get("errors"))
+}
@@ -0,0 +1,60 @@
+package ConcaveHull
+
+import "math"
+
+// Index of the ring containing each point, -1 for points outside all of them, for example to label points with the cluster
+// hulls of ComputeClusters. Rings that overlap give the point to the first of them. Candidate rings are found in a grid of
+// their bounding boxes and tested against an index of their edges, so large point sets don't scan every ring
+func AssignPoints (rings []FlatPoints, points FlatPoints) []int {
+	assigned := make([]int, points.Len())
+	for i := range(assigned) {
+		assigned[i] = -1
+	}
+	boxes := make([][4]float64, len(rings))
+	edges := make([]*edgeIndex, len(rings))
+	var all FlatPoints
+	for r, ring := range(rings) {
+		ring = openRing(ring)
+		if ring.Len() < 3 {
+			continue
+		}
+		minX, minY, maxX, maxY := bbox(ring)
+		boxes[r] = [4]float64{minX, minY, maxX, maxY}
+		edges[r] = newEdgeIndex(ring)
+		all = append(all, minX, minY, maxX, maxY)
+	}
+	if all == nil {
+		return assigned
+	}
+	// grid of about four cells per ring, each listing the rings whose box overlaps it
+	minX, minY, maxX, maxY := bbox(all)
+	grid := newCellGrid(minX, minY, maxX, maxY, math.Sqrt(float64(4 * len(rings))))
+	cells := make([][]int, grid.nx * grid.ny)
+	for r, box := range(boxes) {
+		if edges[r] == nil {
+			continue
+		}
+		ax, ay := grid.cell(box[0], box[1])
+		bx, by := grid.cell(box[2], box[3])
+		for cy := ay; cy <= by; cy++ {
+			for cx := ax; cx <= bx; cx++ {
+				cells[cy * grid.nx + cx] = append(cells[cy * grid.nx + cx], r)
+			}
+		}
+	}
+	for i := range(assigned) {
+		x, y := points.Take(i)
+		if x < minX || x > maxX || y < minY || y > maxY {
+			continue
+		}
+		cx, cy := grid.cell(x, y)
+		for _, r := range(cells[cy * grid.nx + cx]) {
+			box := boxes[r]
+			if x >= box[0] && x <= box[2] && y >= box[1] && y <= box[3] && edges[r].contains(x, y) {
+				assigned[i] = r
+				break
+			}
+		}
+	}
+	return assigned
+}
@@ -0,0 +1,47 @@
+package ConcaveHull
+
+import (
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestAssignPoints (t *testing.T) {
+	rings := []FlatPoints{
+		{0, 0, 4, 0, 4, 4, 2, 1, 0, 4, 0, 0},
+		{10, 10, 12, 10, 12, 12, 10, 12},
+		{1, 1},
+		// overlaps the first ring in its notch and below it
+		{1.5, -1, 2.5, -1, 2.5, 3, 1.5, 3},
+	}
+	points := FlatPoints{1, 0.5, 2, 2, 11, 11, 5, 5, -1, -1, 2, -0.5, 2, 3.5}
+	assert.Equal(t, []int{0, 3, 1, -1, -1, 3, -1}, AssignPoints(rings, points))
+	assert.Equal(t, []int{-1, -1}, AssignPoints(nil, FlatPoints{0, 0, 1, 1}))
+}
+
+func TestAssignPoints_matchesRingContains (t *testing.T) {
+	r := rand.New(rand.NewSource(12))
+	var rings []FlatPoints
+	for k := 0; k < 5; k++ {
+		points := FlatPoints(hulltest.Random(r, 500))
+		for i := range(points) {
+			points[i] = points[i] * 2 + float64(k)
+		}
+		rings = append(rings, ComputeWithOptions(points, &Options{Seglength: 0.05}))
+	}
+	points := make(FlatPoints, 0, 20000)
+	for i := 0; i < 10000; i++ {
+		points = append(points, r.Float64() * 8 - 1, r.Float64() * 8 - 1)
+	}
+	for i, assigned := range(AssignPoints(rings, points)) {
+		expected := -1
+		for k, ring := range(rings) {
+			if ringContains(ring, points[2 * i], points[2 * i + 1]) {
+				expected = k
+				break
+			}
+		}
+		assert.Equal(t, expected, assigned)
+	}
+}
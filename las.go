@@ -0,0 +1,119 @@
+package ConcaveHull
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+var ErrInvalidLAS = errors.New("ConcaveHull: invalid LAS")
+
+// Returned for LAZ files when LASOptions.Decompress is not set
+var ErrCompressedLAS = errors.New("ConcaveHull: LAZ files need LASOptions.Decompress")
+
+// Bytes of the header of LAS 1.0 to 1.2, which later versions extend
+const lasHeaderSize = 227
+
+// Filtering and thinning of the points read by ReadLAS and ScanLAS
+type LASOptions struct {
+	// Classifications kept, for example 2 for ground, all if empty
+	Classes []uint8
+	DropWithheld bool // skip points flagged as withheld
+	// Keep one point out of Thin of those passing the filters, all if 0 or 1
+	Thin int
+	// Called with the whole LAZ file to get the equivalent LAS stream, for example from laszip -stdin -olas -stdout or a LASzip
+	// binding, so this package does not depend on one
+	Decompress func (r io.Reader) (io.Reader, error)
+}
+
+// Read the X and Y of the points of a LAS file, see ScanLAS
+func ReadLAS (r io.Reader, o LASOptions) (FlatPoints, error) {
+	var points FlatPoints
+	err := ScanLAS(r, o, func (x, y float64) error {
+		points = append(points, x, y)
+		return nil
+	})
+	return points, err
+}
+
+// Call f with the scaled X and Y of each point of a LAS 1.0 to 1.4 file as it is read, so the records are never held in memory
+// as a whole. Z is ignored. Errors returned by f stop the scan
+func ScanLAS (r io.Reader, o LASOptions, f func (x, y float64) error) error {
+	buffered := bufio.NewReaderSize(r, 1 << 16)
+	header := make([]byte, lasHeaderSize)
+	if _, err := io.ReadFull(buffered, header); err != nil || string(header[:4]) != "LASF" {
+		return ErrInvalidLAS
+	}
+	format := header[104]
+	if format & 0xC0 != 0 {
+		if o.Decompress == nil {
+			return ErrCompressedLAS
+		}
+		decompressed, err := o.Decompress(io.MultiReader(bytes.NewReader(header), buffered))
+		if err != nil {
+			return err
+		}
+		o.Decompress = nil
+		return ScanLAS(decompressed, o, f)
+	}
+	headerSize := uint32(binary.LittleEndian.Uint16(header[94:]))
+	offset := binary.LittleEndian.Uint32(header[96:])
+	recordLength := int(binary.LittleEndian.Uint16(header[105:]))
+	count := uint64(binary.LittleEndian.Uint32(header[107:]))
+	read := uint32(lasHeaderSize)
+	if header[24] == 1 && header[25] >= 4 && headerSize >= 255 {
+		extended := make([]byte, 255 - lasHeaderSize)
+		if _, err := io.ReadFull(buffered, extended); err != nil {
+			return ErrInvalidLAS
+		}
+		read = 255
+		if count == 0 {
+			count = binary.LittleEndian.Uint64(extended[247 - lasHeaderSize:])
+		}
+	}
+	minimum := 20
+	if format >= 6 {
+		minimum = 30
+	}
+	if format > 10 || recordLength < minimum || offset < read {
+		return fmt.Errorf("%w: point format %d with records of %d bytes", ErrInvalidLAS, format, recordLength)
+	}
+	// variable length records are skipped
+	if _, err := io.CopyN(io.Discard, buffered, int64(offset - read)); err != nil {
+		return ErrInvalidLAS
+	}
+	scaleX, scaleY := math.Float64frombits(binary.LittleEndian.Uint64(header[131:])), math.Float64frombits(binary.LittleEndian.Uint64(header[139:]))
+	offsetX, offsetY := math.Float64frombits(binary.LittleEndian.Uint64(header[155:])), math.Float64frombits(binary.LittleEndian.Uint64(header[163:]))
+	var classes [256]bool
+	for _, class := range(o.Classes) {
+		classes[class] = true
+	}
+	record := make([]byte, recordLength)
+	kept := 0
+	for i := uint64(0); i < count; i++ {
+		if _, err := io.ReadFull(buffered, record); err != nil {
+			return fmt.Errorf("%w: point %d of %d: %v", ErrInvalidLAS, i, count, err)
+		}
+		class, withheld := record[15] & 0x1F, record[15] & 0x80 != 0
+		if format >= 6 {
+			class, withheld = record[16], record[15] & 0x04 != 0
+		}
+		if len(o.Classes) > 0 && !classes[class] || o.DropWithheld && withheld {
+			continue
+		}
+		kept++
+		if o.Thin > 1 && (kept - 1) % o.Thin != 0 {
+			continue
+		}
+		x := float64(int32(binary.LittleEndian.Uint32(record))) * scaleX + offsetX
+		y := float64(int32(binary.LittleEndian.Uint32(record[4:]))) * scaleY + offsetY
+		if err := f(x, y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
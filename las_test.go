@@ -0,0 +1,107 @@
+package ConcaveHull
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+type testLASPoint struct {
+	x, y int32
+	class uint8
+	withheld bool
+}
+
+// LAS file of the given version and point format, with scale 0.01 and offset 1000, 2000 and a variable length record
+func testLAS (minor, format uint8, points []testLASPoint) []byte {
+	headerSize, recordLength := 227, 20
+	if minor >= 4 {
+		headerSize = 375
+	}
+	if format >= 6 {
+		recordLength = 30
+	}
+	vlr := make([]byte, 60)
+	header := make([]byte, headerSize)
+	copy(header, "LASF")
+	header[24], header[25] = 1, minor
+	binary.LittleEndian.PutUint16(header[94:], uint16(headerSize))
+	binary.LittleEndian.PutUint32(header[96:], uint32(headerSize + len(vlr)))
+	binary.LittleEndian.PutUint32(header[100:], 1)
+	header[104] = format
+	binary.LittleEndian.PutUint16(header[105:], uint16(recordLength))
+	if minor >= 4 {
+		binary.LittleEndian.PutUint64(header[247:], uint64(len(points)))
+	} else {
+		binary.LittleEndian.PutUint32(header[107:], uint32(len(points)))
+	}
+	for i, v := range([]float64{0.01, 0.01, 0.01, 1000, 2000, 0}) {
+		binary.LittleEndian.PutUint64(header[131 + 8 * i:], math.Float64bits(v))
+	}
+	file := append(header, vlr...)
+	for _, p := range(points) {
+		record := make([]byte, recordLength)
+		binary.LittleEndian.PutUint32(record, uint32(p.x))
+		binary.LittleEndian.PutUint32(record[4:], uint32(p.y))
+		if format >= 6 {
+			record[16] = p.class
+			if p.withheld {
+				record[15] = 0x04
+			}
+		} else {
+			record[15] = p.class
+			if p.withheld {
+				record[15] |= 0x80
+			}
+		}
+		file = append(file, record...)
+	}
+	return file
+}
+
+func TestReadLAS (t *testing.T) {
+	points := []testLASPoint{{100, 200, 2, false}, {-100, 300, 1, false}, {500, -500, 2, true}, {700, 800, 2, false}, {900, 900, 2, false}}
+	for _, file := range([][]byte{testLAS(2, 1, points), testLAS(4, 6, points)}) {
+		read, err := ReadLAS(bytes.NewReader(file), LASOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, FlatPoints{1001, 2002, 999, 2003, 1005, 1995, 1007, 2008, 1009, 2009}, read)
+
+		read, err = ReadLAS(bytes.NewReader(file), LASOptions{Classes: []uint8{2}, DropWithheld: true})
+		assert.Nil(t, err)
+		assert.Equal(t, FlatPoints{1001, 2002, 1007, 2008, 1009, 2009}, read)
+
+		read, err = ReadLAS(bytes.NewReader(file), LASOptions{Classes: []uint8{2}, DropWithheld: true, Thin: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, FlatPoints{1001, 2002, 1009, 2009}, read)
+	}
+}
+
+func TestReadLAS_compressed (t *testing.T) {
+	file := testLAS(2, 1, []testLASPoint{{100, 200, 2, false}})
+	compressed := append([]byte{}, file...)
+	compressed[104] |= 0x80
+	_, err := ReadLAS(bytes.NewReader(compressed), LASOptions{})
+	assert.Equal(t, ErrCompressedLAS, err)
+
+	read, err := ReadLAS(bytes.NewReader(compressed), LASOptions{Decompress: func (r io.Reader) (io.Reader, error) {
+		data, err := io.ReadAll(r)
+		assert.Equal(t, compressed, data)
+		return bytes.NewReader(file), err
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, FlatPoints{1001, 2002}, read)
+}
+
+func TestReadLAS_invalid (t *testing.T) {
+	file := testLAS(2, 0, []testLASPoint{{1, 2, 0, false}, {3, 4, 0, false}})
+	for _, n := range([]int{0, 100, 250, len(file) - 1}) {
+		_, err := ReadLAS(bytes.NewReader(file[:n]), LASOptions{})
+		assert.True(t, errors.Is(err, ErrInvalidLAS))
+	}
+	stop := errors.New("stop")
+	assert.Equal(t, stop, ScanLAS(bytes.NewReader(file), LASOptions{}, func (x, y float64) error { return stop }))
+}
@@ -0,0 +1,14 @@
+package ConcaveHull
+
+import "sort"
+
+// Hulls of the points simplified with each of the tolerances, sharing a single densification pass. Tiled map servers can pass
+// one tolerance per zoom level, typically the size of a pixel at that zoom, instead of recomputing the hull for every level.
+// The result is aligned with tolerances. Options.Seglength still drives the densification, points are sorted in place
+func ComputeLevels (points FlatPoints, tolerances []float64, o *Options) []FlatPoints {
+	o = o.snapshot()
+	points = clipInput(points, o)
+	sort.Sort(LexSorter(points))
+	_, levelHulls := computeFromSortedWithLevels(nil, points, o, tolerances)
+	return levelHulls
+}
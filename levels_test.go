@@ -0,0 +1,22 @@
+package ConcaveHull
+
+import (
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestComputeLevels (t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	points := hulltest.Ring(r, 2000, 0.3, 0.5)
+	levels := ComputeLevels(FlatPoints(append([]float64{}, points...)), []float64{0.001, 0.01, 0.05}, &Options{Seglength: 0.001})
+	assert.Len(t, levels, 3)
+	// same as computing the hull with that seglength
+	assert.Equal(t, ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.001}), levels[0])
+	assert.True(t, levels[0].Len() > levels[1].Len())
+	assert.True(t, levels[1].Len() > levels[2].Len())
+	for _, level := range(levels) {
+		hulltest.AssertValid(t, points, level)
+	}
+}
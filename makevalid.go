@@ -0,0 +1,89 @@
+package ConcaveHull
+
+import "github.com/USACE/concavehull/geomutil"
+
+// Valid polygons covering the same area as the ring under the even odd rule, for consumers that reject invalid geometries,
+// such as SQL Server geography. The ring is split wherever it meets itself and the pieces are chained again, so a ring
+// crossing itself becomes several polygons and a ring touching itself gets a hole or separate parts. Repeated points and
+// spikes are removed and the orientation is fixed. The result is a list of closed rings, exterior rings are counter
+// clockwise and holes clockwise, a ring without area gives none
+func MakeValid (ring FlatPoints) []FlatPoints {
+	ring = openRing(ring)
+	n := ring.Len()
+	if n < 3 {
+		return nil
+	}
+	splits := make([][][2]float64, n)
+	for i := 0; i < n; i++ {
+		ax1, ay1 := ring.Take(i)
+		ax2, ay2 := ring.Take((i + 1) % n)
+		for j := i + 1; j < n; j++ {
+			bx1, by1 := ring.Take(j)
+			bx2, by2 := ring.Take((j + 1) % n)
+			geomutil.SplitEdgePair(ax1, ay1, ax2, ay2, bx1, by1, bx2, by2, &splits[i], &splits[j])
+		}
+	}
+	// pieces covered an even number of times, like spikes, separate faces of the same parity and are dropped
+	count := make(map[[2][2]float64]int)
+	var order [][2][2]float64
+	for _, piece := range(geomutil.RingPieces(ring, splits)) {
+		if piece[1][0] < piece[0][0] || piece[1][0] == piece[0][0] && piece[1][1] < piece[0][1] {
+			piece[0], piece[1] = piece[1], piece[0]
+		}
+		if count[piece] == 0 {
+			order = append(order, piece)
+		}
+		count[piece]++
+	}
+	var boundary [][2][2]float64
+	for _, piece := range(order) {
+		if count[piece] % 2 == 1 {
+			boundary = append(boundary, piece)
+		}
+	}
+	var vertices [][2]float64
+	vertexIds := make(map[[2]float64]int)
+	vertexId := func (p [2]float64) int {
+		if id, ok := vertexIds[p]; ok {
+			return id
+		}
+		vertexIds[p] = len(vertices)
+		vertices = append(vertices, p)
+		return len(vertices) - 1
+	}
+	edges := make([]geomutil.Edge, len(boundary))
+	for i, piece := range(boundary) {
+		from, to := vertexId(piece[0]), vertexId(piece[1])
+		// the inside to the left of every edge
+		if !leftInside(boundary, i) {
+			from, to = to, from
+		}
+		edges[i] = geomutil.Edge{From: from, To: to}
+	}
+	return chainEdges(vertices, edges)
+}
+
+// Whether the face to the left of piece i is inside, that is, a ray cast from the middle of the piece to its left crosses
+// an odd number of the other pieces
+func leftInside (pieces [][2][2]float64, i int) bool {
+	mx, my := (pieces[i][0][0] + pieces[i][1][0]) / 2, (pieces[i][0][1] + pieces[i][1][1]) / 2
+	dx, dy := pieces[i][0][1] - pieces[i][1][1], pieces[i][1][0] - pieces[i][0][0]
+	inside := false
+	for j, piece := range(pieces) {
+		if j == i {
+			continue
+		}
+		// sides of the line of the ray, an end point on the line counting as the positive side
+		sa := dx * (piece[0][1] - my) - dy * (piece[0][0] - mx)
+		sb := dx * (piece[1][1] - my) - dy * (piece[1][0] - mx)
+		if (sa >= 0) == (sb >= 0) {
+			continue
+		}
+		t := sa / (sa - sb)
+		px, py := piece[0][0] + (piece[1][0] - piece[0][0]) * t, piece[0][1] + (piece[1][1] - piece[0][1]) * t
+		if (px - mx) * dx + (py - my) * dy > 0 {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,32 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeValid (t *testing.T) {
+	// valid rings are only reoriented and stripped of repeated points
+	assert.Equal(t, []FlatPoints{{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}}, MakeValid(FlatPoints{0, 0, 0, 2, 2, 2, 2, 2, 2, 0, 0, 0}))
+
+	// bow tie becomes two triangles
+	rings := MakeValid(FlatPoints{0, 0, 2, 2, 2, 0, 0, 2, 0, 0})
+	assert.Len(t, rings, 2)
+	for _, ring := range(rings) {
+		assert.Equal(t, 1., SignedArea(ring))
+		assert.Len(t, ValidityReport(ring), 0)
+	}
+
+	// spike out of a square is dropped
+	assert.Equal(t, []FlatPoints{{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}}, MakeValid(FlatPoints{0, 0, 2, 0, 2, 1, 4, 1, 2, 1, 2, 2, 0, 2, 0, 0}))
+
+	// a square drawn twice, the second time inside, has a hole under the even odd rule
+	rings = MakeValid(FlatPoints{0, 0, 4, 0, 4, 4, 0, 4, 0, 0, 1, 1, 3, 1, 3, 3, 1, 3, 1, 1, 0, 0})
+	area := 0.
+	for _, ring := range(rings) {
+		area += SignedArea(ring)
+	}
+	assert.Equal(t, 12., area)
+
+	assert.Len(t, MakeValid(FlatPoints{0, 0, 1, 1, 2, 2, 0, 0}), 0)
+}
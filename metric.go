@@ -0,0 +1,86 @@
+package ConcaveHull
+
+import "math"
+
+// Distance between two points, see Options.Metric
+type Metric interface {
+	Distance (x1, y1, x2, y2 float64) float64
+	// Factor k such that Distance is at least k times the euclidean distance between the coordinates. It bounds the
+	// nearest neighbour search, a smaller factor is always correct but slower
+	EuclideanFactor () float64
+}
+
+type EuclideanMetric struct{}
+
+func (EuclideanMetric) Distance (x1, y1, x2, y2 float64) float64 {
+	return math.Hypot(x2 - x1, y2 - y1)
+}
+
+func (EuclideanMetric) EuclideanFactor () float64 {
+	return 1
+}
+
+// Sum of the absolute differences of the coordinates
+type ManhattanMetric struct{}
+
+func (ManhattanMetric) Distance (x1, y1, x2, y2 float64) float64 {
+	return math.Abs(x2 - x1) + math.Abs(y2 - y1)
+}
+
+func (ManhattanMetric) EuclideanFactor () float64 {
+	return 1
+}
+
+// Distance sqrt(vᵀ P v) for a symmetric positive definite matrix P, usually the inverse of the covariance of the points.
+// Use NewMahalanobisMetric to build it from the covariance
+type MahalanobisMetric struct {
+	Precision [2][2]float64
+}
+
+// Metric from the covariance matrix of the data
+func NewMahalanobisMetric (varianceX, varianceY, covarianceXY float64) MahalanobisMetric {
+	det := varianceX * varianceY - covarianceXY * covarianceXY
+	return MahalanobisMetric{Precision: [2][2]float64{
+		{varianceY / det, -covarianceXY / det},
+		{-covarianceXY / det, varianceX / det},
+	}}
+}
+
+func (m MahalanobisMetric) Distance (x1, y1, x2, y2 float64) float64 {
+	dx, dy := x2 - x1, y2 - y1
+	p := m.Precision
+	return math.Sqrt(dx * (p[0][0] * dx + p[0][1] * dy) + dy * (p[1][0] * dx + p[1][1] * dy))
+}
+
+// Square root of the smallest eigenvalue of the precision matrix
+func (m MahalanobisMetric) EuclideanFactor () float64 {
+	p := m.Precision
+	mean := (p[0][0] + p[1][1]) / 2
+	radius := math.Sqrt((p[0][0] - p[1][1]) * (p[0][0] - p[1][1]) / 4 + p[0][1] * p[1][0])
+	return math.Sqrt(math.Max(mean - radius, 0))
+}
+
+const earthRadius = 6371008.8
+
+// Great circle distance in meters between longitude, latitude points in degrees
+type HaversineMetric struct {
+	// Largest absolute latitude of the data, used to bound the search since degrees of longitude shrink towards the poles. Defaults to 85
+	MaxLatitude float64
+}
+
+func (HaversineMetric) Distance (x1, y1, x2, y2 float64) float64 {
+	lat1, lat2 := y1 * math.Pi / 180, y2 * math.Pi / 180
+	sinLat := math.Sin((lat2 - lat1) / 2)
+	sinLon := math.Sin((x2 - x1) * math.Pi / 360)
+	a := sinLat * sinLat + math.Cos(lat1) * math.Cos(lat2) * sinLon * sinLon
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Meters per degree at the largest latitude, reduced by 2/π to cover the difference between arcs and chords
+func (m HaversineMetric) EuclideanFactor () float64 {
+	maxLatitude := m.MaxLatitude
+	if maxLatitude == 0 {
+		maxLatitude = 85
+	}
+	return earthRadius * math.Pi / 180 * math.Cos(maxLatitude * math.Pi / 180) * 2 / math.Pi
+}
@@ -0,0 +1,68 @@
+package ConcaveHull
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestGridIndex_nearestWithin (t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	points := FlatPoints(hulltest.Clustered(r, 500, 4, 0.05))
+	g := newGridIndex(points)
+	for _, metric := range([]Metric{EuclideanMetric{}, ManhattanMetric{}, NewMahalanobisMetric(4, 1, 0.5)}) {
+		for q := 0; q < 100; q++ {
+			x, y := r.Float64() * 2 - 0.5, r.Float64() * 2 - 0.5
+			best, bx, by := math.Inf(1), 0., 0.
+			for i := 0; i < points.Len(); i++ {
+				px, py := points.Take(i)
+				if d := metric.Distance(x, y, px, py); d < best {
+					best, bx, by = d, px, py
+				}
+			}
+			nx, ny, found := g.nearestWithin(x, y, math.Inf(1), metric)
+			assert.True(t, found)
+			assert.Equal(t, []float64{bx, by}, []float64{nx, ny})
+			_, _, found = g.nearestWithin(x, y, best / 2, metric)
+			assert.False(t, found)
+		}
+	}
+}
+
+func TestComputeWithOptions_metric (t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	points := hulltest.Ring(r, 1000, 0.3, 0.5)
+	euclidean := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.01})
+	withMetric := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.01, Metric: EuclideanMetric{}})
+	assert.Equal(t, euclidean, withMetric)
+	manhattan := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.01, Metric: ManhattanMetric{}})
+	hulltest.AssertValid(t, points, manhattan)
+}
+
+func TestHaversineMetric (t *testing.T) {
+	// one degree of latitude
+	assert.InDelta(t, 111195, HaversineMetric{}.Distance(0, 0, 0, 1), 1)
+	assert.InDelta(t, 111195 / 2, HaversineMetric{}.Distance(0, 60, 1, 60), 100)
+}
+
+func TestComputeWithOptions_haversine (t *testing.T) {
+	// 0.2 by 0.2 degrees, about 16 by 22 km at latitude 45, with a notch 0.16 wide and 0.04 deep from the top
+	r := rand.New(rand.NewSource(7))
+	var points FlatPoints
+	for points.Len() < 3000 {
+		x, y := r.Float64() * 0.2, r.Float64() * 0.2
+		if x > 0.02 && x < 0.18 && y > 0.16 {
+			continue
+		}
+		points = append(points, 10 + x, 45 + y)
+	}
+	// seglength and simplification tolerance in meters
+	hull := ComputeWithOptions(append(FlatPoints{}, points...), &Options{Seglength: 1000, Metric: HaversineMetric{}})
+	assert.True(t, hull.Len() >= 7)
+	hulltest.AssertValid(t, points, hull)
+	assert.False(t, ringContains(hull, 10.1, 45.19))
+	assert.True(t, ringContains(hull, 10.01, 45.19))
+	assert.InDelta(t, 0.2 * 0.2 - 0.16 * 0.04, SignedArea(hull), 0.002)
+}
@@ -0,0 +1,168 @@
+// Package mobile wraps ConcaveHull in the types gomobile can bind, for field data collection apps computing coverage hulls
+// on iOS and Android devices:
+//
+//	gomobile bind -target=android github.com/USACE/concavehull/mobile
+//
+// gomobile doesn't bind slices other than []byte, so points are either added one at a time to a Points or passed in bulk as
+// little endian float64 x and y, the layout of a ByteBuffer of doubles on Android or of a Data of Doubles on iOS. Hulls are
+// read back vertex by vertex, in the same binary layout, or as GeoJSON and WKT
+package mobile
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+	"github.com/USACE/concavehull"
+)
+
+// Settings of a computation, a subset of ConcaveHull.Options. The zero value of each field is the default
+type Options struct {
+	// Length of the bisection steps along the convex hull, in the units of the coordinates, or in meters with LonLat
+	Seglength float64
+	// Seglength as a fraction of the diagonal of the bounding box of the points, used if Seglength is 0
+	SeglengthRelative float64
+	// The points are longitude and latitude in degrees, the hull is computed in Web Mercator and returned in degrees
+	LonLat bool
+	// Edges not refined within the budget are left straight and the hull is Partial, 0 means no limit
+	TimeBudgetMillis int64
+	// The hull is simplified further until it has at most that many vertices, 0 means no limit
+	MaxVertices int
+	// Arc length of the moving average smoothing the hull, 0 means no smoothing
+	SmoothWindow float64
+	// Remove repeated vertices and zero area spikes
+	RemoveSpikes bool
+}
+
+func NewOptions () *Options {
+	return &Options{}
+}
+
+func (o *Options) options () *ConcaveHull.Options {
+	if o == nil {
+		return nil
+	}
+	options := &ConcaveHull.Options{
+		Seglength: o.Seglength,
+		SeglengthRelative: o.SeglengthRelative,
+		TimeBudget: time.Duration(o.TimeBudgetMillis) * time.Millisecond,
+		MaxVertices: o.MaxVertices,
+		SmoothWindow: o.SmoothWindow,
+		RemoveSpikes: o.RemoveSpikes,
+	}
+	if o.LonLat {
+		options.Projection = ConcaveHull.WebMercator{}
+	}
+	return options
+}
+
+// Points added one at a time, for example as positions are recorded. Not safe for concurrent use
+type Points struct {
+	points ConcaveHull.FlatPoints
+}
+
+func NewPoints () *Points {
+	return &Points{}
+}
+
+func (p *Points) Add (x, y float64) {
+	p.points = append(p.points, x, y)
+}
+
+// Append points encoded as little endian float64 x and y
+func (p *Points) AddBinary (data []byte) error {
+	points, err := decodePoints(data)
+	if err != nil {
+		return err
+	}
+	p.points = append(p.points, points...)
+	return nil
+}
+
+func (p *Points) Len () int {
+	return p.points.Len()
+}
+
+// Remove all the points, keeping their memory for the next ones
+func (p *Points) Clear () {
+	p.points = p.points[:0]
+}
+
+// Closed counter clockwise ring of a concave hull
+type Hull struct {
+	hull ConcaveHull.Hull
+}
+
+// Number of vertices, the first being repeated at the end
+func (h *Hull) Len () int {
+	return h.hull.Points.Len()
+}
+
+func (h *Hull) X (i int) float64 {
+	return h.hull.Points[2 * i]
+}
+
+func (h *Hull) Y (i int) float64 {
+	return h.hull.Points[2 * i + 1]
+}
+
+// Some edges were left straight because the time budget expired
+func (h *Hull) Partial () bool {
+	return h.hull.Partial
+}
+
+// Vertices encoded as little endian float64 x and y
+func (h *Hull) Binary () []byte {
+	data := make([]byte, 0, 8 * len(h.hull.Points))
+	for _, v := range(h.hull.Points) {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+	}
+	return data
+}
+
+// GeoJSON Polygon geometry
+func (h *Hull) GeoJSON () (string, error) {
+	encoded, err := h.hull.GeoJSON()
+	return string(encoded), err
+}
+
+func (h *Hull) WKT () string {
+	return h.hull.WKT()
+}
+
+// Hull of the points, which are not modified. o may be nil for the defaults
+func Compute (points *Points, o *Options) (*Hull, error) {
+	if points == nil {
+		return nil, fmt.Errorf("%w: no points", ConcaveHull.ErrMalformedPoints)
+	}
+	return compute(append(ConcaveHull.FlatPoints{}, points.points...), o)
+}
+
+// Hull of points encoded as little endian float64 x and y. o may be nil for the defaults
+func ComputeBinary (data []byte, o *Options) (*Hull, error) {
+	points, err := decodePoints(data)
+	if err != nil {
+		return nil, err
+	}
+	return compute(points, o)
+}
+
+func compute (points ConcaveHull.FlatPoints, o *Options) (*Hull, error) {
+	hull, err := ConcaveHull.ComputeContext(context.Background(), points, o.options())
+	if err != nil {
+		return nil, err
+	}
+	return &Hull{hull: hull}, nil
+}
+
+func decodePoints (data []byte) (ConcaveHull.FlatPoints, error) {
+	if len(data) % 16 != 0 {
+		return nil, fmt.Errorf("%w: %d bytes is not a whole number of points", ConcaveHull.ErrMalformedPoints, len(data))
+	}
+	points := make(ConcaveHull.FlatPoints, len(data) / 8)
+	for i := range(points) {
+		points[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8 * i:]))
+	}
+	return points, nil
+}
@@ -0,0 +1,64 @@
+package mobile
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull"
+)
+
+func TestCompute (t *testing.T) {
+	r := rand.New(rand.NewSource(37))
+	points := NewPoints()
+	var flat ConcaveHull.FlatPoints
+	for i := 0; i < 300; i++ {
+		x, y := r.Float64(), r.Float64()
+		points.Add(x, y)
+		flat = append(flat, x, y)
+	}
+	o := NewOptions()
+	o.Seglength = 0.05
+	hull, err := Compute(points, o)
+	assert.NoError(t, err)
+	expected := ConcaveHull.ComputeWithOptions(append(ConcaveHull.FlatPoints{}, flat...), &ConcaveHull.Options{Seglength: 0.05})
+	assert.Equal(t, expected.Len(), hull.Len())
+	for i := 0; i < hull.Len(); i++ {
+		assert.Equal(t, expected[2 * i], hull.X(i))
+		assert.Equal(t, expected[2 * i + 1], hull.Y(i))
+	}
+	assert.False(t, hull.Partial())
+	// the points are left in the order they were added
+	assert.Equal(t, flat, points.points)
+
+	// same points in bulk
+	bulk, err := ComputeBinary((&Hull{hull: ConcaveHull.Hull{Points: flat}}).Binary(), o)
+	assert.NoError(t, err)
+	assert.Equal(t, hull.Binary(), bulk.Binary())
+	geojson, err := bulk.GeoJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, geojson, `"type":"Polygon"`)
+	assert.Contains(t, bulk.WKT(), "POLYGON((")
+
+	_, err = ComputeBinary(make([]byte, 12), nil)
+	assert.True(t, errors.Is(err, ConcaveHull.ErrMalformedPoints))
+	points.Clear()
+	assert.Equal(t, 0, points.Len())
+	assert.NoError(t, points.AddBinary(hull.Binary()))
+	assert.Equal(t, hull.Len(), points.Len())
+}
+
+func TestCompute_lonLat (t *testing.T) {
+	points := NewPoints()
+	for _, p := range([][2]float64{{-3.70, 40.41}, {-3.69, 40.41}, {-3.69, 40.42}, {-3.70, 40.42}, {-3.695, 40.415}}) {
+		points.Add(p[0], p[1])
+	}
+	o := &Options{Seglength: 200, LonLat: true}
+	hull, err := Compute(points, o)
+	assert.NoError(t, err)
+	assert.True(t, hull.Len() >= 5)
+	for i := 0; i < hull.Len(); i++ {
+		assert.InDelta(t, -3.695, hull.X(i), 0.0051)
+		assert.InDelta(t, 40.415, hull.Y(i), 0.0051)
+	}
+}
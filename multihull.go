@@ -0,0 +1,230 @@
+package ConcaveHull
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Polygon of a MultiHull: a closed exterior ring and closed holes
+type HullPolygon struct {
+	Exterior FlatPoints
+	Holes []FlatPoints
+}
+
+// Several polygons, each with optional holes, for results that don't fit in a single ring: clusters, hulls with holes and
+// hulls split at the antimeridian. Encoders fix the orientation of the rings, exteriors counter clockwise and holes clockwise
+type MultiHull []HullPolygon
+
+// Multi hull of the clusters that have an area, the point and segment hulls of Options.KeepSmallClusters are left out
+func NewClusterMultiHull (hulls []ClusterHull) MultiHull {
+	var m MultiHull
+	for _, hull := range(hulls) {
+		if openRing(hull.Points).Len() >= 3 {
+			m = append(m, HullPolygon{Exterior: closeRing(hull.Points)})
+		}
+	}
+	return m
+}
+
+// Same as STConcaveHull as a multi hull of one polygon
+func STConcaveHullMulti (points FlatPoints, targetPercent float64, allowHoles bool) MultiHull {
+	exterior, holes := STConcaveHull(points, targetPercent, allowHoles)
+	if openRing(exterior).Len() < 3 {
+		return nil
+	}
+	return MultiHull{{Exterior: closeRing(exterior), Holes: holes}}
+}
+
+// Multi hull of rings such as those of Union, Intersection, Difference or MakeValid, counter clockwise exteriors and
+// clockwise holes. Each hole goes to the smallest exterior that contains it
+func MultiHullFromRings (rings []FlatPoints) MultiHull {
+	var m MultiHull
+	var holes []FlatPoints
+	for _, ring := range(rings) {
+		if IsCCW(ring) {
+			m = append(m, HullPolygon{Exterior: closeRing(ring)})
+		} else if SignedArea(ring) < 0 {
+			holes = append(holes, closeRing(ring))
+		}
+	}
+	// smallest exteriors first
+	sort.SliceStable(m, func (i, j int) bool { return SignedArea(m[i].Exterior) < SignedArea(m[j].Exterior) })
+	for _, hole := range(holes) {
+		x, y := hole.Take(0)
+		for i := range(m) {
+			if ringContains(m[i].Exterior, x, y) {
+				m[i].Holes = append(m[i].Holes, hole)
+				break
+			}
+		}
+	}
+	return m
+}
+
+// Split a ring of longitude, latitude coordinates whose longitudes were unwrapped past ±180, for example computed from
+// longitudes shifted to [0, 360) so that the hull of points around the antimeridian is one piece. The parts beyond ±180 are
+// cut off at the antimeridian and shifted back by 360 degrees
+func SplitAntimeridian (ring FlatPoints) MultiHull {
+	minX, minY, maxX, maxY := bbox(ring)
+	if minX >= -180 && maxX <= 180 {
+		return MultiHullFromRings([]FlatPoints{counterClockwise(ring)})
+	}
+	var rings []FlatPoints
+	for shift := math.Floor((minX + 180) / 360) * 360; shift - 180 < maxX; shift += 360 {
+		box := FlatPoints{shift - 180, minY - 1, shift + 180, minY - 1, shift + 180, maxY + 1, shift - 180, maxY + 1}
+		for _, part := range(Intersection(ring, box)) {
+			shifted := make(FlatPoints, len(part))
+			for i := 0; i < part.Len(); i++ {
+				shifted[2 * i], shifted[2 * i + 1] = part[2 * i] - shift, part[2 * i + 1]
+			}
+			rings = append(rings, shifted)
+		}
+	}
+	return MultiHullFromRings(rings)
+}
+
+// Rings of the polygon, exterior first, closed and oriented as RFC 7946 and OGC ask
+func (p HullPolygon) rings () []FlatPoints {
+	rings := make([]FlatPoints, 0, len(p.Holes) + 1)
+	for i, ring := range(append([]FlatPoints{p.Exterior}, p.Holes...)) {
+		ring = closeRing(ring)
+		if (SignedArea(ring) < 0) == (i == 0) {
+			ring = reverseRing(ring)
+		}
+		rings = append(rings, ring)
+	}
+	return rings
+}
+
+// GeoJSON MultiPolygon geometry object
+func (m MultiHull) GeoJSON () ([]byte, error) {
+	coordinates := make([][][][2]float64, len(m))
+	for i, p := range(m) {
+		coordinates[i] = geoJSONRings(p.rings())
+	}
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		Coordinates [][][][2]float64 `json:"coordinates"`
+	}{"MultiPolygon", coordinates})
+}
+
+// Well known text MULTIPOLYGON, with the shortest decimal representation of each coordinate that parses back to it
+func (m MultiHull) WKT () string {
+	if len(m) == 0 {
+		return "MULTIPOLYGON EMPTY"
+	}
+	var b strings.Builder
+	b.WriteString("MULTIPOLYGON(")
+	for i, p := range(m) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		writeWKTRings(&b, p.rings())
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// Little endian well known binary MultiPolygon
+func (m MultiHull) WKB () []byte {
+	data := appendWKBHeader(nil, wkbMultiPolygon, len(m))
+	for _, p := range(m) {
+		data = appendWKBPolygon(data, p.rings())
+	}
+	return data
+}
+
+// Rings of the hull, closed and counter clockwise, none if it has no area
+func (h Hull) rings () []FlatPoints {
+	if openRing(h.Points).Len() < 3 {
+		return nil
+	}
+	return HullPolygon{Exterior: h.Points}.rings()
+}
+
+// GeoJSON Polygon geometry object, without coordinates if the hull has no area
+func (h Hull) GeoJSON () ([]byte, error) {
+	return json.Marshal(geoJSONGeometry{Type: "Polygon", Coordinates: geoJSONRings(h.rings())})
+}
+
+// Well known text POLYGON, POLYGON EMPTY if the hull has no area
+func (h Hull) WKT () string {
+	rings := h.rings()
+	if len(rings) == 0 {
+		return "POLYGON EMPTY"
+	}
+	var b strings.Builder
+	b.WriteString("POLYGON")
+	writeWKTRings(&b, rings)
+	return b.String()
+}
+
+// Little endian well known binary Polygon, without rings if the hull has no area
+func (h Hull) WKB () []byte {
+	return appendWKBPolygon(nil, h.rings())
+}
+
+func geoJSONRings (rings []FlatPoints) [][][2]float64 {
+	coordinates := make([][][2]float64, 0, len(rings))
+	for _, ring := range(rings) {
+		points := make([][2]float64, ring.Len())
+		for j := range(points) {
+			points[j][0], points[j][1] = ring.Take(j)
+		}
+		coordinates = append(coordinates, points)
+	}
+	return coordinates
+}
+
+// Parenthesized list of parenthesized rings
+func writeWKTRings (b *strings.Builder, rings []FlatPoints) {
+	b.WriteByte('(')
+	for i, ring := range(rings) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('(')
+		writeWKTCoordinates(b, ring)
+		b.WriteByte(')')
+	}
+	b.WriteByte(')')
+}
+
+func writeWKTCoordinates (b *strings.Builder, points FlatPoints) {
+	for i := 0; i < points.Len(); i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		x, y := points.Take(i)
+		b.WriteString(strconv.FormatFloat(x, 'g', -1, 64))
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatFloat(y, 'g', -1, 64))
+	}
+}
+
+const (
+	wkbPolygon = 3
+	wkbMultiPolygon = 6
+)
+
+// Byte order, geometry type and number of parts
+func appendWKBHeader (data []byte, geometryType uint32, parts int) []byte {
+	data = append(data, 1)
+	data = binary.LittleEndian.AppendUint32(data, geometryType)
+	return binary.LittleEndian.AppendUint32(data, uint32(parts))
+}
+
+func appendWKBPolygon (data []byte, rings []FlatPoints) []byte {
+	data = appendWKBHeader(data, wkbPolygon, len(rings))
+	for _, ring := range(rings) {
+		data = binary.LittleEndian.AppendUint32(data, uint32(ring.Len()))
+		for _, v := range(ring) {
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+		}
+	}
+	return data
+}
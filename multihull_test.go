@@ -0,0 +1,69 @@
+package ConcaveHull
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiHull_encoders (t *testing.T) {
+	m := MultiHullFromRings(Difference(FlatPoints{0, 0, 4, 0, 4, 4, 0, 4}, FlatPoints{1, 1, 3, 1, 3, 3, 1, 3}))
+	m = append(m, HullPolygon{Exterior: FlatPoints{5, 0, 5, 1, 6, 0}})
+	assert.Len(t, m, 2)
+	assert.Len(t, m[0].Holes, 1)
+	assert.Equal(t, "MULTIPOLYGON(((0 0,4 0,4 4,0 4,0 0),(1 1,1 3,3 3,3 1,1 1)),((5 0,6 0,5 1,5 0)))", m.WKT())
+	geoJSON, err := m.GeoJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"MultiPolygon","coordinates":[[[[0,0],[4,0],[4,4],[0,4],[0,0]],[[1,1],[1,3],[3,3],[3,1],[1,1]]],[[[5,0],[6,0],[5,1],[5,0]]]]}`, string(geoJSON))
+
+	wkb := m.WKB()
+	assert.Equal(t, byte(1), wkb[0])
+	assert.Equal(t, uint32(6), binary.LittleEndian.Uint32(wkb[1:]))
+	assert.Equal(t, uint32(2), binary.LittleEndian.Uint32(wkb[5:]))
+	// header, rings of 5, 5 and 4 points
+	assert.Len(t, wkb, 9 + 9 + 4 + 80 + 4 + 80 + 9 + 4 + 64)
+	assert.Equal(t, 4., math.Float64frombits(binary.LittleEndian.Uint64(wkb[9 + 9 + 4 + 16:])))
+
+	assert.Equal(t, "MULTIPOLYGON EMPTY", MultiHull(nil).WKT())
+}
+
+func TestHull_encoders (t *testing.T) {
+	hull := Hull{Points: FlatPoints{0, 0, 0, 1, 1, 1, 1, 0, 0, 0}}
+	assert.Equal(t, "POLYGON((0 0,1 0,1 1,0 1,0 0))", hull.WKT())
+	geoJSON, err := hull.GeoJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}`, string(geoJSON))
+	assert.Equal(t, MultiHull{{Exterior: hull.Points}}.WKB()[9:], hull.WKB())
+
+	point := Hull{Points: FlatPoints{1, 1}}
+	assert.Equal(t, "POLYGON EMPTY", point.WKT())
+	assert.Equal(t, []byte{1, 3, 0, 0, 0, 0, 0, 0, 0}, point.WKB())
+}
+
+func TestNewClusterMultiHull (t *testing.T) {
+	points := FlatPoints{0, 0, 1, 0, 1, 1, 0, 1, 0.5, 0.5, 10, 10, 11, 10, 11, 11, 10, 11, 20, 20}
+	hulls := ComputeClusters(points, &Options{ClusterDistance: 2, MinPoints: 3, KeepSmallClusters: true})
+	assert.Len(t, hulls, 3)
+	m := NewClusterMultiHull(hulls)
+	assert.Len(t, m, 2)
+
+	m = STConcaveHullMulti(points[:18], 1, false)
+	assert.Len(t, m, 1)
+}
+
+func TestSplitAntimeridian (t *testing.T) {
+	// from 170 to 190 east, that is 170 east to 170 west
+	m := SplitAntimeridian(FlatPoints{170, -10, 190, -10, 190, 10, 170, 10, 170, -10})
+	assert.Len(t, m, 2)
+	area := 0.
+	for _, p := range(m) {
+		minX, _, maxX, _ := bbox(p.Exterior)
+		assert.True(t, minX >= -180 && maxX <= 180)
+		area += SignedArea(p.Exterior)
+	}
+	assert.Equal(t, 400., area)
+	assert.Equal(t, `MULTIPOLYGON(((170 -10,180 -10,180 10,170 10,170 -10)),((-180 -10,-170 -10,-170 10,-180 10,-180 -10)))`, m.WKT())
+
+	assert.Len(t, SplitAntimeridian(FlatPoints{0, 0, 1, 0, 1, 1}), 1)
+}
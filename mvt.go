@@ -0,0 +1,219 @@
+package ConcaveHull
+
+import (
+	"math"
+	"sort"
+)
+
+const DEFAULT_MVT_EXTENT = 4096
+const DEFAULT_MVT_LAYER = "hulls"
+
+// Largest latitude representable in Web Mercator, the projection of map tiles
+const maxMercatorLatitude = 85.05112878
+
+// A polygon feature of a Mapbox Vector Tile, with coordinates in longitude, latitude degrees
+type MVTFeature struct {
+	ID uint64
+	// Exterior rings each followed by its holes. Rings may be closed or not, orientation is fixed on encoding
+	Rings []FlatPoints
+	// Values can be string, float64, int, int64 or bool, other types are skipped
+	Properties map[string]interface{}
+}
+
+type MVTOptions struct {
+	Layer string // name of the layer, defaults to DEFAULT_MVT_LAYER
+	Extent uint32 // tile resolution, defaults to DEFAULT_MVT_EXTENT
+}
+
+// Encode the features as a Mapbox Vector Tile with a single layer for tile z/x/y. Coordinates are projected to Web Mercator
+// and quantized to the tile grid, rings that collapse to less than three points are dropped. Geometries are not clipped
+// to the tile, renderers clip them
+func EncodeMVT (features []MVTFeature, z, x, y int, o MVTOptions) []byte {
+	if o.Layer == "" {
+		o.Layer = DEFAULT_MVT_LAYER
+	}
+	if o.Extent == 0 {
+		o.Extent = DEFAULT_MVT_EXTENT
+	}
+	var layer protoBuffer
+	layer.uintField(15, 2) // version
+	layer.stringField(1, o.Layer)
+	var keys []string
+	keyIndex := make(map[string]int)
+	var values []interface{}
+	valueIndex := make(map[interface{}]int)
+	for _, f := range(features) {
+		geometry := mvtPolygonGeometry(f.Rings, z, x, y, o.Extent)
+		if len(geometry) == 0 {
+			continue
+		}
+		var feature protoBuffer
+		if f.ID != 0 {
+			feature.uintField(1, f.ID)
+		}
+		// sorted keys so that the output is deterministic
+		names := make([]string, 0, len(f.Properties))
+		for name := range(f.Properties) {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var tags []uint64
+		for _, name := range(names) {
+			value := f.Properties[name]
+			switch v := value.(type) {
+			case int:
+				value = int64(v)
+			case string, float64, int64, bool:
+			default:
+				continue
+			}
+			if _, ok := keyIndex[name]; !ok {
+				keyIndex[name] = len(keys)
+				keys = append(keys, name)
+			}
+			if _, ok := valueIndex[value]; !ok {
+				valueIndex[value] = len(values)
+				values = append(values, value)
+			}
+			tags = append(tags, uint64(keyIndex[name]), uint64(valueIndex[value]))
+		}
+		if len(tags) > 0 {
+			feature.packedField(2, tags)
+		}
+		feature.uintField(3, 3) // polygon
+		feature.packedField(4, geometry)
+		layer.bytesField(2, feature)
+	}
+	for _, k := range(keys) {
+		layer.stringField(3, k)
+	}
+	for _, v := range(values) {
+		var value protoBuffer
+		switch v := v.(type) {
+		case string:
+			value.stringField(1, v)
+		case float64:
+			value.fixed64Field(3, math.Float64bits(v))
+		case int64:
+			value.uintField(6, zigzag(v))
+		case bool:
+			b := uint64(0)
+			if v {
+				b = 1
+			}
+			value.uintField(7, b)
+		}
+		layer.bytesField(4, value)
+	}
+	layer.uintField(5, uint64(o.Extent))
+	var tile protoBuffer
+	tile.bytesField(3, layer)
+	return tile
+}
+
+// Geometry commands of a polygon. Exterior rings get positive area in tile coordinates, where y points down, and holes negative area
+func mvtPolygonGeometry (rings []FlatPoints, z, x, y int, extent uint32) []uint64 {
+	var geometry []uint64
+	var cursorX, cursorY int64
+	for r, ring := range(rings) {
+		ring = openRing(ring)
+		var quantized []int64
+		for i := 0; i < ring.Len(); i++ {
+			px, py := tilePixel(ring[2 * i], ring[2 * i + 1], z, x, y, extent)
+			if n := len(quantized); n > 0 && quantized[n - 2] == px && quantized[n - 1] == py {
+				continue
+			}
+			quantized = append(quantized, px, py)
+		}
+		if n := len(quantized); n > 2 && quantized[0] == quantized[n - 2] && quantized[1] == quantized[n - 1] {
+			quantized = quantized[:n - 2]
+		}
+		if len(quantized) < 6 {
+			continue
+		}
+		area := 0.
+		for i := 0; i < len(quantized); i += 2 {
+			j := (i + 2) % len(quantized)
+			area += float64(quantized[i] * quantized[j + 1] - quantized[j] * quantized[i + 1])
+		}
+		// the first ring is an exterior, following ones are holes unless they have the orientation of an exterior in the input
+		isExterior := r == 0 || IsCCW(ring)
+		if isExterior != (area > 0) {
+			for i, j := 0, len(quantized) - 2; i < j; i, j = i + 2, j - 2 {
+				quantized[i], quantized[i + 1], quantized[j], quantized[j + 1] = quantized[j], quantized[j + 1], quantized[i], quantized[i + 1]
+			}
+		}
+		geometry = append(geometry, mvtCommand(1, 1), zigzag(quantized[0] - cursorX), zigzag(quantized[1] - cursorY))
+		cursorX, cursorY = quantized[0], quantized[1]
+		geometry = append(geometry, mvtCommand(2, len(quantized) / 2 - 1))
+		for i := 2; i < len(quantized); i += 2 {
+			geometry = append(geometry, zigzag(quantized[i] - cursorX), zigzag(quantized[i + 1] - cursorY))
+			cursorX, cursorY = quantized[i], quantized[i + 1]
+		}
+		geometry = append(geometry, mvtCommand(7, 1))
+	}
+	return geometry
+}
+
+// Position of a longitude, latitude in the grid of tile z/x/y
+func tilePixel (lon, lat float64, z, x, y int, extent uint32) (int64, int64) {
+	worldX, worldY := lonLatToWorld(lon, lat)
+	scale := math.Exp2(float64(z))
+	return int64(math.Round((worldX * scale - float64(x)) * float64(extent))), int64(math.Round((worldY * scale - float64(y)) * float64(extent)))
+}
+
+// Web Mercator position in [0, 1] x [0, 1], with y growing southwards as in map tiles
+func lonLatToWorld (lon, lat float64) (float64, float64) {
+	lat = math.Max(-maxMercatorLatitude, math.Min(maxMercatorLatitude, lat))
+	sin := math.Sin(lat * math.Pi / 180)
+	return (lon + 180) / 360, 0.5 - math.Log((1 + sin) / (1 - sin)) / (4 * math.Pi)
+}
+
+func mvtCommand (id, count int) uint64 {
+	return uint64(id & 0x7 | count << 3)
+}
+
+func zigzag (v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+// Minimal protocol buffers writer
+type protoBuffer []byte
+
+func (b *protoBuffer) varint (v uint64) {
+	for v >= 0x80 {
+		*b = append(*b, byte(v) | 0x80)
+		v >>= 7
+	}
+	*b = append(*b, byte(v))
+}
+
+func (b *protoBuffer) uintField (field int, v uint64) {
+	b.varint(uint64(field << 3))
+	b.varint(v)
+}
+
+func (b *protoBuffer) fixed64Field (field int, v uint64) {
+	b.varint(uint64(field << 3 | 1))
+	for i := 0; i < 8; i++ {
+		*b = append(*b, byte(v >> (8 * i)))
+	}
+}
+
+func (b *protoBuffer) bytesField (field int, data []byte) {
+	b.varint(uint64(field << 3 | 2))
+	b.varint(uint64(len(data)))
+	*b = append(*b, data...)
+}
+
+func (b *protoBuffer) stringField (field int, s string) {
+	b.bytesField(field, []byte(s))
+}
+
+func (b *protoBuffer) packedField (field int, values []uint64) {
+	var packed protoBuffer
+	for _, v := range(values) {
+		packed.varint(v)
+	}
+	b.bytesField(field, packed)
+}
@@ -0,0 +1,35 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeMVT (t *testing.T) {
+	// counter clockwise square covering the north east quarter of tile 0/0/0
+	square := FlatPoints{0, 0, 180, 0, 180, maxMercatorLatitude, 0, maxMercatorLatitude, 0, 0}
+	tile := EncodeMVT([]MVTFeature{{ID: 7, Rings: []FlatPoints{square}, Properties: map[string]interface{}{"name": "a"}}}, 0, 0, 0, MVTOptions{Extent: 4})
+	geometry := []uint64{
+		// clockwise with y pointing down
+		mvtCommand(1, 1), zigzag(2), zigzag(0),
+		mvtCommand(2, 3), zigzag(2), zigzag(0), zigzag(0), zigzag(2), zigzag(-2), zigzag(0),
+		mvtCommand(7, 1),
+	}
+	var feature protoBuffer
+	feature.uintField(1, 7)
+	feature.packedField(2, []uint64{0, 0})
+	feature.uintField(3, 3)
+	feature.packedField(4, geometry)
+	var value protoBuffer
+	value.stringField(1, "a")
+	var layer protoBuffer
+	layer.uintField(15, 2)
+	layer.stringField(1, DEFAULT_MVT_LAYER)
+	layer.bytesField(2, feature)
+	layer.stringField(3, "name")
+	layer.bytesField(4, value)
+	layer.uintField(5, 4)
+	var expected protoBuffer
+	expected.bytesField(3, layer)
+	assert.Equal(t, []byte(expected), tile)
+}
@@ -0,0 +1,420 @@
+package ConcaveHull
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+var ErrInvalidNetCDF = errors.New("ConcaveHull: invalid NetCDF")
+
+// Returned for NetCDF-4 files, which are HDF5 files. They can be converted to the classic format with nccopy -k classic
+var ErrUnsupportedNetCDF = errors.New("ConcaveHull: only the classic NetCDF formats are supported")
+
+// Variables of the coordinates in ReadNetCDF
+type NetCDFOptions struct {
+	// Names of the variables, found by their standard_name, units or usual names (lon, longitude, lat, latitude) if empty
+	XVariable, YVariable string
+}
+
+// Types of the classic NetCDF format
+const (
+	ncByte = 1
+	ncChar = 2
+	ncShort = 3
+	ncInt = 4
+	ncFloat = 5
+	ncDouble = 6
+	ncUByte = 7
+	ncUShort = 8
+	ncUInt = 9
+	ncInt64 = 10
+	ncUInt64 = 11
+)
+
+// Tags of the lists of the header
+const (
+	ncDimensionTag = 0x0A
+	ncVariableTag = 0x0B
+	ncAttributeTag = 0x0C
+)
+
+type ncDimension struct {
+	name string
+	length uint64 // 0 for the record dimension
+}
+
+type ncAttribute struct {
+	name string
+	ncType uint32
+	text string // for NC_CHAR
+	values []float64 // for numeric types
+}
+
+type ncVariable struct {
+	name string
+	dimensions []uint64
+	attributes []ncAttribute
+	ncType uint32
+	size uint64
+	begin uint64
+}
+
+type ncFile struct {
+	version byte
+	numRecords uint64
+	dimensions []ncDimension
+	variables []ncVariable
+	recordSize uint64
+}
+
+// Read station or point coordinates from a NetCDF file in the classic, 64-bit offset or 64-bit data format, typically
+// longitudes and latitudes. Packed values are unpacked with scale_factor and add_offset, and points where either coordinate
+// is _FillValue, missing_value or NaN are skipped. Variables sharing their dimensions give one point per element, two one
+// dimensional variables over different dimensions, such as the axes of a model grid, give one point per grid node
+func ReadNetCDF (r io.ReaderAt, o NetCDFOptions) (FlatPoints, error) {
+	f, err := readNetCDFHeader(bufio.NewReader(io.NewSectionReader(r, 0, math.MaxInt64)))
+	if err != nil {
+		return nil, err
+	}
+	x, y := f.find(o.XVariable, "longitude", "degrees_east", "lon"), f.find(o.YVariable, "latitude", "degrees_north", "lat")
+	if x == nil || y == nil {
+		return nil, fmt.Errorf("%w: coordinate variables not found", ErrInvalidNetCDF)
+	}
+	xs, err := f.values(r, x)
+	if err != nil {
+		return nil, err
+	}
+	ys, err := f.values(r, y)
+	if err != nil {
+		return nil, err
+	}
+	var points FlatPoints
+	if len(x.dimensions) == 1 && len(y.dimensions) == 1 && x.dimensions[0] != y.dimensions[0] {
+		for _, vy := range(ys) {
+			for _, vx := range(xs) {
+				points = appendValidPoint(points, vx, vy)
+			}
+		}
+		return points, nil
+	}
+	if len(xs) != len(ys) {
+		return nil, fmt.Errorf("%w: variables %s and %s have %d and %d values", ErrInvalidNetCDF, x.name, y.name, len(xs), len(ys))
+	}
+	for i := range(xs) {
+		points = appendValidPoint(points, xs[i], ys[i])
+	}
+	return points, nil
+}
+
+// Missing values are NaN after unpacking
+func appendValidPoint (points FlatPoints, x, y float64) FlatPoints {
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return points
+	}
+	return append(points, x, y)
+}
+
+// The variable called name or, if name is empty, the first one with that standard_name, units or usual name
+func (f *ncFile) find (name, standardName, units, shortName string) *ncVariable {
+	if name != "" {
+		for i := range(f.variables) {
+			if f.variables[i].name == name {
+				return &f.variables[i]
+			}
+		}
+		return nil
+	}
+	for _, matches := range([]func (v *ncVariable) bool{
+		func (v *ncVariable) bool { return v.attribute("standard_name") == standardName },
+		func (v *ncVariable) bool { return v.attribute("units") == units },
+		func (v *ncVariable) bool { return v.name == shortName || v.name == standardName },
+	}) {
+		for i := range(f.variables) {
+			if matches(&f.variables[i]) {
+				return &f.variables[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (v *ncVariable) attribute (name string) string {
+	for _, a := range(v.attributes) {
+		if a.name == name {
+			return a.text
+		}
+	}
+	return ""
+}
+
+func (v *ncVariable) number (name string) (float64, bool) {
+	for _, a := range(v.attributes) {
+		if a.name == name && len(a.values) > 0 {
+			return a.values[0], true
+		}
+	}
+	return 0, false
+}
+
+// Values of a numeric variable, unpacked, with missing values as NaN
+func (f *ncFile) values (r io.ReaderAt, v *ncVariable) ([]float64, error) {
+	size := ncTypeSize(v.ncType)
+	if size == 0 || v.ncType == ncChar {
+		return nil, fmt.Errorf("%w: variable %s is not numeric", ErrInvalidNetCDF, v.name)
+	}
+	isRecord := false
+	count := uint64(1)
+	for k, d := range(v.dimensions) {
+		if d >= uint64(len(f.dimensions)) {
+			return nil, ErrInvalidNetCDF
+		}
+		if f.dimensions[d].length == 0 && k == 0 {
+			isRecord = true
+			continue
+		}
+		count *= f.dimensions[d].length
+	}
+	if count > math.MaxInt32 / size {
+		return nil, ErrInvalidNetCDF
+	}
+	var raw []byte
+	if isRecord {
+		if f.numRecords > math.MaxInt32 / (count * size + 1) {
+			return nil, ErrInvalidNetCDF
+		}
+		raw = make([]byte, 0, f.numRecords * count * size)
+		chunk := make([]byte, count * size)
+		for record := uint64(0); record < f.numRecords; record++ {
+			if _, err := r.ReadAt(chunk, int64(v.begin + record * f.recordSize)); err != nil {
+				return nil, ErrInvalidNetCDF
+			}
+			raw = append(raw, chunk...)
+		}
+	} else {
+		raw = make([]byte, count * size)
+		if _, err := r.ReadAt(raw, int64(v.begin)); err != nil {
+			return nil, ErrInvalidNetCDF
+		}
+	}
+	values := ncDecode(v.ncType, raw)
+	scale, hasScale := v.number("scale_factor")
+	offset, _ := v.number("add_offset")
+	fill, hasFill := v.number("_FillValue")
+	missing, hasMissing := v.number("missing_value")
+	for i, value := range(values) {
+		if hasFill && value == fill || hasMissing && value == missing {
+			values[i] = math.NaN()
+			continue
+		}
+		if hasScale {
+			value *= scale
+		}
+		values[i] = value + offset
+	}
+	return values, nil
+}
+
+func ncTypeSize (t uint32) uint64 {
+	switch t {
+	case ncByte, ncChar, ncUByte:
+		return 1
+	case ncShort, ncUShort:
+		return 2
+	case ncInt, ncFloat, ncUInt:
+		return 4
+	case ncDouble, ncInt64, ncUInt64:
+		return 8
+	}
+	return 0
+}
+
+// Big endian values of type t as float64
+func ncDecode (t uint32, raw []byte) []float64 {
+	size := int(ncTypeSize(t))
+	values := make([]float64, len(raw) / size)
+	for i := range(values) {
+		b := raw[i * size:]
+		switch t {
+		case ncByte:
+			values[i] = float64(int8(b[0]))
+		case ncChar, ncUByte:
+			values[i] = float64(b[0])
+		case ncShort:
+			values[i] = float64(int16(binary.BigEndian.Uint16(b)))
+		case ncUShort:
+			values[i] = float64(binary.BigEndian.Uint16(b))
+		case ncInt:
+			values[i] = float64(int32(binary.BigEndian.Uint32(b)))
+		case ncUInt:
+			values[i] = float64(binary.BigEndian.Uint32(b))
+		case ncFloat:
+			values[i] = float64(math.Float32frombits(binary.BigEndian.Uint32(b)))
+		case ncDouble:
+			values[i] = math.Float64frombits(binary.BigEndian.Uint64(b))
+		case ncInt64:
+			values[i] = float64(int64(binary.BigEndian.Uint64(b)))
+		case ncUInt64:
+			values[i] = float64(binary.BigEndian.Uint64(b))
+		}
+	}
+	return values
+}
+
+// Reads the header, remembering the first error so that callers check only once
+type ncReader struct {
+	r io.Reader
+	version byte
+	err error
+}
+
+func (nr *ncReader) read (n uint64) []byte {
+	if nr.err != nil {
+		return nil
+	}
+	if n > 1 << 24 {
+		nr.err = ErrInvalidNetCDF
+		return nil
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(nr.r, b); err != nil {
+		nr.err = ErrInvalidNetCDF
+		return nil
+	}
+	return b
+}
+
+func (nr *ncReader) uint32 () uint32 {
+	if b := nr.read(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (nr *ncReader) uint64 () uint64 {
+	if b := nr.read(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+// Counts and sizes are 64 bits in the 64-bit data format
+func (nr *ncReader) nonNegative () uint64 {
+	if nr.version == 5 {
+		return nr.uint64()
+	}
+	return uint64(nr.uint32())
+}
+
+// Offsets are 64 bits in the 64-bit offset and 64-bit data formats
+func (nr *ncReader) offset () uint64 {
+	if nr.version == 1 {
+		return uint64(nr.uint32())
+	}
+	return nr.uint64()
+}
+
+// Bytes padded to a multiple of 4
+func (nr *ncReader) padded (n uint64) []byte {
+	b := nr.read(n)
+	nr.read((4 - n % 4) % 4)
+	return b
+}
+
+func (nr *ncReader) name () string {
+	return string(nr.padded(nr.nonNegative()))
+}
+
+// Number of elements of a list with the given tag, 0 if absent
+func (nr *ncReader) list (tag uint32) uint64 {
+	t := nr.uint32()
+	n := nr.nonNegative()
+	if nr.err == nil && t != tag && (t != 0 || n != 0) {
+		nr.err = ErrInvalidNetCDF
+	}
+	return n
+}
+
+func (nr *ncReader) attributes () []ncAttribute {
+	var attributes []ncAttribute
+	for n := nr.list(ncAttributeTag); n > 0 && nr.err == nil; n-- {
+		a := ncAttribute{name: nr.name(), ncType: nr.uint32()}
+		count := nr.nonNegative()
+		size := ncTypeSize(a.ncType)
+		if size == 0 || count > 1 << 24 {
+			nr.err = ErrInvalidNetCDF
+			break
+		}
+		raw := nr.padded(count * size)
+		if a.ncType == ncChar {
+			a.text = string(raw)
+		} else {
+			a.values = ncDecode(a.ncType, raw)
+		}
+		attributes = append(attributes, a)
+	}
+	return attributes
+}
+
+func readNetCDFHeader (r io.Reader) (*ncFile, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, ErrInvalidNetCDF
+	}
+	if magic == [4]byte{0x89, 'H', 'D', 'F'} {
+		return nil, ErrUnsupportedNetCDF
+	}
+	if string(magic[:3]) != "CDF" || magic[3] != 1 && magic[3] != 2 && magic[3] != 5 {
+		return nil, ErrInvalidNetCDF
+	}
+	nr := &ncReader{r: r, version: magic[3]}
+	f := &ncFile{version: magic[3]}
+	f.numRecords = nr.nonNegative()
+	if f.numRecords == 0xFFFFFFFF || f.numRecords == math.MaxUint64 {
+		return nil, fmt.Errorf("%w: streaming files with an unknown number of records", ErrUnsupportedNetCDF)
+	}
+	for n := nr.list(ncDimensionTag); n > 0 && nr.err == nil; n-- {
+		f.dimensions = append(f.dimensions, ncDimension{name: nr.name(), length: nr.nonNegative()})
+	}
+	nr.attributes()
+	var recordVariables []*ncVariable
+	for n := nr.list(ncVariableTag); n > 0 && nr.err == nil; n-- {
+		v := ncVariable{name: nr.name()}
+		rank := nr.nonNegative()
+		if rank > 1 << 10 {
+			return nil, ErrInvalidNetCDF
+		}
+		for k := uint64(0); k < rank; k++ {
+			v.dimensions = append(v.dimensions, nr.nonNegative())
+		}
+		v.attributes = nr.attributes()
+		v.ncType = nr.uint32()
+		v.size = nr.nonNegative()
+		v.begin = nr.offset()
+		f.variables = append(f.variables, v)
+	}
+	if nr.err != nil {
+		return nil, nr.err
+	}
+	for i := range(f.variables) {
+		v := &f.variables[i]
+		if len(v.dimensions) > 0 && v.dimensions[0] < uint64(len(f.dimensions)) && f.dimensions[v.dimensions[0]].length == 0 {
+			recordVariables = append(recordVariables, v)
+			f.recordSize += v.size
+		}
+	}
+	// a single record variable is not padded
+	if len(recordVariables) == 1 {
+		v := recordVariables[0]
+		f.recordSize = ncTypeSize(v.ncType)
+		for _, d := range(v.dimensions[1:]) {
+			if d < uint64(len(f.dimensions)) {
+				f.recordSize *= f.dimensions[d].length
+			}
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,236 @@
+package ConcaveHull
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+type testNCVariable struct {
+	name string
+	dimensions []uint64
+	attributes []ncAttribute
+	ncType uint32
+	data []byte // all of it, records are interleaved by classicNetCDF
+}
+
+// NetCDF file in the given classic format version, record variables first dimension being the dimension of length 0
+func classicNetCDF (version byte, numRecords uint64, dimensions []ncDimension, variables []testNCVariable) []byte {
+	var header bytes.Buffer
+	nonNegative := func (n uint64) {
+		if version == 5 {
+			header.Write(binary.BigEndian.AppendUint64(nil, n))
+		} else {
+			header.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+		}
+	}
+	padded := func (b []byte) {
+		header.Write(b)
+		header.Write(make([]byte, (4 - len(b) % 4) % 4))
+	}
+	name := func (s string) {
+		nonNegative(uint64(len(s)))
+		padded([]byte(s))
+	}
+	isRecord := func (v testNCVariable) bool {
+		return len(v.dimensions) > 0 && dimensions[v.dimensions[0]].length == 0
+	}
+	// sizes are padded to 4 bytes, except for the only record variable
+	records := 0
+	for _, v := range(variables) {
+		if isRecord(v) {
+			records++
+		}
+	}
+	size := func (v testNCVariable) uint64 {
+		n := uint64(len(v.data))
+		if isRecord(v) {
+			n /= numRecords
+			if records == 1 {
+				return n
+			}
+		}
+		return (n + 3) &^ 3
+	}
+	recordSize := uint64(0)
+	for _, v := range(variables) {
+		if isRecord(v) {
+			recordSize += size(v)
+		}
+	}
+	write := func (begins []uint64) {
+		header.Reset()
+		header.Write([]byte{'C', 'D', 'F', version})
+		nonNegative(numRecords)
+		header.Write(binary.BigEndian.AppendUint32(nil, ncDimensionTag))
+		nonNegative(uint64(len(dimensions)))
+		for _, d := range(dimensions) {
+			name(d.name)
+			nonNegative(d.length)
+		}
+		header.Write(make([]byte, 4))
+		nonNegative(0)
+		header.Write(binary.BigEndian.AppendUint32(nil, ncVariableTag))
+		nonNegative(uint64(len(variables)))
+		for i, v := range(variables) {
+			name(v.name)
+			nonNegative(uint64(len(v.dimensions)))
+			for _, d := range(v.dimensions) {
+				nonNegative(d)
+			}
+			header.Write(binary.BigEndian.AppendUint32(nil, ncAttributeTag))
+			nonNegative(uint64(len(v.attributes)))
+			for _, a := range(v.attributes) {
+				name(a.name)
+				header.Write(binary.BigEndian.AppendUint32(nil, a.ncType))
+				if a.ncType == ncChar {
+					nonNegative(uint64(len(a.text)))
+					padded([]byte(a.text))
+				} else {
+					nonNegative(uint64(len(a.values)))
+					padded(binary.BigEndian.AppendUint64(nil, math.Float64bits(a.values[0])))
+				}
+			}
+			header.Write(binary.BigEndian.AppendUint32(nil, v.ncType))
+			nonNegative(size(v))
+			if version == 1 {
+				header.Write(binary.BigEndian.AppendUint32(nil, uint32(begins[i])))
+			} else {
+				header.Write(binary.BigEndian.AppendUint64(nil, begins[i]))
+			}
+		}
+	}
+	begins := make([]uint64, len(variables))
+	write(begins)
+	offset := uint64(header.Len())
+	for i, v := range(variables) {
+		if !isRecord(v) {
+			begins[i] = offset
+			offset += size(v)
+		}
+	}
+	for i, v := range(variables) {
+		if isRecord(v) {
+			begins[i] = offset
+			offset += size(v)
+		}
+	}
+	write(begins)
+	file := header.Bytes()
+	for _, v := range(variables) {
+		if !isRecord(v) {
+			file = append(file, v.data...)
+			file = append(file, make([]byte, size(v) - uint64(len(v.data)))...)
+		}
+	}
+	for record := uint64(0); record < numRecords; record++ {
+		for i, v := range(variables) {
+			if isRecord(v) {
+				n := uint64(len(v.data)) / numRecords
+				for uint64(len(file)) < begins[i] + record * recordSize {
+					file = append(file, 0)
+				}
+				file = append(file, v.data[record * n:(record + 1) * n]...)
+			}
+		}
+	}
+	return file
+}
+
+func ncDoubles (values ...float64) []byte {
+	var b []byte
+	for _, v := range(values) {
+		b = binary.BigEndian.AppendUint64(b, math.Float64bits(v))
+	}
+	return b
+}
+
+func ncFloats (values ...float64) []byte {
+	var b []byte
+	for _, v := range(values) {
+		b = binary.BigEndian.AppendUint32(b, math.Float32bits(float32(v)))
+	}
+	return b
+}
+
+func TestReadNetCDF_stations (t *testing.T) {
+	dimensions := []ncDimension{{name: "station", length: 3}}
+	for _, version := range([]byte{1, 2, 5}) {
+		file := classicNetCDF(version, 0, dimensions, []testNCVariable{
+			{name: "elevation", dimensions: []uint64{0}, ncType: ncDouble, data: ncDoubles(10, 20, 30)},
+			{name: "y", dimensions: []uint64{0}, ncType: ncDouble, data: ncDoubles(40, 41, 42),
+				attributes: []ncAttribute{{name: "standard_name", ncType: ncChar, text: "latitude"}}},
+			{name: "x", dimensions: []uint64{0}, ncType: ncFloat, data: ncFloats(-90, -89.5, -89),
+				attributes: []ncAttribute{{name: "units", ncType: ncChar, text: "degrees_east"}}},
+		})
+		points, err := ReadNetCDF(bytes.NewReader(file), NetCDFOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, FlatPoints{-90, 40, -89.5, 41, -89, 42}, points)
+
+		points, err = ReadNetCDF(bytes.NewReader(file), NetCDFOptions{XVariable: "elevation", YVariable: "y"})
+		assert.Nil(t, err)
+		assert.Equal(t, FlatPoints{10, 40, 20, 41, 30, 42}, points)
+	}
+}
+
+func TestReadNetCDF_records (t *testing.T) {
+	short := func (values ...int16) []byte {
+		var b []byte
+		for _, v := range(values) {
+			b = binary.BigEndian.AppendUint16(b, uint16(v))
+		}
+		return b
+	}
+	packed := []ncAttribute{
+		{name: "scale_factor", ncType: ncDouble, values: []float64{0.5}},
+		{name: "add_offset", ncType: ncDouble, values: []float64{100}},
+		{name: "_FillValue", ncType: ncDouble, values: []float64{-1}},
+	}
+	file := classicNetCDF(1, 4, []ncDimension{{name: "obs", length: 0}}, []testNCVariable{
+		{name: "lon", dimensions: []uint64{0}, ncType: ncShort, data: short(0, 2, -1, 4), attributes: packed},
+		{name: "lat", dimensions: []uint64{0}, ncType: ncDouble, data: ncDoubles(1, 2, 3, math.NaN())},
+	})
+	points, err := ReadNetCDF(bytes.NewReader(file), NetCDFOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, FlatPoints{100, 1, 101, 2}, points)
+
+	// a single record variable is not padded, here it is an axis of a grid
+	file = classicNetCDF(1, 3, []ncDimension{{name: "obs", length: 0}, {name: "station", length: 3}}, []testNCVariable{
+		{name: "lon", dimensions: []uint64{1}, ncType: ncDouble, data: ncDoubles(5, 6, 7)},
+		{name: "lat", dimensions: []uint64{0}, ncType: ncShort, data: short(1, 2, 3)},
+	})
+	points, err = ReadNetCDF(bytes.NewReader(file), NetCDFOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, FlatPoints{5, 1, 6, 1, 7, 1, 5, 2, 6, 2, 7, 2, 5, 3, 6, 3, 7, 3}, points)
+}
+
+func TestReadNetCDF_grid (t *testing.T) {
+	file := classicNetCDF(2, 0, []ncDimension{{name: "lat", length: 2}, {name: "lon", length: 3}}, []testNCVariable{
+		{name: "lat", dimensions: []uint64{0}, ncType: ncDouble, data: ncDoubles(10, 11)},
+		{name: "lon", dimensions: []uint64{1}, ncType: ncDouble, data: ncDoubles(0, 1, 2)},
+	})
+	points, err := ReadNetCDF(bytes.NewReader(file), NetCDFOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, FlatPoints{0, 10, 1, 10, 2, 10, 0, 11, 1, 11, 2, 11}, points)
+}
+
+func TestReadNetCDF_invalid (t *testing.T) {
+	_, err := ReadNetCDF(bytes.NewReader([]byte("\x89HDF\r\n\x1a\n")), NetCDFOptions{})
+	assert.True(t, errors.Is(err, ErrUnsupportedNetCDF))
+	_, err = ReadNetCDF(bytes.NewReader([]byte("CDF\x03")), NetCDFOptions{})
+	assert.True(t, errors.Is(err, ErrInvalidNetCDF))
+
+	file := classicNetCDF(1, 0, []ncDimension{{name: "station", length: 2}}, []testNCVariable{
+		{name: "lon", dimensions: []uint64{0}, ncType: ncDouble, data: ncDoubles(1, 2)},
+		{name: "lat", dimensions: []uint64{0}, ncType: ncDouble, data: ncDoubles(3, 4)},
+	})
+	for n := 0; n < len(file); n++ {
+		_, err = ReadNetCDF(bytes.NewReader(file[:n]), NetCDFOptions{})
+		assert.True(t, errors.Is(err, ErrInvalidNetCDF))
+	}
+	_, err = ReadNetCDF(bytes.NewReader(file), NetCDFOptions{XVariable: "longitude"})
+	assert.True(t, errors.Is(err, ErrInvalidNetCDF))
+}
@@ -0,0 +1,152 @@
+package ConcaveHull
+
+import "time"
+
+// Functional option for NewOptions. New settings are added as new functions, so code built on them keeps compiling
+type Option func (*Options)
+
+// Options with the given settings applied in order, for example NewOptions(WithSeglength(10), WithWorkers(4))
+func NewOptions (opts ...Option) *Options {
+	o := &Options{}
+	for _, opt := range(opts) {
+		opt(o)
+	}
+	return o
+}
+
+func WithSeglength (seglength float64) Option {
+	return func (o *Options) { o.Seglength = seglength }
+}
+
+func WithSeglengthRelative (fraction float64) Option {
+	return func (o *Options) { o.SeglengthRelative = fraction }
+}
+
+func WithSearchEpsilon (epsilon float64) Option {
+	return func (o *Options) { o.SearchEpsilon = epsilon }
+}
+
+func WithMetric (metric Metric) Option {
+	return func (o *Options) { o.Metric = metric }
+}
+
+func WithAlgorithm (algorithm Algorithm) Option {
+	return func (o *Options) { o.Algorithm = algorithm }
+}
+
+func WithCache (cache Cache) Option {
+	return func (o *Options) { o.Cache = cache }
+}
+
+func WithTimeBudget (budget time.Duration) Option {
+	return func (o *Options) { o.TimeBudget = budget }
+}
+
+func WithCheckpoint (path string, interval time.Duration) Option {
+	return func (o *Options) { o.CheckpointPath, o.CheckpointInterval = path, interval }
+}
+
+func WithMaxEdgeLength (length float64) Option {
+	return func (o *Options) { o.MaxEdgeLength = length }
+}
+
+func WithWeldTolerance (tolerance float64) Option {
+	return func (o *Options) { o.WeldTolerance = tolerance }
+}
+
+func WithSmoothWindow (window float64) Option {
+	return func (o *Options) { o.SmoothWindow = window }
+}
+
+func WithMaxDepth (depth float64) Option {
+	return func (o *Options) { o.MaxDepth = depth }
+}
+
+func WithGridSize (size float64) Option {
+	return func (o *Options) { o.GridSize = size }
+}
+
+func WithClipInputBBox (box BBox) Option {
+	return func (o *Options) { o.ClipInputBBox = &box }
+}
+
+func WithMaxBisectionDepth (depth int) Option {
+	return func (o *Options) { o.MaxBisectionDepth = depth }
+}
+
+func WithMaxVertices (maxVertices int) Option {
+	return func (o *Options) { o.MaxVertices = maxVertices }
+}
+
+func WithTransform (transform Affine) Option {
+	return func (o *Options) { o.Transform = &transform }
+}
+
+func WithProjection (projection CoordinateTransformer) Option {
+	return func (o *Options) { o.Projection = projection }
+}
+
+func WithClusters (distance float64, minPoints int) Option {
+	return func (o *Options) { o.ClusterDistance, o.MinPoints = distance, minPoints }
+}
+
+func WithWorkers (workers int) Option {
+	return func (o *Options) { o.Workers = workers }
+}
+
+func WithMaxProbes (probes int) Option {
+	return func (o *Options) { o.MaxProbes = probes }
+}
+
+func WithRTreeNodeSize (size int) Option {
+	return func (o *Options) { o.RTreeNodeSize = size }
+}
+
+func WithCompatVersion (version CompatVersion) Option {
+	return func (o *Options) { o.CompatVersion = version }
+}
+
+func WithStrict () Option {
+	return func (o *Options) { o.Strict = true }
+}
+
+func WithSingleThreaded () Option {
+	return func (o *Options) { o.SingleThreaded = true }
+}
+
+func WithInstrumentation (instrumentation Instrumentation) Option {
+	return func (o *Options) { o.Instrumentation = instrumentation }
+}
+
+func WithTracer (tracer Tracer) Option {
+	return func (o *Options) { o.Tracer = tracer }
+}
+
+func WithDebug () Option {
+	return func (o *Options) { o.Debug = true }
+}
+
+// Copy of the options taken when a computation starts, so that the caller may change or share them meanwhile.
+// Values behind pointers are copied too, interfaces are shared
+func (o *Options) snapshot () *Options {
+	if o == nil {
+		return nil
+	}
+	s := *o
+	if o.Transform != nil {
+		transform := *o.Transform
+		s.Transform = &transform
+	}
+	if o.InverseTransform != nil {
+		inverse := *o.InverseTransform
+		s.InverseTransform = &inverse
+	}
+	if o.ClipInputBBox != nil {
+		box := *o.ClipInputBBox
+		s.ClipInputBBox = &box
+	}
+	if o.PreserveVertices != nil {
+		s.PreserveVertices = append(FlatPoints{}, o.PreserveVertices...)
+	}
+	return &s
+}
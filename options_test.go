@@ -0,0 +1,46 @@
+package ConcaveHull
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestNewOptions (t *testing.T) {
+	o := NewOptions(WithSeglength(10), WithClusters(2, 3), WithWorkers(4), WithTransform(Translation(1, 2)), WithDebug())
+	translation := Translation(1, 2)
+	assert.Equal(t, &Options{Seglength: 10, ClusterDistance: 2, MinPoints: 3, Workers: 4, Transform: &translation, Debug: true}, o)
+}
+
+func TestOptions_snapshot (t *testing.T) {
+	translation := Translation(1, 2)
+	o := &Options{Seglength: 1, Transform: &translation}
+	s := o.snapshot()
+	o.Seglength = 2
+	o.Transform.C = 5
+	assert.Equal(t, 1., s.Seglength)
+	assert.Equal(t, 1., s.Transform.C)
+	assert.Nil(t, (*Options)(nil).snapshot())
+}
+
+func TestOptions_sharedAcrossGoroutines (t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	points := hulltest.Random(r, 300)
+	o := NewOptions(WithSeglength(0.05), WithTransform(Rotation(0.3)))
+	expected := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), o)
+	var wg sync.WaitGroup
+	results := make([]FlatPoints, 8)
+	for i := range(results) {
+		wg.Add(1)
+		go func (i int) {
+			defer wg.Done()
+			results[i] = ComputeWithOptions(FlatPoints(append([]float64{}, points...)), o)
+		}(i)
+	}
+	wg.Wait()
+	for _, result := range(results) {
+		assert.Equal(t, expected, result)
+	}
+}
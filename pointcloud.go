@@ -0,0 +1,173 @@
+package ConcaveHull
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPointCloud = errors.New("ConcaveHull: invalid point cloud")
+
+type PointCloudFormat int
+
+const (
+	PointCloudXYZ PointCloudFormat = iota // one "x y z" line per point, separated by spaces, tabs or commas
+	PointCloudPTS // XYZ lines after a line with the number of points, as written by Leica scanners
+	PointCloudPLY // ASCII PLY with x and y properties on the vertex element
+)
+
+// Points of a point cloud and, if every point has one, their Z. Computations sort Points in place, so the Z of hull vertices
+// is found with Elevations rather than by index
+type PointCloud struct {
+	Points FlatPoints
+	Z []float64
+}
+
+// Read an ASCII point cloud. Empty lines and, in XYZ and PTS files, lines starting with # or // are skipped, as are the columns
+// after Z
+func ReadPointCloud (r io.Reader, format PointCloudFormat) (*PointCloud, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64 * 1024), 1024 * 1024)
+	line := 0
+	cloud := &PointCloud{}
+	xColumn, yColumn, zColumn, count := 0, 1, 2, -1
+	if format == PointCloudPLY {
+		var err error
+		if xColumn, yColumn, zColumn, count, line, err = readPLYHeader(scanner); err != nil {
+			return nil, err
+		}
+	}
+	for count != 0 && scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || format != PointCloudPLY && (text[0] == '#' || strings.HasPrefix(text, "//")) {
+			continue
+		}
+		fields := strings.FieldsFunc(text, func (r rune) bool { return r == ' ' || r == '\t' || r == ',' || r == ';' })
+		if format == PointCloudPTS && len(fields) == 1 && cloud.Points == nil {
+			continue
+		}
+		if xColumn >= len(fields) || yColumn >= len(fields) {
+			return nil, fmt.Errorf("%w: line %d has %d fields", ErrInvalidPointCloud, line, len(fields))
+		}
+		x, err := strconv.ParseFloat(fields[xColumn], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidPointCloud, line, err)
+		}
+		y, err := strconv.ParseFloat(fields[yColumn], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidPointCloud, line, err)
+		}
+		cloud.Points = append(cloud.Points, x, y)
+		if zColumn >= 0 && zColumn < len(fields) {
+			z, err := strconv.ParseFloat(fields[zColumn], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidPointCloud, line, err)
+			}
+			cloud.Z = append(cloud.Z, z)
+		} else {
+			zColumn = -1
+		}
+		count--
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPointCloud, err)
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("%w: %d vertices missing", ErrInvalidPointCloud, count)
+	}
+	if len(cloud.Z) != cloud.Points.Len() {
+		cloud.Z = nil
+	}
+	return cloud, nil
+}
+
+// Columns of x, y and z, z being -1 if absent, in the vertex lines of an ASCII PLY file, the number of vertices and the last
+// line of the header. Elements declared before the vertices are not supported
+func readPLYHeader (scanner *bufio.Scanner) (xColumn, yColumn, zColumn, count, line int, err error) {
+	xColumn, yColumn, zColumn, count = -1, -1, -1, -1
+	column := 0
+	inVertex := false
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case line == 1:
+			if len(fields) != 1 || fields[0] != "ply" {
+				return 0, 0, 0, 0, line, ErrInvalidPointCloud
+			}
+		case len(fields) == 0 || fields[0] == "comment" || fields[0] == "obj_info":
+		case fields[0] == "format":
+			if len(fields) < 2 || fields[1] != "ascii" {
+				return 0, 0, 0, 0, line, fmt.Errorf("%w: only ASCII PLY is supported", ErrInvalidPointCloud)
+			}
+		case fields[0] == "element" && len(fields) == 3:
+			if count >= 0 {
+				inVertex = false
+				continue
+			}
+			if fields[1] != "vertex" {
+				return 0, 0, 0, 0, line, fmt.Errorf("%w: element %s before the vertices", ErrInvalidPointCloud, fields[1])
+			}
+			inVertex = true
+			if count, err = strconv.Atoi(fields[2]); err != nil || count < 0 {
+				return 0, 0, 0, 0, line, fmt.Errorf("%w: line %d: vertex count %s", ErrInvalidPointCloud, line, fields[2])
+			}
+		case fields[0] == "property" && len(fields) >= 3:
+			if !inVertex {
+				continue
+			}
+			if fields[1] == "list" {
+				return 0, 0, 0, 0, line, fmt.Errorf("%w: list property on vertices", ErrInvalidPointCloud)
+			}
+			switch fields[len(fields) - 1] {
+			case "x":
+				xColumn = column
+			case "y":
+				yColumn = column
+			case "z":
+				zColumn = column
+			}
+			column++
+		case fields[0] == "end_header":
+			if xColumn < 0 || yColumn < 0 {
+				return 0, 0, 0, 0, line, fmt.Errorf("%w: no x and y vertex properties", ErrInvalidPointCloud)
+			}
+			return xColumn, yColumn, zColumn, count, line, nil
+		default:
+			return 0, 0, 0, 0, line, fmt.Errorf("%w: line %d of header", ErrInvalidPointCloud, line)
+		}
+	}
+	return 0, 0, 0, 0, line, ErrInvalidPointCloud
+}
+
+// Z of each vertex of a hull of the cloud, for 2.5D footprints, found by the coordinates of the vertex so the order of Points
+// does not matter. Vertices that are not points of the cloud, such as those added by smoothing or buffering, get NaN, and
+// duplicate points with different Z get one of them
+func (pc *PointCloud) Elevations (hull FlatPoints) []float64 {
+	elevations := make([]float64, hull.Len())
+	if pc.Z == nil {
+		for i := range(elevations) {
+			elevations[i] = math.NaN()
+		}
+		return elevations
+	}
+	z := make(map[[2]float64]float64, len(pc.Z))
+	for i := len(pc.Z) - 1; i >= 0; i-- {
+		x, y := pc.Points.Take(i)
+		z[[2]float64{x, y}] = pc.Z[i]
+	}
+	for i := range(elevations) {
+		x, y := hull.Take(i)
+		if value, ok := z[[2]float64{x, y}]; ok {
+			elevations[i] = value
+		} else {
+			elevations[i] = math.NaN()
+		}
+	}
+	return elevations
+}
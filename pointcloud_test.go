@@ -0,0 +1,60 @@
+package ConcaveHull
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPointCloud (t *testing.T) {
+	for _, c := range([]struct {
+		format PointCloudFormat
+		text string
+	}{
+		{PointCloudXYZ, "# exported\n0 0 5\n1,0,6\n\n1\t1\t7 255 0 0\n0 1 8\n"},
+		{PointCloudPTS, "4\n0 0 5 -1200 10 20 30\n1 0 6 -1200 10 20 30\n1 1 7 -1200 10 20 30\n0 1 8 -1200 10 20 30\n"},
+		{PointCloudPLY, "ply\nformat ascii 1.0\ncomment scan\nelement vertex 4\nproperty uchar red\nproperty float x\n" +
+			"property float y\nproperty float z\nelement face 1\nproperty list uchar int vertex_indices\nend_header\n" +
+			"255 0 0 5\n0 1 0 6\n0 1 1 7\n0 0 1 8\n3 0 1 2\n"},
+	}) {
+		cloud, err := ReadPointCloud(strings.NewReader(c.text), c.format)
+		assert.Nil(t, err)
+		assert.Equal(t, FlatPoints{0, 0, 1, 0, 1, 1, 0, 1}, cloud.Points)
+		assert.Equal(t, []float64{5, 6, 7, 8}, cloud.Z)
+
+		hull := ComputeWithOptions(cloud.Points, &Options{Seglength: 1})
+		elevations := cloud.Elevations(hull)
+		for i := range(elevations) {
+			x, y := hull.Take(i)
+			assert.Equal(t, map[[2]float64]float64{{0, 0}: 5, {1, 0}: 6, {1, 1}: 7, {0, 1}: 8}[[2]float64{x, y}], elevations[i])
+		}
+	}
+}
+
+func TestReadPointCloud_withoutZ (t *testing.T) {
+	cloud, err := ReadPointCloud(strings.NewReader("0 0 1\n1 0\n1 1 2\n"), PointCloudXYZ)
+	assert.Nil(t, err)
+	assert.Equal(t, FlatPoints{0, 0, 1, 0, 1, 1}, cloud.Points)
+	assert.Nil(t, cloud.Z)
+	elevations := cloud.Elevations(FlatPoints{0, 0})
+	assert.True(t, math.IsNaN(elevations[0]))
+}
+
+func TestReadPointCloud_invalid (t *testing.T) {
+	for _, c := range([]struct {
+		format PointCloudFormat
+		text string
+	}{
+		{PointCloudXYZ, "0 0 0\n1\n"},
+		{PointCloudXYZ, "0 a 0\n"},
+		{PointCloudPLY, "ply\nformat binary_little_endian 1.0\nelement vertex 1\nproperty float x\nproperty float y\nend_header\n"},
+		{PointCloudPLY, "ply\nformat ascii 1.0\nelement vertex 2\nproperty float x\nproperty float y\nend_header\n0 0\n"},
+		{PointCloudPLY, "ply\nformat ascii 1.0\nelement vertex 2\nproperty float x\nproperty float z\nend_header\n0 0\n"},
+		{PointCloudPLY, "obj\n"},
+	}) {
+		_, err := ReadPointCloud(strings.NewReader(c.text), c.format)
+		assert.True(t, errors.Is(err, ErrInvalidPointCloud))
+	}
+}
@@ -0,0 +1,78 @@
+package ConcaveHull
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// Number of decimal digits kept by the encoded polyline format by default
+const DEFAULT_POLYLINE_PRECISION = 5
+
+var ErrInvalidPolyline = errors.New("ConcaveHull: invalid encoded polyline")
+
+// Encode longitude, latitude points, such as a hull, in Google's encoded polyline format, which stores latitude first.
+// Coordinates are rounded to precision decimal digits, DEFAULT_POLYLINE_PRECISION if it is 0
+func EncodePolyline (points FlatPoints, precision int) string {
+	factor := polylineFactor(precision)
+	var b strings.Builder
+	var lastLat, lastLon int64
+	for i := 0; i < points.Len(); i++ {
+		lon, lat := points.Take(i)
+		ilat, ilon := int64(math.Round(lat * factor)), int64(math.Round(lon * factor))
+		encodePolylineValue(&b, ilat - lastLat)
+		encodePolylineValue(&b, ilon - lastLon)
+		lastLat, lastLon = ilat, ilon
+	}
+	return b.String()
+}
+
+// Decode an encoded polyline into longitude, latitude points
+func DecodePolyline (encoded string, precision int) (FlatPoints, error) {
+	factor := polylineFactor(precision)
+	var points FlatPoints
+	var lat, lon int64
+	for i := 0; i < len(encoded); {
+		var deltas [2]int64
+		for k := range(deltas) {
+			var result int64
+			shift := uint(0)
+			for {
+				if i >= len(encoded) || encoded[i] < 63 || shift > 60 {
+					return nil, ErrInvalidPolyline
+				}
+				chunk := int64(encoded[i]) - 63
+				i++
+				result |= (chunk & 0x1f) << shift
+				shift += 5
+				if chunk < 0x20 {
+					break
+				}
+			}
+			deltas[k] = (result >> 1) ^ -(result & 1)
+		}
+		lat, lon = lat + deltas[0], lon + deltas[1]
+		points = append(points, float64(lon) / factor, float64(lat) / factor)
+	}
+	return points, nil
+}
+
+func polylineFactor (precision int) float64 {
+	if precision == 0 {
+		precision = DEFAULT_POLYLINE_PRECISION
+	}
+	return math.Pow(10, float64(precision))
+}
+
+func encodePolylineValue (b *strings.Builder, value int64) {
+	// zigzag, then 5 bit chunks from the lowest, with 0x20 marking that more chunks follow
+	v := uint64(value << 1)
+	if value < 0 {
+		v = ^v
+	}
+	for v >= 0x20 {
+		b.WriteByte(byte((0x20 | (v & 0x1f)) + 63))
+		v >>= 5
+	}
+	b.WriteByte(byte(v + 63))
+}
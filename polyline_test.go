@@ -0,0 +1,23 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodePolyline (t *testing.T) {
+	// example from Google's documentation, given as longitude, latitude
+	points := FlatPoints{-120.2, 38.5, -120.95, 40.7, -126.453, 43.252}
+	encoded := EncodePolyline(points, 0)
+	assert.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", encoded)
+	decoded, err := DecodePolyline(encoded, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, points, decoded)
+
+	decoded, err = DecodePolyline(EncodePolyline(points, 6), 6)
+	assert.NoError(t, err)
+	assert.Equal(t, points, decoded)
+
+	_, err = DecodePolyline("_p~iF~ps|U_ulL", 0)
+	assert.Equal(t, ErrInvalidPolyline, err)
+}
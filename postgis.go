@@ -0,0 +1,54 @@
+package ConcaveHull
+
+import (
+	"math"
+	"sort"
+	"github.com/furstenheim/go-convex-hull-2d"
+)
+
+// Number of hulls computed by STConcaveHull while looking for the target area
+const stConcaveHullIterations = 20
+
+// Equivalent of PostGIS ST_ConcaveHull(geom, target_percent, allow_holes) for a set of points.
+// As in PostGIS, targetPercent is the fraction of the area of the convex hull that the concave hull should approach,
+// 1 returns the convex hull and smaller values return more concave hulls. The seglength is searched for the largest value
+// whose hull area is at most the target, giving up with the most concave hull tried if the target cannot be reached.
+// If allowHoles is set, interior regions without points at the resolution of that seglength are returned as clockwise holes.
+// The input is not modified
+func STConcaveHull (points FlatPoints, targetPercent float64, allowHoles bool) (exterior FlatPoints, holes []FlatPoints) {
+	sorted := append(FlatPoints{}, points...)
+	sort.Sort(LexSorter(sorted))
+	convexHull := closeRing(go_convex_hull_2d.NewFromSortedArray(append(FlatPoints{}, sorted...)).(FlatPoints))
+	if convexHull.Len() < 4 || targetPercent >= 1 {
+		return convexHull, nil
+	}
+	targetArea := math.Max(targetPercent, 0) * SignedArea(convexHull)
+	diagonal := bboxDiagonal(sorted)
+	// the area increases with seglength, a larger one gives a more convex hull. Bisect in logarithmic scale for the largest
+	// seglength whose area is within the target
+	low, high := math.Log(diagonal * 1e-4), math.Log(diagonal * 0.1)
+	var best FlatPoints
+	var bestSeglength float64
+	for i := 0; i < stConcaveHullIterations; i++ {
+		seglength := math.Exp((low + high) / 2)
+		hull := ComputeFromSortedWithOptions(append(FlatPoints{}, sorted...), &Options{Seglength: seglength})
+		if SignedArea(hull) <= targetArea {
+			best, bestSeglength = hull, seglength
+			low = math.Log(seglength)
+		} else {
+			high = math.Log(seglength)
+		}
+	}
+	if best == nil {
+		bestSeglength = math.Exp(low)
+		best = ComputeFromSortedWithOptions(append(FlatPoints{}, sorted...), &Options{Seglength: bestSeglength})
+	}
+	if !allowHoles {
+		return best, nil
+	}
+	cellSize := math.Max(bestSeglength, diagonal / 512)
+	for _, void := range(Voids(sorted, best, cellSize, 4 * cellSize * cellSize)) {
+		holes = append(holes, reverseRing(void))
+	}
+	return best, holes
+}
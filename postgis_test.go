@@ -0,0 +1,31 @@
+package ConcaveHull
+
+import (
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestSTConcaveHull (t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	points := FlatPoints(hulltest.Clustered(r, 2000, 3, 0.05))
+	input := append(FlatPoints{}, points...)
+	convex, holes := STConcaveHull(points, 1, false)
+	assert.Nil(t, holes)
+	assert.Equal(t, input, points)
+	convexArea := SignedArea(convex)
+	concave, _ := STConcaveHull(points, 0.7, false)
+	area := SignedArea(concave)
+	assert.True(t, area <= 0.7 * convexArea, area / convexArea)
+	assert.True(t, area >= 0.5 * convexArea, area / convexArea)
+	hulltest.AssertValid(t, points, concave)
+}
+
+func TestSTConcaveHull_allowHoles (t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	points := FlatPoints(hulltest.Ring(r, 2000, 0.2, 0.5))
+	_, holes := STConcaveHull(points, 0.9, true)
+	assert.Len(t, holes, 1)
+	assert.True(t, SignedArea(holes[0]) < 0)
+}
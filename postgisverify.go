@@ -0,0 +1,98 @@
+package ConcaveHull
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidWKT = errors.New("ConcaveHull: invalid WKT polygon")
+
+// Differences between the exterior ring of STConcaveHull and that of PostGIS ST_ConcaveHull for the same points and parameters
+type PostGISDelta struct {
+	Area, ReferenceArea float64
+	// (Area - ReferenceArea) / ReferenceArea
+	RelativeAreaDelta float64
+	// Largest distance from a vertex of either ring to the boundary of the other, like ST_HausdorffDistance
+	Hausdorff float64
+}
+
+// Compare STConcaveHull with ST_ConcaveHull run by a PostGIS database, through a driver that takes $1 placeholders such as
+// lib/pq or pgx. Only exterior rings are compared
+func VerifyPostGIS (ctx context.Context, db *sql.DB, points FlatPoints, targetPercent float64, allowHoles bool) (PostGISDelta, error) {
+	var wkt string
+	err := db.QueryRowContext(ctx, "SELECT ST_AsText(ST_ConcaveHull(ST_GeomFromText($1), $2, $3))",
+		multiPointWKT(points), targetPercent, allowHoles).Scan(&wkt)
+	if err != nil {
+		return PostGISDelta{}, err
+	}
+	return VerifyPostGISFixture(points, targetPercent, allowHoles, wkt)
+}
+
+// Compare STConcaveHull with a stored result of ST_AsText(ST_ConcaveHull(...)) for the same points and parameters, a POLYGON
+func VerifyPostGISFixture (points FlatPoints, targetPercent float64, allowHoles bool, referenceWKT string) (PostGISDelta, error) {
+	reference, err := parseWKTPolygon(referenceWKT)
+	if err != nil {
+		return PostGISDelta{}, err
+	}
+	exterior, _ := STConcaveHull(points, targetPercent, allowHoles)
+	d := PostGISDelta{Area: math.Abs(SignedArea(exterior)), ReferenceArea: math.Abs(SignedArea(reference))}
+	if d.ReferenceArea > 0 {
+		d.RelativeAreaDelta = (d.Area - d.ReferenceArea) / d.ReferenceArea
+	}
+	a, b := Hull{Points: exterior}, Hull{Points: reference}
+	d.Hausdorff = math.Max(directedHausdorff(&a, &b), directedHausdorff(&b, &a))
+	return d, nil
+}
+
+// Largest distance from a vertex of a to the boundary of b
+func directedHausdorff (a, b *Hull) float64 {
+	farthest := 0.
+	for i := 0; i < a.Points.Len(); i++ {
+		_, _, _, d := b.Project(a.Points.Take(i))
+		farthest = math.Max(farthest, d)
+	}
+	return farthest
+}
+
+func multiPointWKT (points FlatPoints) string {
+	var b strings.Builder
+	b.WriteString("MULTIPOINT(")
+	writeWKTCoordinates(&b, points)
+	b.WriteByte(')')
+	return b.String()
+}
+
+// Exterior ring of a WKT POLYGON, holes are skipped
+func parseWKTPolygon (wkt string) (FlatPoints, error) {
+	text := strings.TrimSpace(wkt)
+	if len(text) < 7 || !strings.EqualFold(text[:7], "POLYGON") {
+		return nil, fmt.Errorf("%w: %.20s", ErrInvalidWKT, text)
+	}
+	text = strings.TrimSpace(text[7:])
+	if !strings.HasPrefix(text, "((") {
+		return nil, fmt.Errorf("%w: no ring", ErrInvalidWKT)
+	}
+	end := strings.IndexByte(text, ')')
+	if end < 0 {
+		return nil, fmt.Errorf("%w: unterminated ring", ErrInvalidWKT)
+	}
+	var ring FlatPoints
+	for _, pair := range(strings.Split(text[2:end], ",")) {
+		fields := strings.Fields(pair)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%w: point %q", ErrInvalidWKT, pair)
+		}
+		x, errX := strconv.ParseFloat(fields[0], 64)
+		y, errY := strconv.ParseFloat(fields[1], 64)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("%w: point %q", ErrInvalidWKT, pair)
+		}
+		ring = append(ring, x, y)
+	}
+	return ring, nil
+}
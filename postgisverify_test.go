@@ -0,0 +1,98 @@
+package ConcaveHull
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestVerifyPostGISFixture (t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	points := FlatPoints(hulltest.Clustered(r, 500, 3, 0.05))
+	exterior, _ := STConcaveHull(points, 0.8, false)
+	same, err := VerifyPostGISFixture(points, 0.8, false, polygonWKT(exterior))
+	assert.NoError(t, err)
+	assert.InDelta(t, 0, same.RelativeAreaDelta, 1e-12)
+	assert.InDelta(t, 0, same.Hausdorff, 1e-12)
+
+	// the convex hull as the reference
+	convex, _ := STConcaveHull(points, 1, false)
+	delta, err := VerifyPostGISFixture(points, 0.8, false, polygonWKT(convex))
+	assert.NoError(t, err)
+	assert.True(t, delta.RelativeAreaDelta < 0, "%v", delta)
+	assert.True(t, delta.Hausdorff > 0)
+
+	_, err = VerifyPostGISFixture(points, 0.8, false, "LINESTRING(0 0,1 1)")
+	assert.True(t, errors.Is(err, ErrInvalidWKT))
+}
+
+func TestParseWKTPolygon (t *testing.T) {
+	ring, err := parseWKTPolygon("POLYGON((0 0,1 0,1 1,0 0),(0.1 0.1,0.2 0.1,0.2 0.2,0.1 0.1))")
+	assert.NoError(t, err)
+	assert.Equal(t, FlatPoints{0, 0, 1, 0, 1, 1, 0, 0}, ring)
+	_, err = parseWKTPolygon("POLYGON((0 0,1 x))")
+	assert.True(t, errors.Is(err, ErrInvalidWKT))
+	assert.Equal(t, "MULTIPOINT(0.5 -1,2 3)", multiPointWKT(FlatPoints{0.5, -1, 2, 3}))
+}
+
+func TestVerifyPostGIS (t *testing.T) {
+	db := sql.OpenDB(fixtureDriver{"POLYGON((0 0,1 0,1 1,0 1,0 0))"})
+	defer db.Close()
+	points := FlatPoints{0, 0, 1, 0, 1, 1, 0, 1, 0.5, 0.5}
+	delta, err := VerifyPostGIS(context.Background(), db, points, 0.99, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1., delta.ReferenceArea)
+}
+
+// Driver answering every query with one row of one value, checking the arguments of VerifyPostGIS
+type fixtureDriver struct {
+	value string
+}
+
+func (d fixtureDriver) Open (string) (driver.Conn, error) { return fixtureConn(d), nil }
+func (d fixtureDriver) Connect (context.Context) (driver.Conn, error) { return fixtureConn(d), nil }
+func (d fixtureDriver) Driver () driver.Driver { return d }
+
+type fixtureConn fixtureDriver
+
+func (c fixtureConn) Prepare (query string) (driver.Stmt, error) { return fixtureStmt(c), nil }
+func (c fixtureConn) Close () error { return nil }
+func (c fixtureConn) Begin () (driver.Tx, error) { return nil, errors.New("no transactions") }
+
+type fixtureStmt fixtureConn
+
+func (s fixtureStmt) Close () error { return nil }
+func (s fixtureStmt) NumInput () int { return 3 }
+func (s fixtureStmt) Exec ([]driver.Value) (driver.Result, error) { return nil, errors.New("no exec") }
+func (s fixtureStmt) Query (args []driver.Value) (driver.Rows, error) {
+	if wkt, ok := args[0].(string); !ok || wkt[:10] != "MULTIPOINT" {
+		return nil, errors.New("expected a MULTIPOINT")
+	}
+	return &fixtureRows{value: s.value}, nil
+}
+
+type fixtureRows struct {
+	value string
+	done bool
+}
+
+func (r *fixtureRows) Columns () []string { return []string{"st_astext"} }
+func (r *fixtureRows) Close () error { return nil }
+func (r *fixtureRows) Next (dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func polygonWKT (ring FlatPoints) string {
+	return "POLYGON((" + multiPointWKT(closeRing(ring))[len("MULTIPOINT("):] + ")"
+}
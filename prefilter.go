@@ -0,0 +1,236 @@
+package ConcaveHull
+
+import (
+	"math"
+	"sort"
+)
+
+const DEFAULT_OUTLIER_NEIGHBOURS = 4
+
+// How extreme points are ranked for trimming
+type TrimCriterion int
+
+const (
+	TrimByCentroidDistance TrimCriterion = iota // distance to the centroid of the points
+	TrimBySparsity // distance to the OutlierNeighbours-th nearest neighbour
+)
+
+// Which points take part in the computation, nil if all of them do
+func prefilter (points FlatPoints, o *Options) (keep []bool) {
+	if o == nil || points.Len() == 0 {
+		return nil
+	}
+	if o.DensityThreshold > 0 {
+		keep = densityKeep(points, o.DensityBandwidth, o.DensityThreshold)
+	}
+	if o.OutlierRejection > 0 {
+		k := DEFAULT_OUTLIER_NEIGHBOURS
+		if o.OutlierNeighbours > 0 {
+			k = o.OutlierNeighbours
+		}
+		keep = intersectKeep(keep, outlierKeep(points, k, o.OutlierRejection))
+	}
+	if o.TrimFraction > 0 {
+		var scores []float64
+		switch o.TrimBy {
+		case TrimBySparsity:
+			k := DEFAULT_OUTLIER_NEIGHBOURS
+			if o.OutlierNeighbours > 0 {
+				k = o.OutlierNeighbours
+			}
+			scores = kNearestDistances(points, k)
+		default:
+			scores = centroidDistances(points)
+		}
+		keep = intersectKeep(keep, trimKeep(scores, o.TrimFraction))
+	}
+	if o.PeelLayers > 0 {
+		keep = intersectKeep(keep, peelKeep(points, o.PeelLayers))
+	}
+	return keep
+}
+
+// Mark all points except the fraction with the highest scores. Ties are broken by index so the result is deterministic
+func trimKeep (scores []float64, fraction float64) []bool {
+	n := len(scores)
+	keep := make([]bool, n)
+	order := make([]int, n)
+	for i := range(order) {
+		order[i] = i
+		keep[i] = true
+	}
+	sort.SliceStable(order, func (i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	trimmed := int(math.Min(math.Floor(fraction * float64(n)), float64(n)))
+	for _, i := range(order[:trimmed]) {
+		keep[i] = false
+	}
+	return keep
+}
+
+func centroidDistances (points FlatPoints) []float64 {
+	n := points.Len()
+	var cx, cy float64
+	for i := 0; i < n; i++ {
+		x, y := points.Take(i)
+		cx, cy = cx + x, cy + y
+	}
+	cx, cy = cx / float64(n), cy / float64(n)
+	distances := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x, y := points.Take(i)
+		distances[i] = math.Hypot(x - cx, y - cy)
+	}
+	return distances
+}
+
+func intersectKeep (a, b []bool) []bool {
+	if a == nil {
+		return b
+	}
+	for i := range(a) {
+		a[i] = a[i] && b[i]
+	}
+	return a
+}
+
+func droppedIndices (keep []bool) []int {
+	var dropped []int
+	for i, k := range(keep) {
+		if !k {
+			dropped = append(dropped, i)
+		}
+	}
+	return dropped
+}
+
+// Copy of the points that are marked to be kept. Order is preserved, so sorted input stays sorted
+func filterPoints (points FlatPoints, keep []bool) FlatPoints {
+	filtered := make(FlatPoints, 0, len(points))
+	for i, k := range(keep) {
+		if k {
+			filtered = append(filtered, points[2 * i], points[2 * i + 1])
+		}
+	}
+	return filtered
+}
+
+// Mark the points whose distance to the k-th nearest neighbour is at most factor times the median of that distance
+func outlierKeep (points FlatPoints, k int, factor float64) []bool {
+	distances := kNearestDistances(points, k)
+	keep := make([]bool, points.Len())
+	sorted := append([]float64{}, distances...)
+	sort.Float64s(sorted)
+	maxDistance := factor * sorted[len(sorted) / 2]
+	for i, d := range(distances) {
+		keep[i] = d <= maxDistance
+	}
+	return keep
+}
+
+// Distance from each point to its k-th nearest neighbour, +Inf if there are not enough points.
+// Points are binned in a grid with about one point per cell, and rings of cells are visited until no closer point can be found.
+// The grid spans the robust extents of the points, points outside of them are clamped into the border cells, so that
+// a few outliers don't make the cells large and put most points in the same one
+func kNearestDistances (points FlatPoints, k int) []float64 {
+	n := points.Len()
+	distances := make([]float64, n)
+	if n == 0 {
+		return distances
+	}
+	minX, minY, maxX, maxY := robustExtents(points, kNearestExtentQuantile)
+	cellSize := math.Hypot(maxX - minX, maxY - minY) / math.Sqrt(float64(n))
+	if cellSize == 0 {
+		minX, minY, maxX, maxY = bbox(points)
+		cellSize = math.Hypot(maxX - minX, maxY - minY) / math.Sqrt(float64(n))
+	}
+	if cellSize == 0 {
+		// all points are the same
+		for i := range(distances) {
+			if n <= k {
+				distances[i] = math.Inf(1)
+			}
+		}
+		return distances
+	}
+	// clamping only brings cells closer, so points in ring r are still more than (r - 1) * cellSize away
+	maxCell := [2]int{int((maxX - minX) / cellSize), int((maxY - minY) / cellSize)}
+	cellOf := func (x, y float64) [2]int {
+		return [2]int{
+			int(math.Max(0, math.Min(float64(maxCell[0]), math.Floor((x - minX) / cellSize)))),
+			int(math.Max(0, math.Min(float64(maxCell[1]), math.Floor((y - minY) / cellSize)))),
+		}
+	}
+	cells := make(map[[2]int][]int)
+	for i := 0; i < n; i++ {
+		cell := cellOf(points.Take(i))
+		cells[cell] = append(cells[cell], i)
+	}
+	maxRing := maxCell[0]
+	if maxCell[1] > maxRing {
+		maxRing = maxCell[1]
+	}
+	nearest := make([]float64, 0, k + 1)
+	for i := 0; i < n; i++ {
+		x, y := points.Take(i)
+		cell := cellOf(x, y)
+		nearest = nearest[:0]
+		for ring := 0; ring <= maxRing; ring++ {
+			for dx := -ring; dx <= ring; dx++ {
+				for dy := -ring; dy <= ring; dy++ {
+					if dx != -ring && dx != ring && dy != -ring && dy != ring {
+						continue
+					}
+					for _, j := range(cells[[2]int{cell[0] + dx, cell[1] + dy}]) {
+						if j == i {
+							continue
+						}
+						xj, yj := points.Take(j)
+						nearest = insertBounded(nearest, math.Hypot(x - xj, y - yj), k)
+					}
+				}
+			}
+			// points in further rings are at least ring * cellSize away
+			if len(nearest) == k && nearest[k - 1] <= float64(ring) * cellSize {
+				break
+			}
+		}
+		if len(nearest) < k {
+			distances[i] = math.Inf(1)
+		} else {
+			distances[i] = nearest[k - 1]
+		}
+	}
+	return distances
+}
+
+// Fraction of the points left out on each side of the extents of the kNearestDistances grid
+const kNearestExtentQuantile = 0.01
+
+// Extents of the points between the quantile and 1 - quantile of each coordinate
+func robustExtents (points FlatPoints, quantile float64) (minX, minY, maxX, maxY float64) {
+	n := points.Len()
+	xs, ys := make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		xs[i], ys[i] = points.Take(i)
+	}
+	sort.Float64s(xs)
+	sort.Float64s(ys)
+	low := int(quantile * float64(n - 1))
+	return xs[low], ys[low], xs[n - 1 - low], ys[n - 1 - low]
+}
+
+// Insert d in the sorted slice keeping at most k values
+func insertBounded (sorted []float64, d float64, k int) []float64 {
+	if len(sorted) == k && d >= sorted[k - 1] {
+		return sorted
+	}
+	i := sort.SearchFloat64s(sorted, d)
+	if len(sorted) < k {
+		sorted = append(sorted, 0)
+	}
+	copy(sorted[i + 1:], sorted[i:])
+	sorted[i] = d
+	return sorted
+}
@@ -0,0 +1,81 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestComputeContext_outlierRejection (t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	points := FlatPoints(append(hulltest.Random(r, 1000), 2, 2, -1, 0.5))
+	hull, err := ComputeContext(context.Background(), points, &Options{Seglength: 0.01, OutlierRejection: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, points.Len() - 1}, hull.Dropped)
+	x, y := points.Take(0)
+	assert.Equal(t, []float64{-1, 0.5}, []float64{x, y})
+	assert.False(t, ringContainsVertex(hull.Points, 2, 2))
+	assert.False(t, ringContainsVertex(hull.Points, -1, 0.5))
+}
+
+func TestKNearestDistances (t *testing.T) {
+	points := FlatPoints{0, 0, 1, 0, 3, 0, 10, 0}
+	assert.Equal(t, []float64{1, 1, 2, 7}, kNearestDistances(points, 1))
+	assert.Equal(t, []float64{3, 2, 3, 9}, kNearestDistances(points, 2))
+}
+
+func TestKNearestDistances_outliers (t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	points := FlatPoints(append(hulltest.Random(r, 500), 1e6, 1e6, -1e5, 3, 0.5, 1e7))
+	n := points.Len()
+	distances := kNearestDistances(points, 3)
+	for i := 0; i < n; i++ {
+		x, y := points.Take(i)
+		var all []float64
+		for j := 0; j < n; j++ {
+			if j != i {
+				xj, yj := points.Take(j)
+				all = append(all, math.Hypot(x - xj, y - yj))
+			}
+		}
+		sort.Float64s(all)
+		assert.Equal(t, all[2], distances[i])
+	}
+}
+
+func Benchmark_trimBySparsity (b *testing.B) {
+	r := rand.New(rand.NewSource(7))
+	uniform := hulltest.Random(r, 100000)
+	benchmarks := []struct {
+		name string
+		points FlatPoints
+	}{
+		{"uniform", FlatPoints(uniform)},
+		{"outliers", FlatPoints(append(append([]float64{}, uniform...), 1e6, 1e6, -1e6, 0.5, 0.5, -1e6))},
+	}
+	for _, bm := range(benchmarks) {
+		b.Run(bm.name, func (b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				prefilter(bm.points, &Options{TrimFraction: 0.01, TrimBy: TrimBySparsity})
+			}
+		})
+	}
+}
+
+func TestComputeContext_trimFraction (t *testing.T) {
+	points := FlatPoints{0, 0, 1, 0, 0, 1, 1, 1, 0.5, 0.5, 0.4, 0.6, 5, 5, 0.6, 0.4, 0.5, 0.6, 0.6, 0.5}
+	hull, err := ComputeContext(context.Background(), points, &Options{TrimFraction: 0.1})
+	assert.NoError(t, err)
+	assert.Len(t, hull.Dropped, 1)
+	x, y := points.Take(hull.Dropped[0])
+	assert.Equal(t, []float64{5, 5}, []float64{x, y})
+	hull, err = ComputeContext(context.Background(), points, &Options{TrimFraction: 0.1, TrimBy: TrimBySparsity, OutlierNeighbours: 1})
+	assert.NoError(t, err)
+	assert.Len(t, hull.Dropped, 1)
+	x, y = points.Take(hull.Dropped[0])
+	assert.Equal(t, []float64{5, 5}, []float64{x, y})
+}
@@ -0,0 +1,198 @@
+package ConcaveHull
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+	"github.com/furstenheim/SimpleRTree"
+	"github.com/furstenheim/go-convex-hull-2d"
+)
+
+// Points prepared to compute several hulls with different options. Sorting, convex hull and spatial index are computed once.
+// A Concaver is safe for concurrent use
+type Concaver struct {
+	sorted FlatPoints
+	// index in the input of each sorted point, nil if the points were given sorted
+	order []int
+	convexHull FlatPoints
+	rtree *SimpleRTree.SimpleRTree
+	// grid of the sorted points searched by ComputeSubset, built on first use
+	subsetOnce sync.Once
+	subsetIndex *categoryIndex
+}
+
+// Prepare a copy of the points
+func Prepare (points FlatPoints) *Concaver {
+	order := make([]int, points.Len())
+	for i := range(order) {
+		order[i] = i
+	}
+	sort.SliceStable(order, func (i, j int) bool {
+		xi, yi := points.Take(order[i])
+		xj, yj := points.Take(order[j])
+		return xi < xj || xi == xj && yi < yj
+	})
+	sorted := make(FlatPoints, 0, len(points))
+	for _, i := range(order) {
+		sorted = append(sorted, points[2 * i], points[2 * i + 1])
+	}
+	p := prepareSorted(sorted)
+	p.order = order
+	return p
+}
+
+// The Concaver takes ownership of sorted
+func prepareSorted (sorted FlatPoints) *Concaver {
+	p := &Concaver{sorted: sorted}
+	p.convexHull = go_convex_hull_2d.NewFromSortedArray(append(FlatPoints{}, sorted...)).(FlatPoints)
+	p.buildIndex()
+	return p
+}
+
+func (p *Concaver) buildIndex () {
+	// the index reorders its points, it gets its own copy
+	p.rtree = SimpleRTree.New()
+	p.rtree.LoadSortedArray(SimpleRTree.FlatPoints(append(FlatPoints{}, p.sorted...)))
+}
+
+// Concave hull of the prepared points
+func (p *Concaver) Compute (o *Options) FlatPoints {
+	hull, _ := p.ComputeContext(context.Background(), o)
+	return hull.Points
+}
+
+// Concave hull of the prepared points, see ComputeFromSortedContext.
+// Options that discard points, select another algorithm or a metric, or transform the coordinates can't use the prepared index, the hull is then computed from scratch
+func (p *Concaver) ComputeContext (ctx context.Context, o *Options) (hull Hull, err error) {
+	o = o.snapshot()
+	start := time.Now()
+	defer func () { observe(o, start, p.sorted.Len(), hull.Points, err) }()
+	defer recoverPanic(&err)
+	if err := ctx.Err(); err != nil {
+		return Hull{}, contextError(err)
+	}
+	return computePrepared(ctx, p.sorted, p.convexHull, p.rtree, o)
+}
+
+// Concave hull of the prepared points for which filter is true, for example those in a time range, as when slicing the
+// data interactively. filter gets the index of the point in the slice given to Prepare, or in sorted order for Concavers
+// prepared from sorted points. The subset is searched in a grid of all the prepared points, built on the first call and
+// shared by the following ones, skipping the points left out. Options are handled as by ComputeContext
+func (p *Concaver) ComputeSubset (ctx context.Context, filter func (i int) bool, o *Options) (hull Hull, err error) {
+	o = o.snapshot()
+	start := time.Now()
+	var subset FlatPoints
+	defer func () { observe(o, start, subset.Len(), hull.Points, err) }()
+	defer recoverPanic(&err)
+	if err := ctx.Err(); err != nil {
+		return Hull{}, contextError(err)
+	}
+	p.subsetOnce.Do(func () { p.subsetIndex = newCategoryIndex(p.sorted, nil) })
+	inputIndex := func (k int) int {
+		if p.order == nil {
+			return k
+		}
+		return p.order[k]
+	}
+	for k := 0; k < p.sorted.Len(); k++ {
+		if filter(inputIndex(k)) {
+			subset = append(subset, p.sorted[2 * k], p.sorted[2 * k + 1])
+		}
+	}
+	convexHull := go_convex_hull_2d.NewFromSortedArray(append(FlatPoints{}, subset...)).(FlatPoints)
+	index := subsetView{p.subsetIndex, func (k int) bool { return filter(inputIndex(k)) }}
+	return computePrepared(ctx, subset, convexHull, index, o)
+}
+
+// Hull of sorted points with their convex hull and index, falling back to a computation from scratch with options that
+// can't use them
+func computePrepared (ctx context.Context, sorted, convexHull FlatPoints, index SpatialIndex, o *Options) (Hull, error) {
+	if err := validateOptions(o); err != nil {
+		return Hull{}, err
+	}
+	if err := checkStrictInput(sorted, o); err != nil {
+		return Hull{}, err
+	}
+	if o != nil && (o.Algorithm != AlgorithmSnapHull || o.Metric != nil || o.ScaleX > 0 || o.ScaleY > 0 || o.Transform != nil || o.Projection != nil || o.GridSize > 0 || o.ClipInputBBox != nil || o.ExactArithmetic || prefilter(sorted, o) != nil) {
+		return finishHull(ctx, computeFromSortedWithContext(ctx, append(FlatPoints{}, sorted...), o), o)
+	}
+	var c concaver
+	c.configure(ctx, convexHull, o, time.Now())
+	c.rtree = index
+	c.inputs = sorted
+	if o != nil && o.Debug {
+		c.startDebug(convexHull)
+	}
+	c.flatPointBuffer = make([]float64, 0, 8 * convexHull.Len())
+	hull := Hull{Points: c.finishRing(c.computeFromSorted(convexHull), o), Partial: c.partial}
+	hull.Stats = c.stats
+	hull.Warnings = c.warnings
+	if c.debug {
+		c.checkRing("simplify", hull.Points)
+	}
+	hull.Provenance = c.provenance(hull.Points)
+	return finishHull(ctx, hull, o)
+}
+
+// Release the resources of the index, the Concaver can't be used afterwards
+func (p *Concaver) Close () {
+	p.rtree.Destroy()
+}
+
+var preparedMagic = [4]byte{'C', 'H', 'P', '2'}
+
+// Concavers serialized before the input order was kept
+var preparedMagicV1 = [4]byte{'C', 'H', 'P', '1'}
+
+var ErrInvalidPrepared = errors.New("ConcaveHull: invalid serialized Concaver")
+
+// Serialize the sorted points, the convex hull and the input order. The index is not serialized, it is rebuilt from the
+// sorted points, which is much cheaper than sorting
+func (p *Concaver) MarshalBinary () ([]byte, error) {
+	data := make([]byte, 0, 4 + 24 + 8 * (len(p.sorted) + len(p.convexHull) + len(p.order)))
+	data = append(data, preparedMagic[:]...)
+	data = appendFloats(data, p.sorted)
+	data = appendFloats(data, p.convexHull)
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(p.order)))
+	for _, i := range(p.order) {
+		data = binary.LittleEndian.AppendUint64(data, uint64(i))
+	}
+	return data, nil
+}
+
+// Restore a Concaver serialized with MarshalBinary. A Concaver can be restored again, its previous index is released
+func (p *Concaver) UnmarshalBinary (data []byte) error {
+	if len(data) < 4 || [4]byte(data[:4]) != preparedMagic && [4]byte(data[:4]) != preparedMagicV1 {
+		return ErrInvalidPrepared
+	}
+	r := binaryReader{data: data[4:]}
+	sorted := r.floats()
+	convexHull := r.floats()
+	var order []int
+	if [4]byte(data[:4]) == preparedMagic {
+		if n := r.length(8); n > 0 {
+			order = make([]int, n)
+			seen := make([]bool, n)
+			for k := range(order) {
+				i := r.uint64()
+				if i >= uint64(n) || seen[i] {
+					return ErrInvalidPrepared
+				}
+				order[k], seen[i] = int(i), true
+			}
+		}
+	}
+	if r.err != nil || len(r.data) != 0 || len(sorted) % 2 != 0 || len(convexHull) % 2 != 0 || order != nil && len(order) != len(sorted) / 2 {
+		return ErrInvalidPrepared
+	}
+	if p.rtree != nil {
+		p.rtree.Destroy()
+	}
+	p.sorted, p.convexHull, p.order = sorted, convexHull, order
+	p.subsetOnce, p.subsetIndex = sync.Once{}, nil
+	p.buildIndex()
+	return nil
+}
@@ -0,0 +1,92 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestConcaver (t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	points := hulltest.Ring(r, 1000, 0.3, 0.5)
+	prepared := Prepare(FlatPoints(points))
+	defer prepared.Close()
+	for _, seglength := range([]float64{0.005, 0.02}) {
+		expected := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: seglength})
+		assert.Equal(t, expected, prepared.Compute(&Options{Seglength: seglength}))
+	}
+
+	data, err := prepared.MarshalBinary()
+	assert.NoError(t, err)
+	var restored Concaver
+	assert.NoError(t, restored.UnmarshalBinary(data))
+	defer restored.Close()
+	assert.Equal(t, prepared.Compute(&Options{Seglength: 0.01}), restored.Compute(&Options{Seglength: 0.01}))
+	assert.Equal(t, ErrInvalidPrepared, restored.UnmarshalBinary(data[:len(data) - 1]))
+}
+
+func TestConcaver_ComputeSubset (t *testing.T) {
+	r := rand.New(rand.NewSource(12))
+	points := FlatPoints(hulltest.Clustered(r, 4000, 5, 0.1))
+	times := make([]int, points.Len())
+	for i := range(times) {
+		times[i] = r.Intn(24)
+	}
+	prepared := Prepare(points)
+	defer prepared.Close()
+	o := &Options{SeglengthRelative: 0.01}
+	for _, hours := range([][2]int{{0, 6}, {6, 18}, {0, 24}}) {
+		inRange := func (i int) bool { return times[i] >= hours[0] && times[i] < hours[1] }
+		var subset FlatPoints
+		for i := 0; i < points.Len(); i++ {
+			if inRange(i) {
+				subset = append(subset, points[2 * i], points[2 * i + 1])
+			}
+		}
+		expected, err := ComputeContext(context.Background(), subset, o)
+		assert.NoError(t, err)
+		hull, err := prepared.ComputeSubset(context.Background(), inRange, o)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.Points, hull.Points)
+	}
+
+	// the input order survives serialization
+	data, err := prepared.MarshalBinary()
+	assert.NoError(t, err)
+	var restored Concaver
+	assert.NoError(t, restored.UnmarshalBinary(data))
+	defer restored.Close()
+	early := func (i int) bool { return times[i] < 6 }
+	expected, _ := prepared.ComputeSubset(context.Background(), early, o)
+	hull, err := restored.ComputeSubset(context.Background(), early, o)
+	assert.NoError(t, err)
+	assert.Equal(t, expected.Points, hull.Points)
+
+	hull, err = prepared.ComputeSubset(context.Background(), func (int) bool { return false }, o)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, hull.Points.Len())
+}
+
+func TestConcaver_UnmarshalBinaryReuse (t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	first, second := Prepare(FlatPoints(hulltest.Random(r, 500))), Prepare(FlatPoints(hulltest.Clustered(r, 800, 3, 0.1)))
+	defer first.Close()
+	defer second.Close()
+	all := func (int) bool { return true }
+	o := &Options{Seglength: 0.02}
+	var restored Concaver
+	defer restored.Close()
+	for _, p := range([]*Concaver{first, second}) {
+		data, err := p.MarshalBinary()
+		assert.NoError(t, err)
+		assert.NoError(t, restored.UnmarshalBinary(data))
+		expected, _ := p.ComputeSubset(context.Background(), all, o)
+		// the grid of the subsets is built for each payload
+		hull, err := restored.ComputeSubset(context.Background(), all, o)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.Points, hull.Points)
+		assert.Equal(t, p.Compute(o), restored.Compute(o))
+	}
+}
@@ -0,0 +1,158 @@
+package ConcaveHull
+
+import "math"
+
+// Closest point to (x, y) on the boundary of the hull, the index of its edge, which goes from vertex edge to the next one,
+// and the distance, for example the distance of a point to the edge of coverage. The edges are indexed on the first call and
+// again whenever Points is replaced, so repeated queries don't scan the whole ring. Calls are safe for concurrent use once one
+// has returned. The distance is infinite for an empty hull
+func (h *Hull) Project (x, y float64) (px, py float64, edge int, distance float64) {
+	ring := openRing(h.Points)
+	if ring.Len() == 0 {
+		return 0, 0, 0, math.Inf(1)
+	}
+	px, py, edge, squared := h.edges(ring).closest(x, y)
+	return px, py, edge, math.Sqrt(squared)
+}
+
+// Index of the edges of the open ring of the hull, built if Points changed since the last call
+func (h *Hull) edges (ring FlatPoints) *edgeIndex {
+	if h.boundary == nil || !h.boundary.indexes(ring) {
+		h.boundary = newEdgeIndex(ring)
+	}
+	return h.boundary
+}
+
+// Uniform grid of the edges of an open ring, each listed in the cells overlapped by its bounding box
+type edgeIndex struct {
+	cellGrid
+	ring FlatPoints
+	// edges of cell i are edges[start[i]:start[i + 1]]
+	start []int
+	edges []int
+}
+
+func newEdgeIndex (ring FlatPoints) *edgeIndex {
+	n := ring.Len()
+	minX, minY, maxX, maxY := bbox(ring)
+	e := &edgeIndex{cellGrid: newCellGrid(minX, minY, maxX, maxY, math.Sqrt(float64(n))), ring: ring}
+	// counting sort of the edges by cell, an edge being counted in every cell of its bounding box
+	e.start = make([]int, e.nx * e.ny + 1)
+	forEachCell := func (i int, f func (c int)) {
+		x1, y1 := ring.Take(i)
+		x2, y2 := ring.Take((i + 1) % n)
+		ax, ay := e.cell(math.Min(x1, x2), math.Min(y1, y2))
+		bx, by := e.cell(math.Max(x1, x2), math.Max(y1, y2))
+		for cy := ay; cy <= by; cy++ {
+			for cx := ax; cx <= bx; cx++ {
+				f(cy * e.nx + cx)
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		forEachCell(i, func (c int) { e.start[c + 1]++ })
+	}
+	for i := 1; i < len(e.start); i++ {
+		e.start[i] += e.start[i - 1]
+	}
+	next := append([]int{}, e.start[:len(e.start) - 1]...)
+	e.edges = make([]int, e.start[len(e.start) - 1])
+	for i := 0; i < n; i++ {
+		forEachCell(i, func (c int) {
+			e.edges[next[c]] = i
+			next[c]++
+		})
+	}
+	return e
+}
+
+// Whether the index was built for this ring
+func (e *edgeIndex) indexes (ring FlatPoints) bool {
+	return len(e.ring) == len(ring) && &e.ring[0] == &ring[0]
+}
+
+// Closest point of the ring to (x, y), its edge and the squared distance. Rings of cells are visited around the cell of the
+// point, clamped to the grid, until no edge further away can be closer. For a point outside the grid, distances to the grid
+// are at least those from its clamped position, so the same bound holds. Ties go to the edge of lowest index
+func (e *edgeIndex) closest (x, y float64) (px, py float64, edge int, best float64) {
+	n := e.ring.Len()
+	best, edge = math.Inf(1), -1
+	cx, cy := e.cell(x, y)
+	maxRing := intMax(intMax(cx, e.nx - 1 - cx), intMax(cy, e.ny - 1 - cy))
+	for ring := 0; ring <= maxRing; ring++ {
+		for i := cx - ring; i <= cx + ring; i++ {
+			if i < 0 || i >= e.nx {
+				continue
+			}
+			for j := cy - ring; j <= cy + ring; j++ {
+				if j < 0 || j >= e.ny || (i != cx - ring && i != cx + ring && j != cy - ring && j != cy + ring) {
+					continue
+				}
+				c := j * e.nx + i
+				for _, k := range(e.edges[e.start[c]:e.start[c + 1]]) {
+					x1, y1 := e.ring.Take(k)
+					x2, y2 := e.ring.Take((k + 1) % n)
+					qx, qy, _ := projectOnSegment(x, y, x1, y1, x2, y2)
+					if d := squaredDistance(x, y, qx, qy); d < best || d == best && k < edge {
+						px, py, edge, best = qx, qy, k, d
+					}
+				}
+			}
+		}
+		// edges only in further rings are at least ring cells away
+		if limit := float64(ring) * e.cellSize; limit * limit > best {
+			break
+		}
+	}
+	return
+}
+
+// Whether the closed segment has a point in common with an edge of the ring
+func (e *edgeIndex) touchesSegment (x1, y1, x2, y2 float64) bool {
+	n := e.ring.Len()
+	minX, minY := math.Min(x1, x2), math.Min(y1, y2)
+	maxX, maxY := math.Max(x1, x2), math.Max(y1, y2)
+	if maxX < e.minX || maxY < e.minY || minX > e.minX + float64(e.nx) * e.cellSize || minY > e.minY + float64(e.ny) * e.cellSize {
+		return false
+	}
+	ax, ay := e.cell(minX, minY)
+	bx, by := e.cell(maxX, maxY)
+	for cy := ay; cy <= by; cy++ {
+		for cx := ax; cx <= bx; cx++ {
+			c := cy * e.nx + cx
+			for _, k := range(e.edges[e.start[c]:e.start[c + 1]]) {
+				ex1, ey1 := e.ring.Take(k)
+				ex2, ey2 := e.ring.Take((k + 1) % n)
+				if segmentsTouch(x1, y1, x2, y2, ex1, ey1, ex2, ey2) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Even odd rule like ringContains, counting only the edges in the cells to the right of (x, y). An edge spanning several of
+// those cells is counted in the leftmost one
+func (e *edgeIndex) contains (x, y float64) bool {
+	if y < e.minY || y > e.minY + float64(e.ny) * e.cellSize {
+		return false
+	}
+	n := e.ring.Len()
+	cx, cy := e.cell(x, y)
+	inside := false
+	for i := cx; i < e.nx; i++ {
+		c := cy * e.nx + i
+		for _, k := range(e.edges[e.start[c]:e.start[c + 1]]) {
+			x1, y1 := e.ring.Take(k)
+			x2, y2 := e.ring.Take((k + 1) % n)
+			if first, _ := e.cell(math.Min(x1, x2), y); i != intMax(first, cx) {
+				continue
+			}
+			if (y1 > y) != (y2 > y) && x < (x2 - x1) * (y - y1) / (y2 - y1) + x1 {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
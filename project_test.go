@@ -0,0 +1,48 @@
+package ConcaveHull
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestHull_Project (t *testing.T) {
+	hull := Hull{Points: FlatPoints{0, 0, 2, 0, 2, 2, 1, 1, 0, 2, 0, 0}}
+	px, py, edge, distance := hull.Project(1, -3)
+	assert.Equal(t, []float64{1, 0, 3}, []float64{px, py, distance})
+	assert.Equal(t, 0, edge)
+	px, py, edge, distance = hull.Project(1, 0.5)
+	assert.Equal(t, []float64{1, 0, 0.5}, []float64{px, py, distance})
+	assert.Equal(t, 0, edge)
+	px, py, edge, distance = hull.Project(1, 1.5)
+	assert.InDelta(t, math.Sqrt(0.125), distance, 1e-12)
+	assert.Equal(t, 2, edge)
+	px, py, edge, distance = hull.Project(10, 10)
+	assert.Equal(t, []float64{2, 2, math.Sqrt(128)}, []float64{px, py, distance})
+	assert.Equal(t, 1, edge)
+
+	// replacing the points rebuilds the index
+	hull.Points = FlatPoints{0, 0, 1, 0, 0, 1, 0, 0}
+	_, _, edge, distance = hull.Project(0, 3)
+	assert.Equal(t, 2., distance)
+	assert.Equal(t, 1, edge)
+
+	_, _, _, distance = (&Hull{}).Project(0, 0)
+	assert.True(t, math.IsInf(distance, 1))
+}
+
+func TestHull_Project_matchesScan (t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	hull := Hull{Points: ComputeWithOptions(hulltest.Random(r, 3000), &Options{Seglength: 0.02})}
+	ring := openRing(hull.Points)
+	for i := 0; i < 1000; i++ {
+		x, y := r.Float64() * 3 - 1, r.Float64() * 3 - 1
+		px, py, edge, distance := hull.Project(x, y)
+		qx, qy, _, squared := closestOnRing(ring, x, y)
+		assert.Equal(t, math.Sqrt(squared), distance)
+		assert.Equal(t, []float64{qx, qy}, []float64{px, py})
+		assert.True(t, edge >= 0 && edge < ring.Len())
+	}
+}
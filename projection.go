@@ -0,0 +1,14 @@
+package ConcaveHull
+
+// Conversion between the coordinate reference system of the points and a planar one, such as a PROJ transformation from
+// EPSG:4326 to a UTM zone. It must be defined for every input point. With github.com/twpayne/go-proj an adapter is:
+//
+//	type projAdapter struct{ pj *proj.PJ }
+//	func (a projAdapter) Forward (x, y float64) (float64, float64) { c, _ := a.pj.Forward(proj.NewCoord(x, y, 0, 0)); return c.X(), c.Y() }
+//	func (a projAdapter) Inverse (x, y float64) (float64, float64) { c, _ := a.pj.Inverse(proj.NewCoord(x, y, 0, 0)); return c.X(), c.Y() }
+type CoordinateTransformer interface {
+	// From the coordinates of the points to the planar ones
+	Forward (x, y float64) (float64, float64)
+	// From the planar coordinates back to those of the points
+	Inverse (x, y float64) (float64, float64)
+}
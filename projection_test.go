@@ -0,0 +1,45 @@
+package ConcaveHull
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+// Equirectangular projection in meters around a reference latitude
+type equirectangular struct {
+	latitude float64
+}
+
+func (e equirectangular) Forward (lon, lat float64) (float64, float64) {
+	return lon * math.Pi / 180 * earthRadius * math.Cos(e.latitude * math.Pi / 180), lat * math.Pi / 180 * earthRadius
+}
+
+func (e equirectangular) Inverse (x, y float64) (float64, float64) {
+	return x / (math.Pi / 180 * earthRadius * math.Cos(e.latitude * math.Pi / 180)), y / (math.Pi / 180 * earthRadius)
+}
+
+func TestComputeWithOptions_projection (t *testing.T) {
+	r := rand.New(rand.NewSource(20))
+	points := hulltest.Ring(r, 1000, 0.3, 0.5)
+	// a ring of radius about 50km at 60 degrees north, in degrees
+	for i := 0; i < len(points); i += 2 {
+		points[i], points[i + 1] = 10 + points[i] * 1.8, 60 + points[i + 1] * 0.9
+	}
+	input := append([]float64{}, points...)
+	hull := ComputeWithOptions(FlatPoints(points), &Options{Seglength: 2000, Projection: equirectangular{60}})
+	hulltest.AssertValid(t, input, hull)
+	// same as computing in meters
+	projected := make(FlatPoints, len(input))
+	for i := 0; i < len(input); i += 2 {
+		projected[i], projected[i + 1] = equirectangular{60}.Forward(input[i], input[i + 1])
+	}
+	expected := ComputeWithOptions(projected, &Options{Seglength: 2000})
+	assert.Equal(t, len(expected), len(hull))
+	for i := 0; i < len(hull); i += 2 {
+		x, y := equirectangular{60}.Forward(hull[i], hull[i + 1])
+		assert.Equal(t, []float64{expected[i], expected[i + 1]}, []float64{x, y})
+	}
+}
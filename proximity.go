@@ -0,0 +1,65 @@
+package ConcaveHull
+
+import "math"
+
+// Minimum distance between two hulls, 0 if they touch, overlap or one contains the other, infinite if either is empty.
+// The edges of both are indexed as by Hull.Project
+func MinDistance (a, b Hull) float64 {
+	ringA, ringB := openRing(a.Points), openRing(b.Points)
+	if ringA.Len() == 0 || ringB.Len() == 0 {
+		return math.Inf(1)
+	}
+	edgesA, edgesB := a.edges(ringA), b.edges(ringB)
+	n := ringA.Len()
+	for i := 0; i < n; i++ {
+		x1, y1 := ringA.Take(i)
+		x2, y2 := ringA.Take((i + 1) % n)
+		if edgesB.touchesSegment(x1, y1, x2, y2) {
+			return 0
+		}
+	}
+	// without crossing edges, the rings are either nested or apart
+	if ringContains(ringB, ringA[0], ringA[1]) || ringContains(ringA, ringB[0], ringB[1]) {
+		return 0
+	}
+	best := math.Inf(1)
+	for i := 0; i < ringA.Len(); i++ {
+		_, _, _, d := edgesB.closest(ringA.Take(i))
+		best = math.Min(best, d)
+	}
+	for i := 0; i < ringB.Len(); i++ {
+		_, _, _, d := edgesA.closest(ringB.Take(i))
+		best = math.Min(best, d)
+	}
+	return math.Sqrt(best)
+}
+
+// Whether the closed segment meets the hull, crossing or touching its boundary or lying inside it, for example to test whether
+// a transect crosses the surveyed area. The edges are indexed as by Hull.Project
+func (h *Hull) IntersectsSegment (x1, y1, x2, y2 float64) bool {
+	ring := openRing(h.Points)
+	if ring.Len() == 0 {
+		return false
+	}
+	return h.edges(ring).touchesSegment(x1, y1, x2, y2) || ringContains(ring, x1, y1)
+}
+
+// Whether the bounding box, boundary included, meets the hull
+func (h *Hull) IntersectsBBox (minX, minY, maxX, maxY float64) bool {
+	ring := openRing(h.Points)
+	if ring.Len() == 0 || minX > maxX || minY > maxY {
+		return false
+	}
+	hullMinX, hullMinY, hullMaxX, hullMaxY := bbox(ring)
+	if maxX < hullMinX || maxY < hullMinY || minX > hullMaxX || minY > hullMaxY {
+		return false
+	}
+	edges := h.edges(ring)
+	if edges.touchesSegment(minX, minY, maxX, minY) || edges.touchesSegment(maxX, minY, maxX, maxY) ||
+		edges.touchesSegment(maxX, maxY, minX, maxY) || edges.touchesSegment(minX, maxY, minX, minY) {
+		return true
+	}
+	// without crossing edges, the box contains the hull or the hull the box
+	x, y := ring.Take(0)
+	return minX <= x && x <= maxX && minY <= y && y <= maxY || ringContains(ring, minX, minY)
+}
@@ -0,0 +1,51 @@
+package ConcaveHull
+
+import (
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinDistance (t *testing.T) {
+	square := func (x, y, size float64) Hull {
+		return Hull{Points: FlatPoints{x, y, x + size, y, x + size, y + size, x, y + size, x, y}}
+	}
+	assert.Equal(t, 3., MinDistance(square(0, 0, 1), square(4, 0, 1)))
+	assert.Equal(t, math.Sqrt(2), MinDistance(square(0, 0, 1), square(2, 2, 1)))
+	// the closest point of b is inside an edge of a
+	assert.Equal(t, 0.5, MinDistance(square(0, 0, 4), Hull{Points: FlatPoints{2, 4.5, 3, 6, 1, 6}}))
+	// touching, overlapping and nested
+	assert.Equal(t, 0., MinDistance(square(0, 0, 1), square(1, 0, 1)))
+	assert.Equal(t, 0., MinDistance(square(0, 0, 2), square(1, 1, 2)))
+	assert.Equal(t, 0., MinDistance(square(0, 0, 4), square(1, 1, 1)))
+	assert.Equal(t, 0., MinDistance(square(1, 1, 1), square(0, 0, 4)))
+	// crossing without a vertex inside the other
+	assert.Equal(t, 0., MinDistance(Hull{Points: FlatPoints{0, 1, 3, 1, 3, 2, 0, 2}}, Hull{Points: FlatPoints{1, 0, 2, 0, 2, 3, 1, 3}}))
+	// the notch of a keeps b out
+	notched := Hull{Points: FlatPoints{0, 0, 4, 0, 4, 4, 2, 1, 0, 4}}
+	assert.InDelta(t, 1 / math.Sqrt(13), MinDistance(notched, Hull{Points: FlatPoints{2, 1.5, 2.5, 3, 1.5, 3}}), 1e-12)
+	assert.True(t, math.IsInf(MinDistance(Hull{}, square(0, 0, 1)), 1))
+}
+
+func TestHull_IntersectsSegment (t *testing.T) {
+	// square with a notch down to (2, 1)
+	hull := Hull{Points: FlatPoints{0, 0, 4, 0, 4, 4, 2, 1, 0, 4, 0, 0}}
+	assert.True(t, hull.IntersectsSegment(-1, 2, 5, 2))
+	assert.True(t, hull.IntersectsSegment(1, 1, 3, 0.5))
+	assert.True(t, hull.IntersectsSegment(4, 4, 6, 6))
+	assert.False(t, hull.IntersectsSegment(1, 3.5, 3, 3.5))
+	assert.False(t, hull.IntersectsSegment(5, -1, 5, 5))
+	assert.False(t, hull.IntersectsSegment(-10, -10, -20, -20))
+	assert.False(t, (&Hull{}).IntersectsSegment(0, 0, 1, 1))
+}
+
+func TestHull_IntersectsBBox (t *testing.T) {
+	hull := Hull{Points: FlatPoints{0, 0, 4, 0, 4, 4, 2, 1, 0, 4, 0, 0}}
+	assert.True(t, hull.IntersectsBBox(1, 1, 2, 2))
+	assert.True(t, hull.IntersectsBBox(0.5, 0.5, 1, 1))
+	assert.True(t, hull.IntersectsBBox(-1, -1, 5, 5))
+	assert.True(t, hull.IntersectsBBox(4, 4, 5, 5))
+	assert.False(t, hull.IntersectsBBox(1.8, 3, 2.2, 3.5))
+	assert.False(t, hull.IntersectsBBox(5, 5, 6, 6))
+	assert.False(t, hull.IntersectsBBox(1, 1, 0, 0))
+}
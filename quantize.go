@@ -0,0 +1,36 @@
+package ConcaveHull
+
+import (
+	"math"
+	"sort"
+)
+
+// Copy of the points rounded to multiples of size, sorted lexicographically without duplicates, and for each of the points
+// the index of its rounded point in the copy
+func snapToGrid (points FlatPoints, size float64) (snapped FlatPoints, index []int) {
+	n := points.Len()
+	rounded := make(FlatPoints, len(points))
+	for i, v := range(points) {
+		rounded[i] = math.Round(v / size) * size
+	}
+	// rounding keeps the order of x but not of y for points merged on the same x
+	order := make([]int, n)
+	for i := range(order) {
+		order[i] = i
+	}
+	sort.SliceStable(order, func (i, j int) bool {
+		xi, yi := rounded.Take(order[i])
+		xj, yj := rounded.Take(order[j])
+		return xi < xj || xi == xj && yi < yj
+	})
+	snapped = make(FlatPoints, 0, len(points))
+	index = make([]int, n)
+	for _, i := range(order) {
+		x, y := rounded.Take(i)
+		if k := snapped.Len(); k == 0 || snapped[2 * k - 2] != x || snapped[2 * k - 1] != y {
+			snapped = append(snapped, x, y)
+		}
+		index[i] = snapped.Len() - 1
+	}
+	return snapped, index
+}
@@ -0,0 +1,38 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestSnapToGrid (t *testing.T) {
+	// sorted before rounding, (0.1, 5) and (0.2, 1) swap once their x are merged
+	points := FlatPoints{0.1, 5, 0.2, 1, 0.9, 1.1, 1.1, 0.9, 1.2, 1.2}
+	snapped, index := snapToGrid(points, 1)
+	assert.Equal(t, FlatPoints{0, 1, 0, 5, 1, 1}, snapped)
+	assert.Equal(t, []int{1, 0, 2, 2, 2}, index)
+	assert.NoError(t, CheckSorted(snapped))
+}
+
+func TestComputeContext_gridSize (t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	points := FlatPoints(hulltest.Random(r, 2000))
+	hull, err := ComputeContext(context.Background(), points, &Options{Seglength: 0.05, GridSize: 0.01})
+	assert.NoError(t, err)
+	for i := 0; i < len(hull.Points); i++ {
+		assert.InDelta(t, 0, math.Remainder(hull.Points[i] * 100, 1), 1e-9)
+	}
+
+	// the outlier stands for the two input points merged into it
+	points = FlatPoints(append(hulltest.Random(r, 1000), 3, 3, 3.001, 3))
+	hull, err = ComputeContext(context.Background(), points, &Options{Seglength: 0.05, GridSize: 0.01, OutlierRejection: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{points.Len() - 2, points.Len() - 1}, hull.Dropped)
+
+	_, err = ComputeContext(context.Background(), points, &Options{GridSize: -1})
+	assert.Error(t, err)
+}
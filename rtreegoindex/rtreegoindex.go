@@ -0,0 +1,51 @@
+// Package rtreegoindex searches the closest points of a ConcaveHull computation with github.com/dhconnelly/rtreego instead
+// of SimpleRTree:
+//
+//	hull := ConcaveHull.ComputeWithOptions(points, &ConcaveHull.Options{SpatialIndex: rtreegoindex.New})
+package rtreegoindex
+
+import (
+	"github.com/USACE/concavehull"
+	"github.com/dhconnelly/rtreego"
+)
+
+// Children of a node of the tree
+const (
+	MIN_CHILDREN = 25
+	MAX_CHILDREN = 50
+)
+
+type point struct {
+	x, y float64
+}
+
+// Points have empty bounds, so the distance to the bounds of a leaf is the distance to the point
+func (p point) Bounds () rtreego.Rect {
+	return rtreego.Point{p.x, p.y}.ToRect(0)
+}
+
+type index struct {
+	tree *rtreego.Rtree
+}
+
+// Bulk load the points in an rtreego tree, a ConcaveHull.SpatialIndexBuilder
+func New (points ConcaveHull.FlatPoints) ConcaveHull.SpatialIndex {
+	objects := make([]rtreego.Spatial, points.Len())
+	for i := range(objects) {
+		x, y := points.Take(i)
+		objects[i] = point{x, y}
+	}
+	return index{tree: rtreego.NewTree(2, MIN_CHILDREN, MAX_CHILDREN, objects...)}
+}
+
+func (i index) FindNearestPointWithin (x, y, maxSquaredDistance float64) (px, py, squaredDistance float64, found bool) {
+	nearest, ok := i.tree.NearestNeighbor(rtreego.Point{x, y}).(point)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	squaredDistance = (nearest.x - x) * (nearest.x - x) + (nearest.y - y) * (nearest.y - y)
+	if squaredDistance > maxSquaredDistance {
+		return 0, 0, 0, false
+	}
+	return nearest.x, nearest.y, squaredDistance, true
+}
@@ -0,0 +1,30 @@
+package rtreegoindex
+
+import (
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestNew (t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := hulltest.Clustered(r, 1000, 3, 0.05)
+	expected := ConcaveHull.ComputeWithOptions(ConcaveHull.FlatPoints(append([]float64{}, points...)), &ConcaveHull.Options{Seglength: 0.01})
+	hull := ConcaveHull.ComputeWithOptions(ConcaveHull.FlatPoints(points), &ConcaveHull.Options{Seglength: 0.01, SpatialIndex: New})
+	assert.Equal(t, expected, hull)
+	hulltest.AssertValid(t, points, hull)
+}
+
+func TestIndex_FindNearestPointWithin (t *testing.T) {
+	i := New(ConcaveHull.FlatPoints{0, 0, 1, 0, 5, 5})
+	x, y, d, found := i.FindNearestPointWithin(0.9, 0.1, 1)
+	assert.True(t, found)
+	assert.Equal(t, []float64{1, 0}, []float64{x, y})
+	assert.InDelta(t, 0.02, d, 1e-12)
+	_, _, _, found = i.FindNearestPointWithin(3, 3, 1)
+	assert.False(t, found)
+	_, _, _, found = New(nil).FindNearestPointWithin(0, 0, 1)
+	assert.False(t, found)
+}
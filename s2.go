@@ -0,0 +1,150 @@
+package ConcaveHull
+
+import (
+	"math"
+	"sort"
+)
+
+const s2MaxLevel = 30
+
+// Hilbert curve position of each (i, j) quadrant for the four orientations, and the orientation change after each position
+var s2IJToPos = [4][4]uint64{{0, 1, 3, 2}, {0, 3, 1, 2}, {2, 3, 1, 0}, {2, 1, 3, 0}}
+var s2PosToOrientation = [4]int{1, 0, 0, 3}
+
+// S2 cell union approximating the hull in the manner of S2's RegionCoverer: cells between minLevel and maxLevel,
+// and at most maxCells of them if it is positive, although cells at minLevel are never merged. Points are longitude,
+// latitude in degrees and edges are geodesics. The hull must fit in a hemisphere around the center of each cube face it touches,
+// that is, be smaller than about 90 degrees. Cell ids are sorted
+func (h Hull) S2Cover (minLevel, maxLevel, maxCells int) []uint64 {
+	ring := closeRing(h.Points)
+	if ring.Len() < 4 {
+		return nil
+	}
+	if maxLevel > s2MaxLevel {
+		maxLevel = s2MaxLevel
+	}
+	type candidate struct {
+		face, level int
+		i, j uint64
+		ring FlatPoints
+	}
+	var queue []candidate
+	for face := 0; face < 6; face++ {
+		if projected, ok := s2FaceProjection(ring, face); ok {
+			queue = append(queue, candidate{face: face, ring: projected})
+		}
+	}
+	var cells []uint64
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		intersects, contained := s2CellRelation(c.ring, c.level, c.i, c.j)
+		if !intersects {
+			continue
+		}
+		if (contained && c.level >= minLevel) || c.level == maxLevel {
+			cells = append(cells, s2CellID(c.face, c.level, c.i, c.j))
+			continue
+		}
+		var children []candidate
+		for k := uint64(0); k < 4; k++ {
+			child := candidate{face: c.face, level: c.level + 1, i: 2 * c.i + k / 2, j: 2 * c.j + k % 2, ring: c.ring}
+			if intersects, _ := s2CellRelation(c.ring, child.level, child.i, child.j); intersects {
+				children = append(children, child)
+			}
+		}
+		if c.level >= minLevel && maxCells > 0 && len(cells) + len(queue) + len(children) > maxCells {
+			cells = append(cells, s2CellID(c.face, c.level, c.i, c.j))
+			continue
+		}
+		queue = append(queue, children...)
+	}
+	sort.Slice(cells, func (i, j int) bool { return cells[i] < cells[j] })
+	return cells
+}
+
+// Gnomonic projection of the ring on the plane of a cube face, in which geodesics are straight lines and the cells of the face are
+// rectangles. ok is false if some point is not in the hemisphere of the face
+func s2FaceProjection (ring FlatPoints, face int) (projected FlatPoints, ok bool) {
+	projected = make(FlatPoints, 0, len(ring))
+	for i := 0; i < ring.Len(); i++ {
+		lng, lat := ring.Take(i)
+		u, v, ok := s2FaceUV(face, lng, lat)
+		if !ok {
+			return nil, false
+		}
+		projected = append(projected, u, v)
+	}
+	return projected, true
+}
+
+func s2FaceUV (face int, lng, lat float64) (u, v float64, ok bool) {
+	sinLat, cosLat := math.Sincos(lat * math.Pi / 180)
+	sinLng, cosLng := math.Sincos(lng * math.Pi / 180)
+	x, y, z := cosLat * cosLng, cosLat * sinLng, sinLat
+	switch face {
+	case 0:
+		return y / x, z / x, x > 0
+	case 1:
+		return -x / y, z / y, y > 0
+	case 2:
+		return -x / z, -y / z, z > 0
+	case 3:
+		return z / x, y / x, x < 0
+	case 4:
+		return z / y, -x / y, y < 0
+	default:
+		return -y / z, -x / z, z < 0
+	}
+}
+
+// Relation between the projected ring and the cell (i, j) of the given level
+func s2CellRelation (projected FlatPoints, level int, i, j uint64) (intersects, contained bool) {
+	size := math.Ldexp(1, -level)
+	u0, u1 := s2STToUV(float64(i) * size), s2STToUV(float64(i + 1) * size)
+	v0, v1 := s2STToUV(float64(j) * size), s2STToUV(float64(j + 1) * size)
+	return rectRelation(projected, u0, v0, u1, v1)
+}
+
+// S2's quadratic transform from cell space to face coordinates
+func s2STToUV (s float64) float64 {
+	if s >= 0.5 {
+		return (4 * s * s - 1) / 3
+	}
+	return (1 - 4 * (1 - s) * (1 - s)) / 3
+}
+
+// Id of the cell (i, j) at the given level of a face: the face in the top 3 bits, then the position along the Hilbert curve
+// and a trailing 1 bit that encodes the level
+func s2CellID (face, level int, i, j uint64) uint64 {
+	orientation := face & 1
+	var pos uint64
+	for k := level - 1; k >= 0; k-- {
+		ij := (i >> uint(k) & 1) << 1 | j >> uint(k) & 1
+		p := s2IJToPos[orientation][ij]
+		pos = pos << 2 | p
+		orientation ^= s2PosToOrientation[p]
+	}
+	return uint64(face) << 61 | pos << uint(61 - 2 * level) | 1 << uint(60 - 2 * level)
+}
+
+// Leaf cell containing a longitude, latitude point
+func s2LeafCellID (lng, lat float64) uint64 {
+	for face := 0; face < 6; face++ {
+		u, v, ok := s2FaceUV(face, lng, lat)
+		if ok && math.Abs(u) <= 1 && math.Abs(v) <= 1 {
+			return s2CellID(face, s2MaxLevel, s2UVToIJ(u), s2UVToIJ(v))
+		}
+	}
+	return 0
+}
+
+func s2UVToIJ (u float64) uint64 {
+	var s float64
+	if u >= 0 {
+		s = 0.5 * math.Sqrt(1 + 3 * u)
+	} else {
+		s = 1 - 0.5 * math.Sqrt(1 - 3 * u)
+	}
+	return uint64(math.Max(0, math.Min(1 << s2MaxLevel - 1, math.Floor(s * (1 << s2MaxLevel)))))
+}
@@ -0,0 +1,40 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS2CellID (t *testing.T) {
+	assert.Equal(t, uint64(0x1000000000000001), s2LeafCellID(0, 0))
+	// face cells
+	assert.Equal(t, uint64(0x1000000000000000), s2CellID(0, 0, 0, 0))
+	assert.Equal(t, uint64(0xb000000000000000), s2CellID(5, 0, 0, 0))
+}
+
+// Whether the cell contains the leaf cell, that is, the leaf is in the range of ids of the descendants of the cell
+func s2Contains (cell, leaf uint64) bool {
+	lsb := cell & -cell
+	return cell - (lsb - 1) <= leaf && leaf <= cell + (lsb - 1)
+}
+
+func TestHull_S2Cover (t *testing.T) {
+	h := Hull{Points: FlatPoints{2, 48, 3, 48, 3, 49, 2, 49, 2, 48}}
+	cells := h.S2Cover(4, 12, 20)
+	assert.True(t, len(cells) > 0 && len(cells) <= 20)
+	covered := func (lng, lat float64) bool {
+		leaf := s2LeafCellID(lng, lat)
+		for _, c := range(cells) {
+			if s2Contains(c, leaf) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range([][2]float64{{2.5, 48.5}, {2.01, 48.01}, {2.99, 48.99}}) {
+		assert.True(t, covered(p[0], p[1]))
+	}
+	assert.False(t, covered(10, 48.5))
+	// without a cell budget the cover is finer
+	assert.True(t, len(h.S2Cover(4, 12, 0)) > len(cells))
+}
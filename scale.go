@@ -0,0 +1,47 @@
+package ConcaveHull
+
+import "sort"
+
+// Factors of Options.ScaleX and Options.ScaleY, ok is false if the coordinates are used as they are
+func axisScale (o *Options) (scaleX, scaleY float64, ok bool) {
+	if o == nil {
+		return 1, 1, false
+	}
+	scaleX, scaleY = o.ScaleX, o.ScaleY
+	if scaleX <= 0 {
+		scaleX = 1
+	}
+	if scaleY <= 0 {
+		scaleY = 1
+	}
+	return scaleX, scaleY, scaleX != 1 || scaleY != 1
+}
+
+// Scaled copy of the points. Positive factors keep the lexicographic order
+func scalePoints (points FlatPoints, scaleX, scaleY float64) FlatPoints {
+	scaled := make(FlatPoints, len(points))
+	for i := 0; i < len(points); i += 2 {
+		scaled[i], scaled[i + 1] = points[i] * scaleX, points[i + 1] * scaleY
+	}
+	return scaled
+}
+
+// Back to the original coordinates. Vertices that are scaled input points are looked up in the sorted input, so they are
+// returned exactly rather than with the rounding of the division
+func unscalePoints (hull, points FlatPoints, scaleX, scaleY float64) FlatPoints {
+	result := make(FlatPoints, len(hull))
+	n := points.Len()
+	for i := 0; i < len(hull); i += 2 {
+		x, y := hull[i], hull[i + 1]
+		j := sort.Search(n, func (j int) bool {
+			sx, sy := points[2 * j] * scaleX, points[2 * j + 1] * scaleY
+			return sx > x || (sx == x && sy >= y)
+		})
+		if j < n && points[2 * j] * scaleX == x && points[2 * j + 1] * scaleY == y {
+			result[i], result[i + 1] = points[2 * j], points[2 * j + 1]
+		} else {
+			result[i], result[i + 1] = x / scaleX, y / scaleY
+		}
+	}
+	return result
+}
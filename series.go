@@ -0,0 +1,73 @@
+package ConcaveHull
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Hull of the points of one time bucket of ComputeSeries
+type SeriesHull struct {
+	Hull
+	// Start of the bucket, which ends at Start plus the bucket duration
+	Start time.Time
+	// Indices of the points of the bucket in the input
+	Indices []int
+}
+
+// Compute one hull per time bucket of the points, times being aligned with points, such as daily or weekly coverage. Buckets
+// are aligned like time.Truncate, so daily buckets start at midnight UTC, and buckets without points are left out, the others
+// being in chronological order. The options are copied once and, without Options.ConcaveHullPool, the buckets share a pool of
+// buffers. With Options.Workers, that many hulls are computed concurrently. Unlike Compute, the input is not modified
+func ComputeSeries (points FlatPoints, times []time.Time, bucket time.Duration, o *Options) (hulls []SeriesHull, err error) {
+	defer recoverPanic(&err)
+	if err := validatePoints(points); err != nil {
+		return nil, err
+	}
+	if len(times) != points.Len() {
+		return nil, fmt.Errorf("%w: %d times for %d points", ErrMalformedPoints, len(times), points.Len())
+	}
+	if bucket <= 0 {
+		return nil, fmt.Errorf("%w: bucket is %v", ErrInvalidOptions, bucket)
+	}
+	if err := validateOptions(o); err != nil {
+		return nil, err
+	}
+	o = o.snapshot()
+	if o == nil {
+		o = &Options{}
+	}
+	if o.ConcaveHullPool == nil {
+		o.ConcaveHullPool = &sync.Pool{}
+	}
+	buckets := make(map[int64][]int)
+	var starts []int64
+	for i, t := range(times) {
+		start := t.Truncate(bucket).UnixNano()
+		if _, ok := buckets[start]; !ok {
+			starts = append(starts, start)
+		}
+		buckets[start] = append(buckets[start], i)
+	}
+	sort.Slice(starts, func (i, j int) bool { return starts[i] < starts[j] })
+	hulls = make([]SeriesHull, len(starts))
+	forEachConcurrently(len(hulls), o.Workers, func (k int) {
+		indices := buckets[starts[k]]
+		sort.Slice(indices, func (i, j int) bool {
+			xi, yi := points.Take(indices[i])
+			xj, yj := points.Take(indices[j])
+			return xi < xj || xi == xj && yi < yj
+		})
+		sorted := make(FlatPoints, 0, 2 * len(indices))
+		for _, i := range(indices) {
+			sorted = append(sorted, points[2 * i], points[2 * i + 1])
+		}
+		hull := computeFromSortedWithContext(nil, sorted, o)
+		for j, d := range(hull.Dropped) {
+			hull.Dropped[j] = indices[d]
+		}
+		hulls[k] = SeriesHull{Hull: hull, Start: time.Unix(0, starts[k]).In(times[indices[0]].Location()), Indices: indices}
+	})
+	return hulls, nil
+}
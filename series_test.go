@@ -0,0 +1,38 @@
+package ConcaveHull
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeSeries (t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	points := FlatPoints{
+		10, 10, 0, 0, 1, 0, 1, 1,
+		0, 1, 11, 10, 11, 11, 10, 11,
+	}
+	times := []time.Time{
+		day.Add(50 * time.Hour), day.Add(time.Hour), day.Add(2 * time.Hour), day.Add(3 * time.Hour),
+		day.Add(23 * time.Hour), day.Add(49 * time.Hour), day.Add(48 * time.Hour), day.Add(60 * time.Hour),
+	}
+	for _, workers := range([]int{0, 3}) {
+		series, err := ComputeSeries(points, times, 24 * time.Hour, &Options{Workers: workers})
+		assert.Nil(t, err)
+		assert.Len(t, series, 2)
+		assert.Equal(t, day, series[0].Start)
+		assert.Equal(t, []int{1, 4, 2, 3}, series[0].Indices)
+		assert.Equal(t, FlatPoints{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}, series[0].Points)
+		assert.Equal(t, day.Add(48 * time.Hour), series[1].Start)
+		assert.Equal(t, FlatPoints{10, 10, 11, 10, 11, 11, 10, 11, 10, 10}, series[1].Points)
+	}
+	assert.Equal(t, FlatPoints{10, 10, 0, 0}, points[:4])
+}
+
+func TestComputeSeries_invalid (t *testing.T) {
+	_, err := ComputeSeries(FlatPoints{0, 0}, nil, time.Hour, nil)
+	assert.True(t, errors.Is(err, ErrMalformedPoints))
+	_, err = ComputeSeries(FlatPoints{0, 0}, []time.Time{{}}, 0, nil)
+	assert.True(t, errors.Is(err, ErrInvalidOptions))
+}
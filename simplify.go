@@ -0,0 +1,159 @@
+package ConcaveHull
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// Constraints of the Douglas Peucker simplification of the boundary, see Options.PreserveVertices, Options.MinVertices and
+// Options.KeepInputsInside
+type simplifyConstraints struct {
+	preserve map[[2]float64]bool
+	minVertices int
+	keepInputsInside bool
+	inputs FlatPoints // sorted input points that must stay inside, nil if they may be excluded
+	metric Metric // distance of the vertices to their projection on the chords, in the units of the tolerance. Euclidean if nil
+}
+
+// Douglas Peucker simplification of a closed ring that honours the constraints. Spans of the ring are split at their farthest
+// vertex while it is farther than tolerance, or while replacing them with a chord would leave out an input point that was
+// next to the span. Then the spans with the farthest vertices are split until the ring has minVertices vertices
+func simplifyConstrained (ring FlatPoints, tolerance float64, constraints simplifyConstraints) FlatPoints {
+	n := ring.Len()
+	if n < 3 {
+		return append(FlatPoints{}, ring...)
+	}
+	keep := make([]bool, n)
+	keep[0], keep[n - 1] = true, true
+	for i := 1; i < n - 1; i++ {
+		x, y := ring.Take(i)
+		keep[i] = constraints.preserve[[2]float64{x, y}]
+	}
+	clockwise := SignedArea(ring) < 0
+	var stack, settled simplifySpans
+	for i, previous := 1, 0; i < n; i++ {
+		if keep[i] {
+			stack = append(stack, farthestInSpan(ring, previous, i, constraints.metric))
+			previous = i
+		}
+	}
+	for len(stack) > 0 {
+		span := stack[len(stack) - 1]
+		stack = stack[:len(stack) - 1]
+		if span.farthest < 0 {
+			continue
+		}
+		if span.distance <= tolerance && !excludesInput(ring, span.from, span.to, constraints.inputs, clockwise) {
+			settled = append(settled, span)
+			continue
+		}
+		keep[span.farthest] = true
+		stack = append(stack, farthestInSpan(ring, span.from, span.farthest, constraints.metric), farthestInSpan(ring, span.farthest, span.to, constraints.metric))
+	}
+	vertices := -1
+	for _, k := range(keep) {
+		if k {
+			vertices++
+		}
+	}
+	heap.Init(&settled)
+	for vertices < constraints.minVertices && settled.Len() > 0 {
+		span := heap.Pop(&settled).(simplifySpan)
+		if span.farthest < 0 {
+			continue
+		}
+		keep[span.farthest] = true
+		vertices++
+		heap.Push(&settled, farthestInSpan(ring, span.from, span.farthest, constraints.metric))
+		heap.Push(&settled, farthestInSpan(ring, span.farthest, span.to, constraints.metric))
+	}
+	simplified := make(FlatPoints, 0, 2 * (vertices + 1))
+	for i, k := range(keep) {
+		if k {
+			simplified = append(simplified, ring[2 * i], ring[2 * i + 1])
+		}
+	}
+	return simplified
+}
+
+// Vertices strictly between from and to are replaced by a chord unless the span is split at farthest, -1 if there are none
+type simplifySpan struct {
+	from, to, farthest int
+	distance float64
+}
+
+func farthestInSpan (ring FlatPoints, from, to int, metric Metric) simplifySpan {
+	if metric == nil {
+		metric = EuclideanMetric{}
+	}
+	span := simplifySpan{from: from, to: to, farthest: -1}
+	x1, y1 := ring.Take(from)
+	x2, y2 := ring.Take(to)
+	for k := from + 1; k < to; k++ {
+		x, y := ring.Take(k)
+		px, py, _ := projectOnSegment(x, y, x1, y1, x2, y2)
+		if d := metric.Distance(x, y, px, py); d > span.distance || span.farthest < 0 {
+			span.distance, span.farthest = d, k
+		}
+	}
+	return span
+}
+
+// Whether one of the vertices of the span, which are input points, or of the sorted inputs lies in the region that the chord
+// from vertex from to vertex to cuts off the ring
+func excludesInput (ring FlatPoints, from, to int, inputs FlatPoints, clockwise bool) bool {
+	if inputs == nil || to - from < 2 {
+		return false
+	}
+	x1, y1 := ring.Take(from)
+	x2, y2 := ring.Take(to)
+	if x1 == x2 && y1 == y2 {
+		// the whole ring would collapse
+		return true
+	}
+	// the interior is on the left of the edges of counter clockwise rings
+	outside := func (x, y float64) bool {
+		side := orientation(x1, y1, x2, y2, x, y)
+		return clockwise && side > 0 || !clockwise && side < 0
+	}
+	for k := from + 1; k < to; k++ {
+		if outside(ring.Take(k)) {
+			return true
+		}
+	}
+	region := ring[2 * from:2 * to + 2]
+	minX, minY, maxX, maxY := bbox(region)
+	for i := sort.Search(inputs.Len(), func (i int) bool { return inputs[2 * i] >= minX }); i < inputs.Len() && inputs[2 * i] <= maxX; i++ {
+		x, y := inputs.Take(i)
+		if y >= minY && y <= maxY && outside(x, y) && ringContains(region, x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// Max heap of spans by distance of their farthest vertex
+type simplifySpans []simplifySpan
+
+func (s simplifySpans) Len () int {
+	return len(s)
+}
+
+func (s simplifySpans) Less (i, j int) bool {
+	return s[i].distance > s[j].distance
+}
+
+func (s simplifySpans) Swap (i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s *simplifySpans) Push (x interface{}) {
+	*s = append(*s, x.(simplifySpan))
+}
+
+func (s *simplifySpans) Pop () interface{} {
+	old := *s
+	span := old[len(old) - 1]
+	*s = old[:len(old) - 1]
+	return span
+}
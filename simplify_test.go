@@ -0,0 +1,50 @@
+package ConcaveHull
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestSimplifyConstrained (t *testing.T) {
+	// counter clockwise square with a small outward bump at (2, -0.1) and a small dent at (2, 4 - 0.1)
+	ring := FlatPoints{0, 0, 1, 0, 2, -0.1, 3, 0, 4, 0, 4, 4, 3, 4, 2, 3.9, 1, 4, 0, 4, 0, 0}
+	assert.Equal(t, FlatPoints{0, 0, 4, 0, 4, 4, 0, 4, 0, 0}, simplifyConstrained(ring, 0.5, simplifyConstraints{}))
+	assert.Equal(t, simplify(ring, 0.5), simplifyConstrained(ring, 0.5, simplifyConstraints{}))
+
+	preserved := simplifyConstrained(ring, 0.5, simplifyConstraints{preserve: map[[2]float64]bool{{3, 4}: true}})
+	assert.Equal(t, FlatPoints{0, 0, 4, 0, 4, 4, 3, 4, 0, 4, 0, 0}, preserved)
+
+	atLeast := simplifyConstrained(ring, 0.5, simplifyConstraints{minVertices: 6})
+	assert.Equal(t, FlatPoints{0, 0, 2, -0.1, 4, 0, 4, 4, 2, 3.9, 0, 4, 0, 0}, atLeast)
+
+	// the bump is an input point that the chord would cut off, the dent may go. The point (1.5, -0.02) is in the bump too
+	inputs := FlatPoints{0, 0, 1, 0, 1.5, -0.02, 2, -0.1, 3, 0, 4, 0}
+	inside := simplifyConstrained(ring, 0.5, simplifyConstraints{keepInputsInside: true, inputs: inputs})
+	assert.Equal(t, FlatPoints{0, 0, 2, -0.1, 4, 0, 4, 4, 0, 4, 0, 0}, inside)
+}
+
+func TestComputeWithOptions_simplifyConstraints (t *testing.T) {
+	r := rand.New(rand.NewSource(24))
+	points := hulltest.Ring(r, 2000, 0.3, 0.5)
+	sorted := append(FlatPoints{}, points...)
+	sort.Sort(LexSorter(sorted))
+	plain := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.05})
+	hull := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.05, MinVertices: plain.Len() - 1 + 10})
+	assert.Equal(t, plain.Len() + 10, hull.Len())
+	hulltest.AssertValid(t, points, hull)
+
+	inside := ComputeWithOptions(FlatPoints(append([]float64{}, points...)), &Options{Seglength: 0.05, KeepInputsInside: true})
+	hulltest.AssertValid(t, points, inside)
+	outside := func (hull FlatPoints) (count int) {
+		for i := 0; i < sorted.Len(); i++ {
+			if x, y := sorted.Take(i); !ringContains(hull, x, y) && !containsPoint(openRing(hull), x, y) {
+				count++
+			}
+		}
+		return count
+	}
+	assert.True(t, outside(inside) < outside(plain))
+}
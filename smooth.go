@@ -0,0 +1,110 @@
+package ConcaveHull
+
+import (
+	"math"
+	"sort"
+)
+
+// Moving average of a closed ring along its arc length: each vertex is moved to the mean position of the boundary over the
+// stretch of length window centred on it, so long edges weigh more than short ones and the result doesn't depend on how
+// densely the boundary is sampled. This rounds corners more gently than Chaikin's corner cutting, for cartographic output at
+// small scales. Vertices at the positions of fixed, such as the endpoints of constraint edges, are kept in place, and the
+// stretch of the other vertices is narrowed so it never reaches past them. The window is capped at half the perimeter.
+// Smoothing moves the boundary inward where it is convex, so input points near it may end up outside
+func Smooth (ring FlatPoints, window float64, fixed FlatPoints) FlatPoints {
+	open := openRing(ring)
+	n := open.Len()
+	if n < 3 || !(window > 0) {
+		return ring
+	}
+	// coordinates relative to the first vertex keep the precision of rings far from the origin
+	x0, y0 := open.Take(0)
+	// arc length at each vertex and integral of the coordinates along the ring up to it
+	arc := make([]float64, n + 1)
+	sumX, sumY := make([]float64, n + 1), make([]float64, n + 1)
+	for i := 0; i < n; i++ {
+		x1, y1 := open.Take(i)
+		x2, y2 := open.Take((i + 1) % n)
+		length := math.Hypot(x2 - x1, y2 - y1)
+		arc[i + 1] = arc[i] + length
+		sumX[i + 1] = sumX[i] + length * ((x1 + x2) / 2 - x0)
+		sumY[i + 1] = sumY[i] + length * ((y1 + y2) / 2 - y0)
+	}
+	perimeter := arc[n]
+	if perimeter == 0 {
+		return ring
+	}
+	// integral of the coordinates from the first vertex to arc length s, which may be negative or beyond the perimeter
+	integral := func (s float64) (float64, float64) {
+		turns := math.Floor(s / perimeter)
+		s -= turns * perimeter
+		i := sort.SearchFloat64s(arc, s) - 1
+		if i < 0 {
+			i = 0
+		}
+		if i >= n {
+			i = n - 1
+		}
+		x1, y1 := open.Take(i)
+		x2, y2 := open.Take((i + 1) % n)
+		length, t := arc[i + 1] - arc[i], s - arc[i]
+		ix, iy := sumX[i] + t * (x1 - x0), sumY[i] + t * (y1 - y0)
+		if length > 0 {
+			ix += t * t / (2 * length) * (x2 - x1)
+			iy += t * t / (2 * length) * (y2 - y1)
+		}
+		return ix + turns * sumX[n], iy + turns * sumY[n]
+	}
+	isFixed := make([]bool, n)
+	var fixedArcs []float64
+	if fixed.Len() > 0 {
+		positions := make(map[[2]float64]bool, fixed.Len())
+		for i := 0; i < fixed.Len(); i++ {
+			x, y := fixed.Take(i)
+			positions[[2]float64{x, y}] = true
+		}
+		for i := 0; i < n; i++ {
+			x, y := open.Take(i)
+			if positions[[2]float64{x, y}] {
+				isFixed[i] = true
+				fixedArcs = append(fixedArcs, arc[i])
+			}
+		}
+	}
+	half := math.Min(window, perimeter / 2) / 2
+	smoothed := make(FlatPoints, 0, len(open) + 2)
+	for i := 0; i < n; i++ {
+		x, y := open.Take(i)
+		h := half
+		for _, s := range(fixedArcs) {
+			// distance along the ring to the fixed vertex, either way
+			d := math.Abs(s - arc[i])
+			h = math.Min(h, math.Min(d, perimeter - d))
+		}
+		if isFixed[i] || h == 0 {
+			smoothed = append(smoothed, x, y)
+			continue
+		}
+		ax, ay := integral(arc[i] - h)
+		bx, by := integral(arc[i] + h)
+		smoothed = append(smoothed, x0 + (bx - ax) / (2 * h), y0 + (by - ay) / (2 * h))
+	}
+	return closeRing(smoothed)
+}
+
+// Smooth the ring with Options.SmoothWindow, reporting the moved vertices as interpolated
+func (c * concaver) smooth (ring FlatPoints) FlatPoints {
+	if c.smoothWindow == 0 {
+		return ring
+	}
+	smoothed, open := Smooth(ring, c.smoothWindow, c.smoothFixed), openRing(ring)
+	if c.interpolated == nil {
+		c.interpolated = map[[2]float64]bool{}
+	}
+	for i := 0; i < openRing(smoothed).Len(); i++ {
+		if x, y := smoothed.Take(i); x != open[2 * i] || y != open[2 * i + 1] {
+			c.interpolated[[2]float64{x, y}] = true
+		}
+	}
+	return smoothed
+}
@@ -0,0 +1,61 @@
+package ConcaveHull
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestSmooth (t *testing.T) {
+	square := FlatPoints{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}
+	smoothed := Smooth(square, 1, nil)
+	assert.Equal(t, square.Len(), smoothed.Len())
+	assert.InDelta(t, 0.125, smoothed[0], 1e-12)
+	assert.InDelta(t, 0.125, smoothed[1], 1e-12)
+	assert.Equal(t, smoothed[:2], smoothed[len(smoothed) - 2:])
+
+	// the result doesn't depend on the sampling of the edges
+	dense := FlatPoints{0, 0, 1, 0, 2, 0, 2, 1, 2, 2, 1, 2, 0, 2, 0, 1, 0, 0}
+	smoothedDense := Smooth(dense, 1, nil)
+	assert.InDelta(t, 0.125, smoothedDense[0], 1e-12)
+	assert.InDelta(t, 1.875, smoothedDense[4], 1e-12)
+	assert.Equal(t, FlatPoints{1, 0}, smoothedDense[2:4])
+
+	// fixed vertices stay and the stretch of the others stops at them
+	smoothed = Smooth(square, 1, FlatPoints{0, 0})
+	assert.Equal(t, FlatPoints{0, 0}, smoothed[:2])
+	assert.InDelta(t, 1.875, smoothed[2], 1e-12)
+	smoothedDense = Smooth(dense, 3, FlatPoints{0, 0})
+	assert.Equal(t, FlatPoints{1, 0}, smoothedDense[2:4])
+	assert.InDelta(t, 1.625, smoothedDense[4], 1e-12)
+	assert.InDelta(t, 0.375, smoothedDense[5], 1e-12)
+
+	// far from the origin
+	far := FlatPoints{500000, 4649776, 500002, 4649776, 500002, 4649778, 500000, 4649778, 500000, 4649776}
+	smoothed = Smooth(far, 1, nil)
+	assert.Equal(t, FlatPoints{500000.125, 4649776.125}, smoothed[:2])
+}
+
+func TestComputeContext_smoothWindow (t *testing.T) {
+	points := FlatPoints(hulltest.Clustered(rand.New(rand.NewSource(8)), 2000, 4, 0.08))
+	plain, err := ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{SeglengthRelative: 0.01})
+	assert.NoError(t, err)
+	hull, err := ComputeContext(context.Background(), append(FlatPoints{}, points...), &Options{SeglengthRelative: 0.01, SmoothWindow: 0.05})
+	assert.NoError(t, err)
+	assert.Equal(t, plain.Points.Len(), hull.Points.Len())
+	assert.True(t, math.Abs(SignedArea(hull.Points)) < math.Abs(SignedArea(plain.Points)))
+	interpolated := 0
+	for _, kind := range(hull.Provenance) {
+		if kind == VertexInterpolated {
+			interpolated++
+		}
+	}
+	assert.True(t, interpolated > hull.Points.Len() / 2)
+
+	_, err = ComputeContext(context.Background(), points, &Options{SmoothWindow: -1})
+	assert.True(t, errors.Is(err, ErrInvalidOptions))
+}
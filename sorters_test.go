@@ -0,0 +1,31 @@
+package ConcaveHull
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestLexSorter (t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	points := FlatPoints(append(hulltest.Grid(r, 200, 5), hulltest.Duplicated(10, 0.5, 0.5)...))
+	assert.False(t, IsLexSorted(points))
+	sort.Sort(LexSorter(points))
+	assert.True(t, IsLexSorted(points))
+	assert.True(t, IsLexSorted(FlatPoints{0, 1, 0, 1, 0, 2, 1, 0}))
+	assert.False(t, IsLexSorted(FlatPoints{0, 2, 0, 1}))
+	assert.True(t, IsLexSorted(FlatPoints{}))
+}
+
+func Benchmark_LexSorter (b *testing.B) {
+	r := rand.New(rand.NewSource(28))
+	points := hulltest.Grid(r, 100000, 1000)
+	sorted := make(FlatPoints, len(points))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		copy(sorted, points)
+		sort.Sort(LexSorter(sorted))
+	}
+}
@@ -0,0 +1,82 @@
+package ConcaveHull
+
+import "github.com/USACE/concavehull/geomutil"
+
+// Split the hull at its narrow bridges: wherever two non adjacent vertices are closer than width and the segment joining them
+// lies inside the hull, the hull is cut along that segment. Pieces that are narrower than width everywhere, like the bridges
+// themselves, are discarded. Rings are closed and counter clockwise
+func SplitNarrow (ring FlatPoints, width float64) []FlatPoints {
+	ring = counterClockwise(ring)
+	if ring.Len() < 3 {
+		return nil
+	}
+	var parts []FlatPoints
+	pending := []FlatPoints{ring}
+	for len(pending) > 0 {
+		current := pending[len(pending) - 1]
+		pending = pending[:len(pending) - 1]
+		i, j, found := narrowestChord(current, width)
+		if !found {
+			if len(Erode(current, width / 2)) > 0 {
+				parts = append(parts, closeRing(current))
+			}
+			continue
+		}
+		first := append(FlatPoints{}, current[2 * i: 2 * j + 2]...)
+		second := append(append(FlatPoints{}, current[2 * j:]...), current[:2 * i + 2]...)
+		pending = append(pending, second, first)
+	}
+	return parts
+}
+
+// Shortest segment between two non adjacent vertices of an open counter clockwise ring, shorter than width, that splits the ring in two
+func narrowestChord (ring FlatPoints, width float64) (bestI, bestJ int, found bool) {
+	n := ring.Len()
+	best := width * width
+	for i := 0; i < n; i++ {
+		xi, yi := ring.Take(i)
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n - 1 {
+				continue
+			}
+			xj, yj := ring.Take(j)
+			d := (xi - xj) * (xi - xj) + (yi - yj) * (yi - yj)
+			if d >= best || !isInteriorChord(ring, i, j) {
+				continue
+			}
+			best, bestI, bestJ, found = d, i, j, true
+		}
+	}
+	return
+}
+
+// Whether the segment between vertices i and j lies inside the ring without touching its boundary
+func isInteriorChord (ring FlatPoints, i, j int) bool {
+	n := ring.Len()
+	xi, yi := ring.Take(i)
+	xj, yj := ring.Take(j)
+	if xi == xj && yi == yj {
+		// repeated vertex, cutting there is a valid split
+		return true
+	}
+	if !ringContains(ring, (xi + xj) / 2, (yi + yj) / 2) {
+		return false
+	}
+	for k := 0; k < n; k++ {
+		l := (k + 1) % n
+		if k == i || k == j || l == i || l == j {
+			continue
+		}
+		xk, yk := ring.Take(k)
+		xl, yl := ring.Take(l)
+		if segmentsTouch(xi, yi, xj, yj, xk, yk, xl, yl) {
+			return false
+		}
+	}
+	return true
+}
+
+// Whether two closed segments have at least one point in common
+func segmentsTouch (ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	return geomutil.SegmentsTouch(ax, ay, bx, by, cx, cy, dx, dy)
+}
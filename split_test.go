@@ -0,0 +1,20 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitNarrow (t *testing.T) {
+	// two 4x4 squares joined by a corridor 0.5 units wide
+	dumbbell := FlatPoints{0, 0, 4, 0, 4, 1.75, 6, 1.75, 6, 0, 10, 0, 10, 4, 6, 4, 6, 2.25, 4, 2.25, 4, 4, 0, 4, 0, 0}
+	parts := SplitNarrow(dumbbell, 1)
+	assert.Len(t, parts, 2)
+	assert.InDelta(t, 32, totalArea(parts), 1e-9)
+	// a wider threshold does not split anything
+	assert.Equal(t, []FlatPoints{dumbbell}, SplitNarrow(dumbbell, 0.25))
+	// one point wide bridge
+	pinched := FlatPoints{0, 0, 2, 0, 2, 2, 3, 1, 4, 0, 6, 0, 6, 3, 4, 3, 3, 1, 2, 3, 0, 3, 0, 0}
+	parts = SplitNarrow(pinched, 0.5)
+	assert.Len(t, parts, 2)
+}
@@ -0,0 +1,107 @@
+package ConcaveHull
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Encoding of the coordinates written by StreamFromSorted and Concaver.WriteBoundary
+type StreamFormat int
+
+const (
+	StreamBinary StreamFormat = iota // little endian float64 x and y, like FlatPoints.MarshalBinary
+	StreamText // one "x y" line per vertex
+)
+
+// Write the boundary of the hull of sorted points to w edge by edge, as the edges of the convex hull are refined, see Concaver.WriteBoundary.
+// The points are not modified
+func StreamFromSorted (w io.Writer, points FlatPoints, o *Options, format StreamFormat) (err error) {
+	defer recoverPanic(&err)
+	if err := validatePoints(points); err != nil {
+		return err
+	}
+	if o == nil || !o.SkipSortCheck {
+		if err := CheckSorted(points); err != nil {
+			return err
+		}
+	}
+	p := prepareSorted(points)
+	defer p.Close()
+	return p.WriteBoundary(w, o, format)
+}
+
+// Write the boundary of the hull to w edge by edge, as the edges of the convex hull are refined. The boundary is not simplified,
+// so it is the detailed ring that Douglas Peucker would reduce, and it is never held in memory as a whole.
+// Only the options of AlgorithmSnapHull that don't discard or transform points are supported, TimeBudget is ignored
+func (p *Concaver) WriteBoundary (w io.Writer, o *Options, format StreamFormat) (err error) {
+	o = o.snapshot()
+	defer recoverPanic(&err)
+	if err := validateOptions(o); err != nil {
+		return err
+	}
+	if o != nil && (o.Algorithm != AlgorithmSnapHull || o.ScaleX > 0 || o.ScaleY > 0 || o.Transform != nil || o.Projection != nil || o.GridSize > 0 || o.ClipInputBBox != nil || prefilter(p.sorted, o) != nil) {
+		return fmt.Errorf("%w: streaming doesn't support other algorithms, transformations or filtering", ErrInvalidOptions)
+	}
+	var c concaver
+	c.configure(nil, p.convexHull, o, time.Now())
+	c.deadline = time.Time{}
+	c.rtree = p.rtree
+	if c.metric != nil || c.exact {
+		c.grid = newGridIndex(p.sorted)
+	}
+	writer := newPointWriter(w, format)
+	if p.convexHull.Len() < 3 {
+		for i := 0; i < p.convexHull.Len(); i++ {
+			writer.write(p.convexHull.Take(i))
+		}
+		return writer.flush()
+	}
+	writer.write(p.convexHull.Take(0))
+	for i := 0; i < p.convexHull.Len() && writer.err == nil; i++ {
+		x1, y1, x2, y2 := convexHullEdge(p.convexHull, i)
+		c.segmentizeEach(x1, y1, x2, y2, func (_ int, x, y float64) {
+			writer.write(x, y)
+		})
+	}
+	return writer.flush()
+}
+
+// Buffered writer of coordinates that keeps the first error
+type pointWriter struct {
+	w *bufio.Writer
+	format StreamFormat
+	buffer []byte
+	err error
+}
+
+func newPointWriter (w io.Writer, format StreamFormat) *pointWriter {
+	return &pointWriter{w: bufio.NewWriter(w), format: format, buffer: make([]byte, 0, 64)}
+}
+
+func (pw *pointWriter) write (x, y float64) {
+	if pw.err != nil {
+		return
+	}
+	if pw.format == StreamText {
+		pw.buffer = strconv.AppendFloat(pw.buffer[:0], x, 'g', -1, 64)
+		pw.buffer = append(pw.buffer, ' ')
+		pw.buffer = strconv.AppendFloat(pw.buffer, y, 'g', -1, 64)
+		pw.buffer = append(pw.buffer, '\n')
+	} else {
+		pw.buffer = binary.LittleEndian.AppendUint64(pw.buffer[:0], math.Float64bits(x))
+		pw.buffer = binary.LittleEndian.AppendUint64(pw.buffer, math.Float64bits(y))
+	}
+	_, pw.err = pw.w.Write(pw.buffer)
+}
+
+func (pw *pointWriter) flush () error {
+	if pw.err != nil {
+		return pw.err
+	}
+	return pw.w.Flush()
+}
@@ -0,0 +1,54 @@
+package ConcaveHull
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestStreamFromSorted_matchesHull (t *testing.T) {
+	r := rand.New(rand.NewSource(12))
+	points := FlatPoints(hulltest.Random(r, 500))
+	sort.Sort(LexSorter(points))
+	var binaryOut, textOut bytes.Buffer
+	assert.Nil(t, StreamFromSorted(&binaryOut, points, &Options{Seglength: 0.02}, StreamBinary))
+	assert.Nil(t, StreamFromSorted(&textOut, points, &Options{Seglength: 0.02}, StreamText))
+	var boundary FlatPoints
+	assert.Nil(t, boundary.UnmarshalBinary(binaryOut.Bytes()))
+	var parsed FlatPoints
+	scanner := bufio.NewScanner(&textOut)
+	for scanner.Scan() {
+		for _, field := range(strings.Fields(scanner.Text())) {
+			v, err := strconv.ParseFloat(field, 64)
+			assert.Nil(t, err)
+			parsed = append(parsed, v)
+		}
+	}
+	assert.Equal(t, boundary, parsed)
+	// the hull is the simplified boundary
+	hull, err := ComputeFromSortedContext(context.Background(), points, &Options{Seglength: 0.02})
+	assert.Nil(t, err)
+	assert.Equal(t, hull.Points, simplify(boundary, 0.02))
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write ([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestStreamFromSorted_errors (t *testing.T) {
+	points := FlatPoints{0, 0, 0, 1, 1, 0, 1, 1}
+	assert.Equal(t, "disk full", StreamFromSorted(failingWriter{}, points, nil, StreamText).Error())
+	var unsorted *UnsortedError
+	assert.True(t, errors.As(StreamFromSorted(&bytes.Buffer{}, FlatPoints{1, 1, 0, 0}, nil, StreamText), &unsorted))
+	assert.True(t, errors.Is(StreamFromSorted(&bytes.Buffer{}, points, &Options{Transform: &Affine{A: 1, E: 1}}, StreamText), ErrInvalidOptions))
+}
@@ -0,0 +1,40 @@
+package ConcaveHull
+
+// Read only access to points stored in another layout than FlatPoints, see ComputeSource
+type PointSource interface {
+	Len () int
+	Take (i int) (x, y float64)
+}
+
+// Points embedded in wider interleaved records, such as x, y, z, t, value, ..., without extracting them: point i is
+// Data[i * Stride + OffsetX], Data[i * Stride + OffsetY]. A last record that is cut short after its y still counts
+type StridedPoints struct {
+	Data []float64
+	OffsetX, OffsetY, Stride int
+}
+
+func (s StridedPoints) Len () int {
+	last := s.OffsetX
+	if s.OffsetY > last {
+		last = s.OffsetY
+	}
+	if s.Stride <= 0 || len(s.Data) <= last {
+		return 0
+	}
+	return (len(s.Data) - last - 1) / s.Stride + 1
+}
+
+func (s StridedPoints) Take (i int) (x, y float64) {
+	return s.Data[i * s.Stride + s.OffsetX], s.Data[i * s.Stride + s.OffsetY]
+}
+
+// Concave hull of the points of a source. They are gathered once into the working array that the computation sorts, so the
+// source is left untouched, unlike with ComputeWithOptions, and no other copy is made
+func ComputeSource (source PointSource, o *Options) FlatPoints {
+	n := source.Len()
+	points := make(FlatPoints, 2 * n)
+	for i := 0; i < n; i++ {
+		points[2 * i], points[2 * i + 1] = source.Take(i)
+	}
+	return ComputeWithOptions(points, o)
+}
@@ -0,0 +1,37 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStridedPoints (t *testing.T) {
+	// x, y, z, t records, the last one without t
+	records := []float64{
+		1, 10, 100, 0,
+		2, 20, 200, 1,
+		3, 30, 300,
+	}
+	s := StridedPoints{Data: records, OffsetX: 0, OffsetY: 1, Stride: 4}
+	assert.Equal(t, 3, s.Len())
+	x, y := s.Take(2)
+	assert.Equal(t, []float64{3, 30}, []float64{x, y})
+	// y and z as coordinates
+	x, y = StridedPoints{Data: records, OffsetX: 1, OffsetY: 2, Stride: 4}.Take(1)
+	assert.Equal(t, []float64{20, 200}, []float64{x, y})
+	assert.Equal(t, 0, StridedPoints{Data: records[:1], OffsetY: 1, Stride: 4}.Len())
+	assert.Equal(t, 0, StridedPoints{Data: records}.Len())
+}
+
+func TestComputeSource (t *testing.T) {
+	flat := FlatPoints{1./3., 0.5, 0.0, 0.0, 1.0, 0.0, 0.0, 1.0, 1.0, 1.0}
+	var records []float64
+	for i := 0; i < flat.Len(); i++ {
+		x, y := flat.Take(i)
+		records = append(records, float64(i), y, -1, x)
+	}
+	original := append([]float64{}, records...)
+	hull := ComputeSource(StridedPoints{Data: records, OffsetX: 3, OffsetY: 1, Stride: 4}, nil)
+	assert.Equal(t, ComputeWithOptions(flat, nil), hull)
+	assert.Equal(t, original, records)
+}
@@ -0,0 +1,36 @@
+package ConcaveHull
+
+import "sort"
+
+// Slippy map tile
+type Tile struct {
+	Z, X, Y int
+}
+
+// Web Mercator tiles of zoom levels minZoom to maxZoom that intersect the hull, ordered by zoom, then row, then column.
+// Points are longitude, latitude in degrees, edges are taken as straight in Web Mercator
+func (h Hull) TileCover (minZoom, maxZoom int) []Tile {
+	ring := closeRing(h.Points)
+	world := make(FlatPoints, len(ring))
+	for i := 0; i < ring.Len(); i++ {
+		world[2 * i], world[2 * i + 1] = lonLatToWorld(ring.Take(i))
+	}
+	var tiles []Tile
+	for z := minZoom; z <= maxZoom; z++ {
+		n := 1 << uint(z)
+		size := 1 / float64(n)
+		for _, cell := range(gridCover(world, 0, 0, size, size, n, n, CoverIntersecting)) {
+			tiles = append(tiles, Tile{Z: z, X: cell[0], Y: cell[1]})
+		}
+	}
+	sort.SliceStable(tiles, func (i, j int) bool {
+		if tiles[i].Z != tiles[j].Z {
+			return tiles[i].Z < tiles[j].Z
+		}
+		if tiles[i].Y != tiles[j].Y {
+			return tiles[i].Y < tiles[j].Y
+		}
+		return tiles[i].X < tiles[j].X
+	})
+	return tiles
+}
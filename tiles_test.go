@@ -0,0 +1,23 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHull_TileCover (t *testing.T) {
+	// small rectangle east of Paris
+	h := Hull{Points: FlatPoints{2.3, 48.8, 2.6, 48.8, 2.6, 48.9, 2.3, 48.9, 2.3, 48.8}}
+	tiles := h.TileCover(0, 10)
+	assert.Equal(t, Tile{0, 0, 0}, tiles[0])
+	assert.Equal(t, Tile{1, 1, 0}, tiles[1])
+	// it spans x from 518.5 to 519.4 and y from 352.3 to 352.6 at z10
+	var z10 []Tile
+	for _, tile := range(tiles) {
+		if tile.Z == 10 {
+			z10 = append(z10, tile)
+		}
+	}
+	assert.Equal(t, []Tile{{10, 518, 352}, {10, 519, 352}}, z10)
+	assert.Equal(t, 0, len(Hull{}.TileCover(0, 3)))
+}
@@ -0,0 +1,48 @@
+package ConcaveHull
+
+import "context"
+
+// Creates spans around the phases of a computation, see Options.Tracer. Spans are children of the span in the context
+// given to the Context functions. With go.opentelemetry.io/otel an adapter is:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//	type otelSpan struct{ trace.Span }
+//	func (t otelTracer) Start (ctx context.Context, name string) (context.Context, ConcaveHull.Span) {
+//		ctx, span := t.tracer.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//	func (s otelSpan) End () { s.Span.End() }
+//
+// The convex hull and the index are built concurrently, so their spans may overlap unless Options.SingleThreaded is set
+type Tracer interface {
+	Start (ctx context.Context, name string) (context.Context, Span)
+}
+
+type Span interface {
+	End ()
+}
+
+// Names of the spans
+const (
+	SPAN_SORT = "ConcaveHull.sort"
+	SPAN_INDEX = "ConcaveHull.index"
+	SPAN_CONVEX_HULL = "ConcaveHull.convexHull"
+	SPAN_SEGMENTIZE = "ConcaveHull.segmentize"
+	SPAN_SIMPLIFY = "ConcaveHull.simplify"
+)
+
+type noopSpan struct{}
+
+func (noopSpan) End () {}
+
+// Start a span named name if Options.Tracer is set. The legacy entry points have no context, their spans are roots
+func startSpan (ctx context.Context, o *Options, name string) Span {
+	if o == nil || o.Tracer == nil {
+		return noopSpan{}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, span := o.Tracer.Start(ctx, name)
+	return span
+}
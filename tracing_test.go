@@ -0,0 +1,61 @@
+package ConcaveHull
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+type parentKey struct{}
+
+type recordingTracer struct {
+	mu sync.Mutex
+	started []string
+	ended []string
+	parents []interface{}
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+	name string
+}
+
+func (t *recordingTracer) Start (ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.started = append(t.started, name)
+	t.parents = append(t.parents, ctx.Value(parentKey{}))
+	return ctx, recordingSpan{tracer: t, name: name}
+}
+
+func (s recordingSpan) End () {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.ended = append(s.tracer.ended, s.name)
+}
+
+func TestTracer_phases (t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	points := hulltest.Random(r, 300)
+	tracer := &recordingTracer{}
+	ctx := context.WithValue(context.Background(), parentKey{}, "request")
+	_, err := ComputeContext(ctx, FlatPoints(points), &Options{Seglength: 0.05, Tracer: tracer, SingleThreaded: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{SPAN_SORT, SPAN_CONVEX_HULL, SPAN_INDEX, SPAN_SEGMENTIZE, SPAN_SIMPLIFY}, tracer.started)
+	assert.Equal(t, tracer.started, tracer.ended)
+	for _, parent := range(tracer.parents) {
+		assert.Equal(t, "request", parent)
+	}
+}
+
+func TestTracer_legacy (t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	points := hulltest.Random(r, 100)
+	tracer := &recordingTracer{}
+	ComputeWithOptions(FlatPoints(points), &Options{Seglength: 0.05, Tracer: tracer})
+	assert.Equal(t, 5, len(tracer.started))
+	assert.Equal(t, 5, len(tracer.ended))
+}
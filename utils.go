@@ -1,4 +1,19 @@
 package ConcaveHull
 
+import (
+	"math"
+	"github.com/USACE/concavehull/geomutil"
+)
 
 type convexHullFlatPoints FlatPoints
+
+// Length of the diagonal of the bounding box of the points
+func bboxDiagonal (points FlatPoints) float64 {
+	minX, minY, maxX, maxY := bbox(points)
+	return math.Hypot(maxX - minX, maxY - minY)
+}
+
+// Bounding box of the points, all zero if there are none
+func bbox (points FlatPoints) (minX, minY, maxX, maxY float64) {
+	return geomutil.BBox(points)
+}
@@ -0,0 +1,156 @@
+package ConcaveHull
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// OGC Simple Features rule broken by a ring, see ValidityReport
+type ViolationKind uint8
+
+const (
+	ViolationUnclosed ViolationKind = iota // the last point is not the first one
+	ViolationTooFewPoints // fewer than four points, the last repeating the first
+	ViolationRepeatedPoint // consecutive equal points
+	ViolationSelfIntersection // two edges that are not consecutive touch or cross
+	ViolationOrientation // the exterior ring is clockwise, counter clockwise is expected
+)
+
+func (k ViolationKind) String () string {
+	switch k {
+	case ViolationUnclosed:
+		return "unclosed ring"
+	case ViolationTooFewPoints:
+		return "too few points"
+	case ViolationRepeatedPoint:
+		return "repeated point"
+	case ViolationSelfIntersection:
+		return "self intersection"
+	case ViolationOrientation:
+		return "orientation"
+	}
+	return "unknown"
+}
+
+// A broken rule and where, the vertex being an index of the ring as given
+type Violation struct {
+	Kind ViolationKind
+	Vertex int
+	X, Y float64
+	Detail string
+}
+
+func (v Violation) String () string {
+	return fmt.Sprintf("%s at vertex %d (%v, %v): %s", v.Kind, v.Vertex, v.X, v.Y, v.Detail)
+}
+
+// Violations of the OGC Simple Features rules by a hull taken as the exterior ring of a polygon, ordered by kind and then
+// vertex, for QA dashboards. A valid ring has none. Self intersections are reported once per pair of edges, at the first
+// point in common
+func ValidityReport (ring FlatPoints) []Violation {
+	var violations []Violation
+	n := ring.Len()
+	if n > 0 {
+		if x0, y0 := ring.Take(0); ring[2 * n - 2] != x0 || ring[2 * n - 1] != y0 {
+			violations = append(violations, Violation{ViolationUnclosed, n - 1, ring[2 * n - 2], ring[2 * n - 1],
+				fmt.Sprintf("ends away from (%v, %v)", x0, y0)})
+		}
+	}
+	open := openRing(ring)
+	m := open.Len()
+	if m < 3 {
+		x, y := 0., 0.
+		if n > 0 {
+			x, y = ring.Take(0)
+		}
+		return append(violations, Violation{ViolationTooFewPoints, 0, x, y, fmt.Sprintf("%d points", n)})
+	}
+	for i := 0; i < m; i++ {
+		x1, y1 := open.Take(i)
+		if x2, y2 := open.Take((i + 1) % m); x1 == x2 && y1 == y2 {
+			violations = append(violations, Violation{ViolationRepeatedPoint, (i + 1) % m, x2, y2,
+				fmt.Sprintf("same as vertex %d", i)})
+		}
+	}
+	// repeated points are reported above, the edges between them are left out so the edges around them stay consecutive
+	distinct, vertices := FlatPoints{}, []int{}
+	for i := 0; i < m; i++ {
+		x, y := open.Take(i)
+		if k := distinct.Len(); k == 0 || distinct[2 * k - 2] != x || distinct[2 * k - 1] != y {
+			distinct, vertices = append(distinct, x, y), append(vertices, i)
+		}
+	}
+	if k := distinct.Len(); k > 1 && distinct[0] == distinct[2 * k - 2] && distinct[1] == distinct[2 * k - 1] {
+		distinct, vertices = distinct[:2 * k - 2], vertices[:k - 1]
+	}
+	violations = append(violations, selfIntersections(distinct, vertices)...)
+	if area := SignedArea(open); area < 0 {
+		x, y := open.Take(0)
+		violations = append(violations, Violation{ViolationOrientation, 0, x, y, fmt.Sprintf("signed area %v", area)})
+	}
+	return violations
+}
+
+// Pairs of edges of an open ring without repeated points that touch without being consecutive, vertices mapping the
+// vertices of the ring to those reported
+func selfIntersections (ring FlatPoints, vertices []int) []Violation {
+	var violations []Violation
+	m := ring.Len()
+	if m < 3 {
+		return nil
+	}
+	e := newEdgeIndex(ring)
+	for i := 0; i < m; i++ {
+		x1, y1 := ring.Take(i)
+		x2, y2 := ring.Take((i + 1) % m)
+		ax, ay := e.cell(math.Min(x1, x2), math.Min(y1, y2))
+		bx, by := e.cell(math.Max(x1, x2), math.Max(y1, y2))
+		// an edge in several cells is found once per cell
+		seen := make(map[int]bool)
+		var touching []int
+		for cy := ay; cy <= by; cy++ {
+			for cx := ax; cx <= bx; cx++ {
+				c := cy * e.nx + cx
+				for _, k := range(e.edges[e.start[c]:e.start[c + 1]]) {
+					if k <= i + 1 || i == 0 && k == m - 1 || seen[k] {
+						continue
+					}
+					seen[k] = true
+					x3, y3 := ring.Take(k)
+					x4, y4 := ring.Take((k + 1) % m)
+					if segmentsTouch(x1, y1, x2, y2, x3, y3, x4, y4) {
+						touching = append(touching, k)
+					}
+				}
+			}
+		}
+		sort.Ints(touching)
+		for _, k := range(touching) {
+			x3, y3 := ring.Take(k)
+			x4, y4 := ring.Take((k + 1) % m)
+			px, py := segmentsMeet(x1, y1, x2, y2, x3, y3, x4, y4)
+			violations = append(violations, Violation{ViolationSelfIntersection, vertices[i], px, py,
+				fmt.Sprintf("edge %d meets edge %d", vertices[i], vertices[k])})
+		}
+	}
+	return violations
+}
+
+// A point in common of two segments that touch: the crossing point, or an end point of one lying on the other
+func segmentsMeet (ax, ay, bx, by, cx, cy, dx, dy float64) (float64, float64) {
+	d1 := orientation(cx, cy, dx, dy, ax, ay)
+	d2 := orientation(cx, cy, dx, dy, bx, by)
+	switch {
+	case d1 == 0 && segmentsTouch(ax, ay, ax, ay, cx, cy, dx, dy):
+		return ax, ay
+	case d2 == 0 && segmentsTouch(bx, by, bx, by, cx, cy, dx, dy):
+		return bx, by
+	case orientation(ax, ay, bx, by, cx, cy) == 0 && segmentsTouch(cx, cy, cx, cy, ax, ay, bx, by):
+		return cx, cy
+	case orientation(ax, ay, bx, by, dx, dy) == 0 && segmentsTouch(dx, dy, dx, dy, ax, ay, bx, by):
+		return dx, dy
+	}
+	t := d1 / (d1 - d2)
+	return ax + (bx - ax) * t, ay + (by - ay) * t
+}
@@ -0,0 +1,36 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidityReport (t *testing.T) {
+	assert.Len(t, ValidityReport(FlatPoints{0, 0, 2, 0, 2, 2, 0, 2, 0, 0}), 0)
+
+	// L shape, with collinear vertices
+	assert.Len(t, ValidityReport(FlatPoints{0, 0, 1, 0, 2, 0, 2, 1, 1, 1, 1, 2, 0, 2, 0, 0}), 0)
+
+	// notch touching the bottom edge at (1, 0)
+	violations := ValidityReport(FlatPoints{0, 0, 2, 0, 2, 2, 1, 0, 0, 2, 0, 0})
+	assert.Equal(t, []Violation{{ViolationSelfIntersection, 0, 1, 0, "edge 0 meets edge 2"},
+		{ViolationSelfIntersection, 0, 1, 0, "edge 0 meets edge 3"}}, violations)
+
+	// bow tie, clockwise half first, with a repeated point and left open
+	bowTie := FlatPoints{0, 0, 2, 2, 2, 2, 2, 0, 0, 2}
+	violations = ValidityReport(bowTie)
+	kinds := make([]ViolationKind, len(violations))
+	for i, v := range(violations) {
+		kinds[i] = v.Kind
+	}
+	assert.Equal(t, []ViolationKind{ViolationUnclosed, ViolationRepeatedPoint, ViolationSelfIntersection}, kinds)
+	assert.Equal(t, 2, violations[1].Vertex)
+	assert.Equal(t, Violation{ViolationSelfIntersection, 0, 1, 1, "edge 0 meets edge 3"}, violations[2])
+
+	violations = ValidityReport(FlatPoints{0, 0, 0, 2, 2, 2, 2, 0, 0, 0})
+	assert.Len(t, violations, 1)
+	assert.Equal(t, ViolationOrientation, violations[0].Kind)
+
+	violations = ValidityReport(FlatPoints{0, 0, 1, 1, 0, 0})
+	assert.Equal(t, ViolationTooFewPoints, violations[0].Kind)
+}
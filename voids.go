@@ -0,0 +1,107 @@
+package ConcaveHull
+
+import (
+	"math"
+	"github.com/USACE/concavehull/geomutil"
+)
+
+// Large point free regions inside the hull, e.g. gaps in a survey. The hull is covered with a grid of the given cell size,
+// cells without points whose center is inside the hull are grouped in connected regions, and the outlines of the regions
+// with an area of at least minArea are returned as closed counter clockwise rings. Points inside a void that are
+// too sparse to split it do not produce holes in the outline
+func Voids (points, hull FlatPoints, cellSize, minArea float64) []FlatPoints {
+	if cellSize <= 0 || openRing(hull).Len() < 3 {
+		return nil
+	}
+	minX, minY, maxX, maxY := bbox(hull)
+	nx := int(math.Ceil((maxX - minX) / cellSize))
+	ny := int(math.Ceil((maxY - minY) / cellSize))
+	if nx == 0 || ny == 0 {
+		return nil
+	}
+	occupied := make([]bool, nx * ny)
+	for i := 0; i < points.Len(); i++ {
+		x, y := points.Take(i)
+		cx, cy := int((x - minX) / cellSize), int((y - minY) / cellSize)
+		if cx >= 0 && cx < nx && cy >= 0 && cy < ny {
+			occupied[cy * nx + cx] = true
+		}
+	}
+	empty := make([]bool, nx * ny)
+	for cy := 0; cy < ny; cy++ {
+		for cx := 0; cx < nx; cx++ {
+			if !occupied[cy * nx + cx] {
+				empty[cy * nx + cx] = ringContains(hull, minX + (float64(cx) + 0.5) * cellSize, minY + (float64(cy) + 0.5) * cellSize)
+			}
+		}
+	}
+	var voids []FlatPoints
+	visited := make([]bool, nx * ny)
+	for start := range(empty) {
+		if !empty[start] || visited[start] {
+			continue
+		}
+		// flood fill with 4 connectivity
+		component := []int{start}
+		visited[start] = true
+		for k := 0; k < len(component); k++ {
+			cx, cy := component[k] % nx, component[k] / nx
+			for _, d := range([4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}) {
+				x, y := cx + d[0], cy + d[1]
+				if x < 0 || x >= nx || y < 0 || y >= ny {
+					continue
+				}
+				if i := y * nx + x; empty[i] && !visited[i] {
+					visited[i] = true
+					component = append(component, i)
+				}
+			}
+		}
+		if float64(len(component)) * cellSize * cellSize < minArea {
+			continue
+		}
+		for _, ring := range(cellsOutline(component, nx, minX, minY, cellSize)) {
+			if IsCCW(ring) {
+				voids = append(voids, ring)
+			}
+		}
+	}
+	return voids
+}
+
+// Rings enclosing a set of grid cells. Every cell contributes its counter clockwise edges and edges shared by two cells cancel out
+func cellsOutline (cells []int, nx int, minX, minY, cellSize float64) []FlatPoints {
+	inSet := make(map[int]bool, len(cells))
+	for _, c := range(cells) {
+		inSet[c] = true
+	}
+	var vertices [][2]float64
+	vertexIds := make(map[[2]int]int)
+	vertexId := func (gx, gy int) int {
+		key := [2]int{gx, gy}
+		if id, ok := vertexIds[key]; ok {
+			return id
+		}
+		vertexIds[key] = len(vertices)
+		vertices = append(vertices, [2]float64{minX + float64(gx) * cellSize, minY + float64(gy) * cellSize})
+		return len(vertices) - 1
+	}
+	var edges []geomutil.Edge
+	for _, c := range(cells) {
+		cx, cy := c % nx, c / nx
+		// bottom, right, top and left sides, only where the neighbour is not in the set
+		if cy == 0 || !inSet[c - nx] {
+			edges = append(edges, geomutil.Edge{From: vertexId(cx, cy), To: vertexId(cx + 1, cy)})
+		}
+		if cx == nx - 1 || !inSet[c + 1] {
+			edges = append(edges, geomutil.Edge{From: vertexId(cx + 1, cy), To: vertexId(cx + 1, cy + 1)})
+		}
+		if !inSet[c + nx] {
+			edges = append(edges, geomutil.Edge{From: vertexId(cx + 1, cy + 1), To: vertexId(cx, cy + 1)})
+		}
+		if cx == 0 || !inSet[c - 1] {
+			edges = append(edges, geomutil.Edge{From: vertexId(cx, cy + 1), To: vertexId(cx, cy)})
+		}
+	}
+	return chainEdges(vertices, edges)
+}
@@ -0,0 +1,23 @@
+package ConcaveHull
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoids (t *testing.T) {
+	// 10x10 grid of points with a 4x3 gap
+	var points FlatPoints
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			if x >= 3 && x < 7 && y >= 4 && y < 7 {
+				continue
+			}
+			points = append(points, float64(x) + 0.5, float64(y) + 0.5)
+		}
+	}
+	hull := FlatPoints{0, 0, 10, 0, 10, 10, 0, 10, 0, 0}
+	voids := Voids(points, hull, 1, 5)
+	assert.Equal(t, []FlatPoints{{3, 4, 7, 4, 7, 7, 3, 7, 3, 4}}, voids)
+	assert.Len(t, Voids(points, hull, 1, 13), 0)
+}
@@ -0,0 +1,51 @@
+package ConcaveHull
+
+import "math"
+
+// Radius of the sphere of Web Mercator (EPSG:3857), the equatorial radius of WGS84
+const webMercatorRadius = 6378137.
+
+// Web Mercator (EPSG:3857) position in meters of a longitude, latitude point in degrees. Latitudes are clamped to
+// ±85.05112878 degrees, beyond which the projection diverges, so that points at the poles map to the edge of the square world
+func LonLatToWebMercator (lon, lat float64) (x, y float64) {
+	lat = math.Max(-maxMercatorLatitude, math.Min(maxMercatorLatitude, lat))
+	x = lon * math.Pi / 180 * webMercatorRadius
+	y = math.Log(math.Tan(math.Pi / 4 + lat * math.Pi / 360)) * webMercatorRadius
+	return x, y
+}
+
+// Longitude, latitude in degrees of a Web Mercator position in meters
+func WebMercatorToLonLat (x, y float64) (lon, lat float64) {
+	lon = x / webMercatorRadius * 180 / math.Pi
+	lat = (2 * math.Atan(math.Exp(y / webMercatorRadius)) - math.Pi / 2) * 180 / math.Pi
+	return lon, lat
+}
+
+// Copy of longitude, latitude points projected to Web Mercator
+func ToWebMercator (points FlatPoints) FlatPoints {
+	projected := make(FlatPoints, len(points))
+	for i := 0; i < len(points); i += 2 {
+		projected[i], projected[i + 1] = LonLatToWebMercator(points[i], points[i + 1])
+	}
+	return projected
+}
+
+// Copy of Web Mercator points as longitude, latitude
+func FromWebMercator (points FlatPoints) FlatPoints {
+	lonLat := make(FlatPoints, len(points))
+	for i := 0; i < len(points); i += 2 {
+		lonLat[i], lonLat[i + 1] = WebMercatorToLonLat(points[i], points[i + 1])
+	}
+	return lonLat
+}
+
+// Options.Projection that computes hulls of longitude, latitude points in Web Mercator, so Seglength is in meters at the equator
+type WebMercator struct{}
+
+func (WebMercator) Forward (lon, lat float64) (float64, float64) {
+	return LonLatToWebMercator(lon, lat)
+}
+
+func (WebMercator) Inverse (x, y float64) (float64, float64) {
+	return WebMercatorToLonLat(x, y)
+}
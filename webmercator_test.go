@@ -0,0 +1,34 @@
+package ConcaveHull
+
+import (
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/USACE/concavehull/hulltest"
+)
+
+func TestLonLatToWebMercator (t *testing.T) {
+	x, y := LonLatToWebMercator(180, maxMercatorLatitude)
+	assert.InDelta(t, 20037508.342789244, x, 1e-6)
+	assert.InDelta(t, 20037508.342789244, y, 1e-2)
+	// poles are clamped
+	_, pole := LonLatToWebMercator(0, 90)
+	assert.Equal(t, y, pole)
+	lon, lat := WebMercatorToLonLat(LonLatToWebMercator(-3.7, 40.4))
+	assert.InDelta(t, -3.7, lon, 1e-12)
+	assert.InDelta(t, 40.4, lat, 1e-12)
+	assert.Equal(t, FlatPoints{0, 0}, ToWebMercator(FlatPoints{0, 0}))
+	back := FromWebMercator(ToWebMercator(FlatPoints{10, 20, -30, -40}))
+	assert.InDelta(t, -40, back[3], 1e-12)
+}
+
+func TestComputeWithOptions_webMercator (t *testing.T) {
+	r := rand.New(rand.NewSource(21))
+	points := hulltest.Clustered(r, 500, 3, 0.05)
+	for i := 0; i < len(points); i += 2 {
+		points[i], points[i + 1] = points[i] - 3.7, points[i + 1] + 40
+	}
+	input := append([]float64{}, points...)
+	hull := ComputeWithOptions(FlatPoints(points), &Options{Seglength: 2000, Projection: WebMercator{}})
+	hulltest.AssertValid(t, input, hull)
+}